@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -13,6 +14,10 @@ import (
 
 var log = logrus.New()
 
+// a quote that expires sooner than this can not be accepted, as lacd does
+// not open contracts that are about to expire
+const quoteExpiryMargin = 30 * time.Second
+
 var openContractCommand = cli.Command{
 	Name:     "opencontract",
 	Category: "Contracts",
@@ -20,42 +25,63 @@ var openContractCommand = cli.Command{
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "asset",
-			Usage: "which asset to denominate the contract in, USD or NOK",
+			Usage: "which asset to denominate the contract in, see listassets",
 			Value: "USD",
 		},
-		cli.IntFlag{
+		cli.StringFlag{
 			Name:  "amount",
-			Usage: "the amount denominated in `asset`",
+			Usage: "the amount denominated in `asset`, ie 0.10, with at most the decimals of the asset",
 		},
 		cli.StringFlag{
 			Name:  "type",
 			Usage: "the contract type as a string, either FUNDED or UNFUNDED",
 			Value: "UNFUNDED",
 		},
+		cli.IntFlag{
+			Name:  "maxfeesat",
+			Usage: "the highest routing fee, in sats, to pay for each invoice. Defaults to the limit of the daemon",
+		},
+		cli.Float64Flag{
+			Name:  "maxfeepercent",
+			Usage: "the highest routing fee, in percent of the amount, to pay for each invoice. Defaults to the limit of the daemon",
+		},
+		cli.IntFlag{
+			Name:  "paymenttimeout",
+			Usage: "how many seconds a single attempt to pay an invoice may take",
+		},
+		cli.IntFlag{
+			Name:  "outgoingchanid",
+			Usage: "the id of the channel to pay through",
+		},
+		cli.BoolFlag{
+			Name:  "pinserverchannel",
+			Usage: "pay through one of our channels with the asset server",
+		},
+		cli.IntFlag{
+			Name:  "paymentattempts",
+			Usage: "how many times to try paying each invoice before giving up",
+		},
 	},
 	Action: openContract,
 }
 
 func openContract(ctx *cli.Context) error {
 	// connect to our local lad daemon
-	client, cleanup := connectToDaemon(ctx.GlobalInt(flag_rpcport))
+	client, cleanup := connectToDaemon(ctx)
 	defer cleanup()
 
 	asset := ctx.String("asset")
-	amount := ctx.Float64("amount")
+	amount := ctx.String("amount")
 	cType, ok := larpc.ContractType_value[ctx.String("type")]
 	if !ok {
 		return fmt.Errorf("contract type %q not supported", ctx.String("type"))
 	}
 
-	// create a contract at the server. This is not open before we have paid
-	// the invoices related to the contract, and won't start rebalancing until they are paid
-	createRes, err := client.CreateContract(context.Background(),
-		&larpc.ClientCreateContractRequest{
-			Asset:        asset,
-			Amount:       amount,
-			ContractType: larpc.ContractType(cType),
-		})
+	createRes, err := createAcceptedContract(client, &larpc.ClientCreateContractRequest{
+		Asset:         asset,
+		AmountDecimal: amount,
+		ContractType:  larpc.ContractType(cType),
+	})
 	if err != nil {
 		log.WithFields(logrus.Fields{
 			"Asset":        asset,
@@ -65,20 +91,20 @@ func openContract(ctx *cli.Context) error {
 		return err
 	}
 
-	if err = displayQuote(
-		createRes.Contract.AmountSatMargin,
-		createRes.ServerPrice,
-		createRes.PercentMargin,
-		createRes.OurPrice); err != nil {
-		return fmt.Errorf("user did not accept terms: %w", err)
-	}
-
 	log.WithField("uuid", createRes.Contract.Uuid).Info("user accepted terms")
 
 	// Open the contract by paying the invoices, the client daemon
 	// makes sure the amounts are correct
 	openResponse, err := client.OpenContract(context.Background(), &larpc.ClientOpenContractRequest{
 		Uuid: createRes.Contract.Uuid,
+		PaymentOptions: &larpc.PaymentOptions{
+			MaxFeeSat:      int64(ctx.Int("maxfeesat")),
+			MaxFeePercent:  ctx.Float64("maxfeepercent"),
+			TimeoutSeconds: int64(ctx.Int("paymenttimeout")),
+			OutgoingChanId: uint64(ctx.Int("outgoingchanid")),
+			PinToServer:    ctx.Bool("pinserverchannel"),
+			MaxAttempts:    int32(ctx.Int("paymentattempts")),
+		},
 	})
 	if err != nil {
 		log.WithError(err).Error("could not open contract")
@@ -104,7 +130,7 @@ var closeContractCommand = cli.Command{
 }
 
 func closeContract(ctx *cli.Context) error {
-	conn, cleanup := connectToDaemon(ctx.GlobalInt(flag_rpcport))
+	conn, cleanup := connectToDaemon(ctx)
 	defer cleanup()
 
 	uuid := ctx.String("uuid")
@@ -128,14 +154,22 @@ var listContractsCommand = cli.Command{
 	Name:     "listcontracts",
 	Category: "Contracts",
 	Usage:    "list all open contracts",
-	Action:   listContracts,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "also list closed, failed and expired contracts",
+		},
+	},
+	Action: listContracts,
 }
 
 func listContracts(ctx *cli.Context) error {
-	conn, cleanup := connectToDaemon(ctx.GlobalInt(flag_rpcport))
+	conn, cleanup := connectToDaemon(ctx)
 	defer cleanup()
 
-	contract, err := conn.ListContracts(context.Background(), &larpc.ClientListContractsRequest{})
+	contract, err := conn.ListContracts(context.Background(), &larpc.ClientListContractsRequest{
+		IncludeInactive: ctx.Bool("all"),
+	})
 	if err != nil {
 		log.WithError(err).Error("could not list contract")
 		return err
@@ -146,10 +180,58 @@ func listContracts(ctx *cli.Context) error {
 	return nil
 }
 
-func displayQuote(amountSats int64, assetPrice, percentMargin, ourPrice float64) error {
-	fmt.Printf("Initiating contract for requires %.2f percent margin, which equals %d sats\n"+
-		"Server used a price of %.2f, we have a price of %.2f\n",
-		percentMargin, amountSats, assetPrice, ourPrice)
+// createAcceptedContract creates a contract at the server, and asks the user
+// to accept its quote. The contract is not open before we have paid its
+// invoices, and won't start rebalancing until they are paid. If the quote
+// expires while the user is deciding, a fresh quote is fetched.
+func createAcceptedContract(client larpc.AssetClientClient,
+	req *larpc.ClientCreateContractRequest) (*larpc.ClientCreateContractResponse, error) {
+
+	for {
+		createRes, err := client.CreateContract(context.Background(), req)
+		if err != nil {
+			return nil, err
+		}
+
+		// lacd rejects quotes that do not match our price or policies,
+		// there is no point in asking the user about those
+		if v := createRes.QuoteValidation; v != nil && !v.Accepted {
+			for _, reason := range v.Reasons {
+				fmt.Println("quote rejected:", reason)
+			}
+			return nil, errors.New("server quote was rejected")
+		}
+
+		if err = displayQuote(createRes); err != nil {
+			return nil, fmt.Errorf("user did not accept terms: %w", err)
+		}
+
+		// lacd refuses to open contracts this close to their expiry
+		expiresAt := time.Unix(createRes.Contract.ExpiresAt, 0)
+		if createRes.Contract.ExpiresAt == 0 ||
+			time.Until(expiresAt) > quoteExpiryMargin {
+			return createRes, nil
+		}
+
+		fmt.Println("The quote expired, getting a fresh quote")
+	}
+}
+
+func displayQuote(quote *larpc.ClientCreateContractResponse) error {
+	fmt.Printf("Initiating contract for %s %s requires %s percent margin, which equals %d sats\n"+
+		"Server used a price of %s, we have a price of %s\n",
+		quote.Contract.AmountDecimal, quote.Contract.Asset, quote.PercentMarginDecimal,
+		quote.Contract.AmountSatMargin, quote.ServerPriceDecimal, quote.OurPriceDecimal)
+
+	if v := quote.QuoteValidation; v != nil {
+		fmt.Printf("The prices differ %.2f percent, we accept up to %.2f percent\n",
+			v.PriceDeviationPercent, v.MaxPriceDeviationPercent)
+	}
+
+	if quote.Contract.ExpiresAt != 0 {
+		fmt.Printf("The quote is valid until %s\n",
+			time.Unix(quote.Contract.ExpiresAt, 0).Format(time.RFC3339))
+	}
 
 	fmt.Printf("CONTINUE OPENING CONTRACT? (y/n)")
 
@@ -163,3 +245,207 @@ func displayQuote(amountSats int64, assetPrice, percentMargin, ourPrice float64)
 
 	return errors.New("opening contract canceled")
 }
+
+var getConfigCommand = cli.Command{
+	Name:     "getconfig",
+	Category: "Daemon",
+	Usage:    "show the effective configuration of lacd, and where each value came from",
+	Action:   getConfig,
+}
+
+func getConfig(ctx *cli.Context) error {
+	conn, cleanup := connectToDaemon(ctx)
+	defer cleanup()
+
+	res, err := conn.GetConfig(context.Background(), &larpc.ClientGetConfigRequest{})
+	if err != nil {
+		log.WithError(err).Error("could not get config")
+		return err
+	}
+
+	fmt.Printf("config file: %s\n", res.ConfigFile)
+	for _, value := range res.Values {
+		fmt.Printf("%-22s = %-40s (%s)\n", value.Name, value.Value, value.Source)
+	}
+
+	return nil
+}
+
+var getStatusCommand = cli.Command{
+	Name:     "getstatus",
+	Category: "Daemon",
+	Usage:    "show the state of the connections of lacd",
+	Action:   getStatus,
+}
+
+func getStatus(ctx *cli.Context) error {
+	conn, cleanup := connectToDaemon(ctx)
+	defer cleanup()
+
+	res, err := conn.GetStatus(context.Background(), &larpc.ClientGetStatusRequest{})
+	if err != nil {
+		log.WithError(err).Error("could not get status")
+		return err
+	}
+
+	printConnectionStatus("server", res.Server)
+	printConnectionStatus("lnd", res.Lnd)
+
+	return nil
+}
+
+func printConnectionStatus(name string, s *larpc.ConnectionStatus) {
+	fmt.Printf("%s (%s): %s since %s\n", name, s.Address, s.State,
+		time.Unix(s.Since, 0).Format(time.RFC3339))
+	if s.LastConnected != 0 {
+		fmt.Printf("  last connected: %s\n",
+			time.Unix(s.LastConnected, 0).Format(time.RFC3339))
+	}
+	if s.LastError != "" {
+		fmt.Printf("  last error:     %s\n", s.LastError)
+	}
+}
+
+var getInfoCommand = cli.Command{
+	Name:     "getinfo",
+	Category: "Daemon",
+	Usage:    "show what lacd is connected to, its contracts and the latest prices",
+	Action:   getInfo,
+}
+
+func getInfo(ctx *cli.Context) error {
+	conn, cleanup := connectToDaemon(ctx)
+	defer cleanup()
+
+	res, err := conn.GetInfo(context.Background(), &larpc.ClientGetInfoRequest{})
+	if err != nil {
+		log.WithError(err).Error("could not get info")
+		return err
+	}
+
+	fmt.Printf("version:         %s\n", res.Version)
+	fmt.Printf("network:         %s\n", res.Network)
+	fmt.Printf("asset server:    %s\n", res.ServerAddress)
+	if res.LndPubkey != "" {
+		fmt.Printf("lnd:             %s (%s), version %s\n", res.LndAlias,
+			res.LndPubkey, res.LndVersion)
+	} else {
+		fmt.Printf("lnd:             not reached yet\n")
+	}
+	fmt.Printf("open contracts:  %d, worth %d sats\n", res.NumOpenContracts,
+		res.TotalContractSat)
+	fmt.Printf("margin:          %d sats\n", res.TotalMarginSat)
+
+	for _, price := range res.Prices {
+		stale := ""
+		if price.Stale {
+			stale = ", stale"
+		}
+		fmt.Printf("price %-10s %s (%s old%s)\n", price.Asset+":", price.ValueDecimal,
+			time.Duration(price.AgeSeconds)*time.Second, stale)
+	}
+
+	return nil
+}
+
+var listAssetsCommand = cli.Command{
+	Name:     "listassets",
+	Category: "Contracts",
+	Usage:    "list the assets contracts can be opened in",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "refresh",
+			Usage: "fetch the supported assets from the server, even if lacd has fetched them recently",
+		},
+	},
+	Action: listAssets,
+}
+
+func listAssets(ctx *cli.Context) error {
+	conn, cleanup := connectToDaemon(ctx)
+	defer cleanup()
+
+	res, err := conn.ListAssets(context.Background(), &larpc.ClientListAssetsRequest{
+		Refresh: ctx.Bool("refresh"),
+	})
+	if err != nil {
+		log.WithError(err).Error("could not list assets")
+		return err
+	}
+
+	for _, asset := range res.Assets {
+		fmt.Println(asset)
+	}
+	for _, asset := range res.UnpricedAssets {
+		fmt.Printf("%s (no price available)\n", asset)
+	}
+
+	return nil
+}
+
+var getServerIdentityCommand = cli.Command{
+	Name:     "getserveridentity",
+	Category: "Daemon",
+	Usage:    "show the lightning node of the asset server lacd pays invoices to",
+	Action:   getServerIdentity,
+}
+
+func getServerIdentity(ctx *cli.Context) error {
+	conn, cleanup := connectToDaemon(ctx)
+	defer cleanup()
+
+	res, err := conn.GetServerIdentity(context.Background(),
+		&larpc.ClientGetServerIdentityRequest{})
+	if err != nil {
+		log.WithError(err).Error("could not get server identity")
+		return err
+	}
+
+	if res.Identity == nil {
+		fmt.Println("no server node pinned yet, the node of the first invoice from the server is pinned")
+		return nil
+	}
+
+	printServerIdentity(res.Identity)
+
+	return nil
+}
+
+var resetServerIdentityCommand = cli.Command{
+	Name:     "resetserveridentity",
+	Category: "Daemon",
+	Usage:    "forget the pinned node of the asset server, the node of the next invoice from the server is pinned instead",
+	Action:   resetServerIdentity,
+}
+
+func resetServerIdentity(ctx *cli.Context) error {
+	conn, cleanup := connectToDaemon(ctx)
+	defer cleanup()
+
+	res, err := conn.ResetServerIdentity(context.Background(),
+		&larpc.ClientResetServerIdentityRequest{})
+	if err != nil {
+		log.WithError(err).Error("could not reset server identity")
+		return err
+	}
+
+	if res.Previous == nil {
+		fmt.Println("no server node was pinned")
+		return nil
+	}
+
+	fmt.Println("forgot server node:")
+	printServerIdentity(res.Previous)
+
+	return nil
+}
+
+func printServerIdentity(identity *larpc.ServerIdentity) {
+	fmt.Printf("pubkey:         %s\n", identity.Pubkey)
+	fmt.Printf("source:         %s\n", identity.Source)
+	fmt.Printf("server address: %s\n", identity.ServerAddress)
+	if identity.PinnedAt != 0 {
+		fmt.Printf("pinned at:      %s\n",
+			time.Unix(identity.PinnedAt, 0).Format(time.RFC3339))
+	}
+}
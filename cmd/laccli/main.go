@@ -2,21 +2,36 @@ package main
 
 import (
 	"fmt"
-	"github.com/ArcaneCryptoAS/lassets-client/build"
-	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"os"
+	"path"
+
+	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/urfave/cli"
 	"google.golang.org/grpc"
-	"os"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ArcaneCryptoAS/lassets-client/build"
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/util"
 )
 
 // default value for flags
 const (
 	defaultRPCPort = 10456
+
+	defaultTLSCertFilename       = "tls.cert"
+	defaultAdminMacaroonFilename = "admin.macaroon"
 )
 
+var defaultLadDir = util.CleanAndExpandPath("~/.lac")
+
 // all flags for laccli command
 const (
-	flag_rpcport = "rpcport"
+	flag_rpcport      = "rpcport"
+	flag_laddir       = "laddir"
+	flag_tlscertpath  = "tlscertpath"
+	flag_macaroonpath = "macaroonpath"
+	flag_nomacaroons  = "nomacaroons"
 )
 
 func main() {
@@ -30,11 +45,34 @@ func main() {
 			Value: defaultRPCPort,
 			Usage: "port to listen for grpc connections on",
 		},
+		cli.StringFlag{
+			Name:  flag_laddir,
+			Value: defaultLadDir,
+			Usage: "path to lacd's base directory, where the TLS certificate and macaroons are found",
+		},
+		cli.StringFlag{
+			Name:  flag_tlscertpath,
+			Usage: "path to lacd's TLS certificate, defaults to tls.cert in laddir",
+		},
+		cli.StringFlag{
+			Name:  flag_macaroonpath,
+			Usage: "path to the macaroon to use, defaults to admin.macaroon in laddir",
+		},
+		cli.BoolFlag{
+			Name:  flag_nomacaroons,
+			Usage: "disable macaroon authentication",
+		},
 	}
 	app.Commands = []cli.Command{
 		openContractCommand,
 		closeContractCommand,
 		listContractsCommand,
+		listAssetsCommand,
+		getConfigCommand,
+		getStatusCommand,
+		getInfoCommand,
+		getServerIdentityCommand,
+		resetServerIdentityCommand,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -43,19 +81,45 @@ func main() {
 }
 
 // connectToDaemon opens a connection to the lightning assets client daemon
-func connectToDaemon(rpcPort int) (larpc.AssetClientClient, func()) {
+func connectToDaemon(ctx *cli.Context) (larpc.AssetClientClient, func()) {
+	ladDir := util.CleanAndExpandPath(ctx.GlobalString(flag_laddir))
+
+	tlsCertPath := util.CleanAndExpandPath(ctx.GlobalString(flag_tlscertpath))
+	if tlsCertPath == "" {
+		tlsCertPath = path.Join(ladDir, defaultTLSCertFilename)
+	}
+
 	// Load the specified TLS certificate and build transport credentials
 	// with it.
-	// TODO: Add tls here..
+	creds, err := credentials.NewClientTLSFromFile(tlsCertPath, "")
+	if err != nil {
+		log.Fatalf("unable to load TLS certificate: %v", err)
+	}
+
 	opts := []grpc.DialOption{
-		grpc.WithInsecure(),
+		grpc.WithTransportCredentials(creds),
+	}
+
+	if !ctx.GlobalBool(flag_nomacaroons) {
+		macaroonPath := util.CleanAndExpandPath(ctx.GlobalString(flag_macaroonpath))
+		if macaroonPath == "" {
+			macaroonPath = path.Join(ladDir, defaultAdminMacaroonFilename)
+		}
+
+		mac, err := util.LoadMacaroon(macaroonPath)
+		if err != nil {
+			log.Fatalf("unable to load macaroon: %v", err)
+		}
+
+		opts = append(opts, grpc.WithPerRPCCredentials(
+			macaroons.NewMacaroonCredential(mac)))
 	}
 
-	rpcServer := fmt.Sprintf("localhost:%d", rpcPort)
+	rpcServer := fmt.Sprintf("localhost:%d", ctx.GlobalInt(flag_rpcport))
 
 	conn, err := grpc.Dial(rpcServer, opts...)
 	if err != nil {
-		log.Fatalf("unable to connect to RPC server: %w", err)
+		log.Fatalf("unable to connect to RPC server: %v", err)
 	}
 
 	cleanUp := func() {
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// how long the assets supported by the server are cached
+const defaultAssetCacheTTL = 10 * time.Minute
+
+// assetCache caches the assets supported by the server, so we do not have
+// to ask the server every time a contract is created
+type assetCache struct {
+	server *grpcServerConnection
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	assets  []string
+	fetched time.Time
+}
+
+func newAssetCache(server *grpcServerConnection, ttl time.Duration) *assetCache {
+	return &assetCache{
+		server: server,
+		ttl:    ttl,
+	}
+}
+
+// get returns the assets supported by the server, and when they were
+// fetched. The assets are fetched from the server if the cache is expired or
+// refresh is set. If the server can not be reached, the last assets we
+// fetched are returned. The lock is not held while asking the server, so a
+// slow server does not hold up callers that can use the cache.
+func (c *assetCache) get(ctx context.Context, refresh bool) ([]string, time.Time, error) {
+	c.mu.Lock()
+	assets, fetched := c.assets, c.fetched
+	c.mu.Unlock()
+
+	if !refresh && !fetched.IsZero() && time.Since(fetched) < c.ttl {
+		return assets, fetched, nil
+	}
+
+	res, err := c.server.server.ListAssets(ctx, &larpc.ServerListAssetsRequest{})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		if c.fetched.IsZero() {
+			return nil, time.Time{}, status.Errorf(codes.Unavailable,
+				"could not get supported assets from server: %v", err)
+		}
+
+		log.WithError(err).Warn("could not refresh supported assets, " +
+			"using the ones we already have")
+		return c.assets, c.fetched, nil
+	}
+
+	// another caller may have fetched the assets while we did, in which
+	// case we keep the latest
+	now := time.Now()
+	if now.After(c.fetched) {
+		c.assets = res.SupportedAssets
+		c.fetched = now
+	}
+
+	return c.assets, c.fetched, nil
+}
+
+// validateAsset checks that contracts can be created in asset, which
+// requires the server to support it and us to have a price for it
+func (a AssetClient) validateAsset(ctx context.Context, asset string) error {
+	supported, _, err := a.assets.get(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	if !contains(supported, asset) {
+		sorted := append([]string(nil), supported...)
+		sort.Strings(sorted)
+
+		return status.Errorf(codes.InvalidArgument,
+			"asset %q is not supported by the server, supported assets are: %s",
+			asset, strings.Join(sorted, ", "))
+	}
+
+	if _, err := a.oracle.Price(asset); err != nil {
+		return status.Errorf(codes.FailedPrecondition,
+			"asset %q has no usable price: %v", asset, err)
+	}
+
+	return nil
+}
+
+func (a AssetClient) ListAssets(ctx context.Context, req *larpc.ClientListAssetsRequest) (*larpc.ClientListAssetsResponse, error) {
+	log.Infoln("received list assets request")
+
+	supported, fetched, err := a.assets.get(ctx, req.Refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &larpc.ClientListAssetsResponse{
+		FetchedAt: fetched.Unix(),
+	}
+	for _, asset := range supported {
+		if _, err := a.oracle.Price(asset); err != nil {
+			res.UnpricedAssets = append(res.UnpricedAssets, asset)
+			continue
+		}
+		res.Assets = append(res.Assets, asset)
+	}
+
+	sort.Strings(res.Assets)
+	sort.Strings(res.UnpricedAssets)
+
+	return res, nil
+}
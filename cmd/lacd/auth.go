@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/lightningnetwork/lnd/macaroons"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+
+	"github.com/ArcaneCryptoAS/lassets-client/util"
+)
+
+const (
+	defaultTLSCertFilename          = "tls.cert"
+	defaultTLSKeyFilename           = "tls.key"
+	defaultAdminMacaroonFilename    = "admin.macaroon"
+	defaultReadonlyMacaroonFilename = "readonly.macaroon"
+
+	// the organization of the self-signed certificates we generate
+	certOrganization = "lacd autogenerated cert"
+)
+
+const (
+	// the file the macaroon service keeps its encrypted root key in
+	macaroonDBFilename = "macaroons.db"
+
+	// the file the password of the macaroon root key is kept in. The
+	// password is generated on first start, so it is different for every
+	// install.
+	macaroonPasswordFilename = "macaroons.password"
+)
+
+var (
+	// readPermissions are given to the readonly macaroon
+	readPermissions = []bakery.Op{
+		{Entity: "contracts", Action: "read"},
+		{Entity: "payments", Action: "read"},
+		{Entity: "info", Action: "read"},
+	}
+
+	// writePermissions are given to the admin macaroon, in addition to
+	// the read permissions
+	writePermissions = []bakery.Op{
+		{Entity: "contracts", Action: "write"},
+		{Entity: "payments", Action: "write"},
+	}
+
+	// rpcPermissions lists the permissions required to call each RPC
+	rpcPermissions = map[string][]bakery.Op{
+		"/larpc.AssetClient/CreateContract": {{
+			Entity: "contracts",
+			Action: "write",
+		}},
+		"/larpc.AssetClient/OpenContract": {{
+			Entity: "contracts",
+			Action: "write",
+		}, {
+			Entity: "payments",
+			Action: "write",
+		}},
+		"/larpc.AssetClient/CloseContract": {{
+			Entity: "contracts",
+			Action: "write",
+		}},
+		"/larpc.AssetClient/ListContracts": {{
+			Entity: "contracts",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/SubscribeClientContracts": {{
+			Entity: "contracts",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/ListPayments": {{
+			Entity: "payments",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/SubscribePayments": {{
+			Entity: "payments",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/GetConfig": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/GetStatus": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/GetInfo": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/ListAssets": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/GetServerIdentity": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/larpc.AssetClient/ResetServerIdentity": {{
+			Entity: "payments",
+			Action: "write",
+		}},
+		"/larpc.AssetClient/RequestPaymentRequest": {{
+			Entity: "payments",
+			Action: "write",
+		}},
+		"/larpc.AssetClient/RequestPayment": {{
+			Entity: "payments",
+			Action: "write",
+		}},
+	}
+
+	// serverRPCs are called by the asset server when it connects to us,
+	// and it does not have any of our macaroons. Every request to these is
+	// verified on its own. Without a public address the server only
+	// reaches us over the push channel, and these require a macaroon like
+	// any other RPC.
+	serverRPCs = map[string]bool{
+		"/larpc.AssetClient/RequestPaymentRequest": true,
+		"/larpc.AssetClient/RequestPayment":        true,
+	}
+)
+
+// loadTLSCredentials loads the TLS certificate and key of lacd, and
+// generates a self-signed pair if there is none
+func loadTLSCredentials(certPath, keyPath string) (credentials.TransportCredentials, error) {
+	if !util.FileExists(certPath) || !util.FileExists(keyPath) {
+		err := util.GenCertPair(certOrganization, certPath, keyPath, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate TLS certificate: %w", err)
+		}
+	}
+
+	creds, err := credentials.NewServerTLSFromFile(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load TLS certificate: %w", err)
+	}
+
+	return creds, nil
+}
+
+// newMacaroonService opens the macaroon service in dir, and creates the
+// admin and readonly macaroons if they do not exist
+func newMacaroonService(ctx context.Context, dir string) (*macaroons.Service, error) {
+	password, err := macaroonPassword(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := macaroons.NewService(dir, macaroons.IPLockChecker)
+	if err != nil {
+		return nil, fmt.Errorf("could not create macaroon service: %w", err)
+	}
+
+	if err := svc.CreateUnlock(&password); err != nil {
+		svc.Close()
+		return nil, fmt.Errorf("could not unlock macaroon service: %w", err)
+	}
+
+	adminPath := path.Join(dir, defaultAdminMacaroonFilename)
+	readonlyPath := path.Join(dir, defaultReadonlyMacaroonFilename)
+
+	if !util.FileExists(adminPath) || !util.FileExists(readonlyPath) {
+		if err := genMacaroons(ctx, svc, adminPath, readonlyPath); err != nil {
+			svc.Close()
+			return nil, err
+		}
+	}
+
+	return svc, nil
+}
+
+// macaroonPassword returns the password the macaroon root key is encrypted
+// with. On first start a random password is generated. Older versions of lacd
+// encrypted the root key with a fixed password, and lnd can not change the
+// password of a root key, so the root key and macaroons of such an install
+// are removed and created again.
+func macaroonPassword(dir string) ([]byte, error) {
+	passwordPath := path.Join(dir, macaroonPasswordFilename)
+
+	if util.FileExists(passwordPath) {
+		password, err := ioutil.ReadFile(passwordPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read macaroon password: %w", err)
+		}
+
+		return password, nil
+	}
+
+	if util.FileExists(path.Join(dir, macaroonDBFilename)) {
+		for _, filename := range []string{macaroonDBFilename,
+			defaultAdminMacaroonFilename, defaultReadonlyMacaroonFilename} {
+
+			err := os.Remove(path.Join(dir, filename))
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("could not remove %s: %w", filename, err)
+			}
+		}
+
+		log.Warn("removed macaroons encrypted with the password of older " +
+			"versions of lacd, new macaroons are created")
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("could not generate macaroon password: %w", err)
+	}
+	password := []byte(hex.EncodeToString(random))
+
+	if err := ioutil.WriteFile(passwordPath, password, 0600); err != nil {
+		return nil, fmt.Errorf("could not write macaroon password: %w", err)
+	}
+
+	return password, nil
+}
+
+// genMacaroons bakes the admin and readonly macaroons and writes them to
+// file
+func genMacaroons(ctx context.Context, svc *macaroons.Service,
+	adminPath, readonlyPath string) error {
+
+	adminPermissions := append(append([]bakery.Op{}, readPermissions...),
+		writePermissions...)
+
+	for macaroonPath, permissions := range map[string][]bakery.Op{
+		adminPath:    adminPermissions,
+		readonlyPath: readPermissions,
+	} {
+		mac, err := svc.Oven.NewMacaroon(ctx, bakery.LatestVersion, nil,
+			permissions...)
+		if err != nil {
+			return fmt.Errorf("could not bake macaroon: %w", err)
+		}
+
+		macBytes, err := mac.M().MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("could not serialize macaroon: %w", err)
+		}
+
+		if err := ioutil.WriteFile(macaroonPath, macBytes, 0600); err != nil {
+			return fmt.Errorf("could not write macaroon: %w", err)
+		}
+
+		log.WithField("path", macaroonPath).Info("created macaroon")
+	}
+
+	return nil
+}
+
+// validateRPC checks that the macaroon sent with a request has the
+// permissions required by the RPC. RPCs we have no permissions for are
+// refused, so a new RPC is never callable without authentication by mistake.
+// The RPCs of the server are only callable without a macaroon if
+// allowServerRPCs is set.
+func validateRPC(ctx context.Context, svc *macaroons.Service, method string,
+	allowServerRPCs bool) error {
+
+	if serverRPCs[method] && allowServerRPCs {
+		return nil
+	}
+
+	permissions, ok := rpcPermissions[method]
+	if !ok {
+		return fmt.Errorf("%s: unknown permissions required for method", method)
+	}
+
+	return svc.ValidateMacaroon(ctx, permissions)
+}
+
+// unaryAuthInterceptor validates the macaroon of every unary request
+func unaryAuthInterceptor(svc *macaroons.Service,
+	allowServerRPCs bool) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if err := validateRPC(ctx, svc, info.FullMethod, allowServerRPCs); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor validates the macaroon of every stream request
+func streamAuthInterceptor(svc *macaroons.Service,
+	allowServerRPCs bool) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+
+		if err := validateRPC(ss.Context(), svc, info.FullMethod, allowServerRPCs); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
@@ -2,69 +2,173 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/boltdb/bolt"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+	"github.com/ArcaneCryptoAS/lassets-client/oracle"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
 )
 
 var _ larpc.AssetClientServer = &AssetClient{}
 
 type AssetClient struct {
 	lncli      lnrpc.LightningClient
-	db         *bolt.DB
-	contracts  *bolt.Bucket
+	router     routerrpc.RouterClient
+	lnd        *lndConnection
+	db         store.Store
 	port       int
+	network    string
 	netAddress string
 	server     *grpcServerConnection
+	identity   *serverIdentity
+	assets     *assetCache
+	oracle     *oracle.Oracle
+	config     *config
 
-	// channels
-	paymentsCh chan larpc.Payment
-	contractCh chan larpc.ClientContract
+	// how many percent a payment requested by the server may differ
+	// from the amount we calculate we owe
+	paymentTolerance money.Decimal
+
+	// which quotes from the server we accept when creating contracts
+	quotePolicy quotePolicy
+
+	// how we pay invoices, unless overridden in a request
+	paymentPolicy paymentPolicy
+
+	// whether interrupted payments have been reconciled with lnd, which
+	// must happen before we pay anything
+	reconciled *reconciliation
+
+	// subscribers of contract and payment updates
+	contractNotifier *notifier
+	paymentNotifier  *notifier
 }
 
 func (a AssetClient) CreateContract(ctx context.Context, req *larpc.ClientCreateContractRequest) (*larpc.ClientCreateContractResponse, error) {
 	log.Infoln("received create contract request")
 
-	if req.Amount == 0 {
+	amount, err := requestAmount(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if amount.IsZero() {
 		return nil, fmt.Errorf("amount can not be 0")
 	}
 
-	res, err := a.server.server.NewContract(ctx, &larpc.ServerNewContractRequest{
-		Asset:        req.Asset,
-		Amount:       req.Amount,
-		Host:         a.netAddress,
-		ContractType: req.ContractType,
-	})
+	if err := a.lnd.ready(); err != nil {
+		return nil, err
+	}
+	if err := a.server.ready(); err != nil {
+		return nil, err
+	}
+
+	if err := a.validateAsset(ctx, req.Asset); err != nil {
+		return nil, err
+	}
+
+	latestPrice, err := a.oracle.Price(req.Asset)
 	if err != nil {
+		return nil, fmt.Errorf("could not get price: %w", err)
+	}
+
+	sig, err := a.newSignature()
+	if err != nil {
+		return nil, err
+	}
+	serverReq := &larpc.ServerNewContractRequest{
+		Asset:         req.Asset,
+		Amount:        amount.Float64(),
+		AmountDecimal: amount.String(),
+		Host:          a.netAddress,
+		ContractType:  req.ContractType,
+		NodePubkey:    sig.NodePubkey,
+		Signature:     sig,
+	}
+	if err := a.sign(ctx, sig, serverReq.SigningPayload()); err != nil {
 		return nil, err
 	}
 
+	res, err := a.server.server.NewContract(ctx, serverReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// the invoices are only trusted if the server node vouches for them
+	err = a.verifyResponse(ctx, sig, res.Signature, res.SigningPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	serverPrice, err := money.ParseDecimalOr(res.AssetPriceDecimal, res.AssetPrice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server price: %w", err)
+	}
+	percentMargin, err := money.ParseDecimalOr(res.PercentMarginDecimal, res.PercentMargin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server margin: %w", err)
+	}
+
+	// the contract starts out worth the amount at the price the server
+	// quoted, and is rebalanced from there
+	amountSat, err := convertPercentOfAssetToSats(amount, serverPrice,
+		money.OneHundredPercent)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	marginInv, err := a.lncli.DecodePayReq(ctx, &lnrpc.PayReqString{
 		PayReq: res.MarginPayReq,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if err := a.identity.verify(marginInv.Destination); err != nil {
+		return nil, err
+	}
 
 	contract := larpc.ClientContract{
 		Uuid:            res.Uuid,
+		ServerPubkey:    marginInv.Destination,
 		Asset:           req.Asset,
-		Amount:          req.Amount,
+		Amount:          amount.Float64(),
+		AmountDecimal:   amount.String(),
 		AmountSatMargin: marginInv.NumSatoshis,
 		MarginInvoice:   res.MarginPayReq,
 		ContractType:    req.ContractType,
-	}
 
-	latestPrice := price[req.Asset]
+		MarginPaymentHash: marginInv.PaymentHash,
+		ExpiresAt:         invoiceExpiry(marginInv).Unix(),
 
-	expectedInitAmount := convertPercentOfAssetToSats(req.Amount, latestPrice, 100)
-	expectedMarginAmount := convertPercentOfAssetToSats(req.Amount, latestPrice, res.PercentMargin)
+		AmountSat: amountSat,
+
+		Status: larpc.ContractStatus_CREATED,
+		StatusHistory: []*larpc.ContractStatusChange{{
+			Status:    larpc.ContractStatus_CREATED,
+			Timestamp: time.Now().Unix(),
+		}},
+	}
+
+	expectedInitMsat, err := assetValue(amount, latestPrice.Value,
+		money.OneHundredPercent)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	expectedMarginMsat, err := assetValue(amount, latestPrice.Value, percentMargin)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	expectedInitAmount := expectedInitMsat.ToSatoshis(money.RoundHalfUp)
+	expectedMarginAmount := expectedMarginMsat.ToSatoshis(money.RoundHalfUp)
 
 	switch req.ContractType {
 	case larpc.ContractType_FUNDED:
@@ -74,19 +178,42 @@ func (a AssetClient) CreateContract(ctx context.Context, req *larpc.ClientCreate
 		if err != nil {
 			return nil, err
 		}
+		if err := a.identity.verify(initInv.Destination); err != nil {
+			return nil, err
+		}
 
 		// update the necessary fields
 		contract.AmountSatInit = initInv.NumSatoshis
 		contract.InitInvoice = res.InitiatingPayReq
+		contract.InitPaymentHash = initInv.PaymentHash
+		if expiry := invoiceExpiry(initInv).Unix(); expiry < contract.ExpiresAt {
+			contract.ExpiresAt = expiry
+		}
 
 	case larpc.ContractType_UNFUNDED:
 		// do some special logic if necesssary
 		expectedInitAmount = 0
+		expectedInitMsat = 0
 	default:
 		return nil, fmt.Errorf("contract type %v not supported", req.ContractType)
 	}
 
-	err = saveContract(a.db, a.contractCh, contract)
+	contract.QuoteValidation = a.quotePolicy.validate(quote{
+		ourPrice:          latestPrice.Value,
+		serverPrice:       serverPrice,
+		percentMargin:     percentMargin,
+		marginSat:         contract.AmountSatMargin,
+		expectedMarginSat: expectedMarginAmount,
+		initSat:           contract.AmountSatInit,
+		expectedInitSat:   expectedInitAmount,
+	})
+	if !contract.QuoteValidation.Accepted {
+		log.WithField("uuid", contract.Uuid).
+			Warnf("rejected quote: %v", contract.QuoteValidation.Reasons)
+	}
+
+	err = saveContract(a.db, a.contractNotifier,
+		larpc.ClientContractUpdate_CREATED, contract)
 	if err != nil {
 		return nil, err
 	}
@@ -96,48 +223,93 @@ func (a AssetClient) CreateContract(ctx context.Context, req *larpc.ClientCreate
 
 		ExpectedMarginAmount: expectedMarginAmount,
 		ExpectedInitAmount:   expectedInitAmount,
-		OurPrice:             latestPrice,
+		OurPrice:             latestPrice.Value.Float64(),
+
+		ServerPrice:   serverPrice.Float64(),
+		PercentMargin: percentMargin.Float64(),
+
+		QuoteValidation: contract.QuoteValidation,
+
+		OurPriceDecimal:      latestPrice.Value.String(),
+		ServerPriceDecimal:   serverPrice.String(),
+		PercentMarginDecimal: percentMargin.String(),
 
-		ServerPrice:   res.AssetPrice,
-		PercentMargin: res.PercentMargin,
+		ExpectedMarginAmountMsat: int64(expectedMarginMsat),
+		ExpectedInitAmountMsat:   int64(expectedInitMsat),
 	}, nil
 }
 
 func (a AssetClient) OpenContract(ctx context.Context, req *larpc.ClientOpenContractRequest) (*larpc.ClientOpenContractResponse, error) {
 	log.Infoln("received open contract request")
 
-	var contract larpc.ClientContract
-
-	err := a.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(contractsBucket)
-
-		asBytes := b.Get([]byte(req.Uuid))
+	// opening pays the server, which we can not do without lnd
+	if err := a.lnd.ready(); err != nil {
+		return nil, err
+	}
+	if err := a.reconciled.ready(); err != nil {
+		return nil, err
+	}
 
-		err := json.Unmarshal(asBytes, &contract)
-		if err != nil {
-			return err
-		}
+	if err := validatePaymentOptions(req.PaymentOptions); err != nil {
+		return nil, err
+	}
+	policy := a.paymentPolicy.withOptions(req.PaymentOptions)
 
-		return nil
-	})
+	stored, err := a.db.GetContract(req.Uuid)
 	if err != nil {
 		return nil, fmt.Errorf("could not get contract from database: %w", err)
 	}
+	contract := *stored
 
-	err = a.PayInvoice(contract.MarginInvoice)
-	if err != nil {
-		return nil, err
-	}
+	switch contract.Status {
+	case larpc.ContractStatus_CREATED:
+		if expiresSoon(&contract) {
+			if err := a.expireContract(&contract); err != nil {
+				return nil, err
+			}
+			return nil, errContractExpired(&contract)
+		}
+
+		// contracts created before quotes were validated have no validation
+		if v := contract.QuoteValidation; v != nil && !v.Accepted {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"quote of contract %s was rejected: %s", contract.Uuid,
+				strings.Join(v.Reasons, ", "))
+		}
 
-	if contract.ContractType == larpc.ContractType_FUNDED {
-		err = a.PayInvoice(contract.InitInvoice)
+		err = transition(&contract, larpc.ContractStatus_OPENING, "")
 		if err != nil {
 			return nil, err
 		}
+		err = saveContract(a.db, a.contractNotifier,
+			larpc.ClientContractUpdate_OPENING, contract)
+		if err != nil {
+			return nil, fmt.Errorf("could not save contract in DB: %w", err)
+		}
+
+	// a previous attempt to open the contract failed or was interrupted,
+	// the invoices that were paid are not paid again
+	case larpc.ContractStatus_OPENING:
+		log.WithField("uuid", contract.Uuid).Info("retrying to open contract")
+
+	case larpc.ContractStatus_EXPIRED:
+		return nil, errContractExpired(&contract)
+
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"contract %s is %s, and can not be opened", contract.Uuid, contract.Status)
 	}
 
-	contract.InvoicesPaid = true
-	err = saveContract(a.db, a.contractCh, contract)
+	if err := a.payOpeningInvoices(ctx, &contract, policy); err != nil {
+		return nil, err
+	}
+
+	err = transition(&contract, larpc.ContractStatus_OPEN, "")
+	if err != nil {
+		return nil, err
+	}
+	err = saveContract(a.db, a.contractNotifier,
+		larpc.ClientContractUpdate_OPENED, contract)
 	if err != nil {
 		return nil, fmt.Errorf("could not save contract in DB: %w", err)
 	}
@@ -149,37 +321,73 @@ func (a AssetClient) OpenContract(ctx context.Context, req *larpc.ClientOpenCont
 	}, nil
 }
 
-// convertPercentOfAssetToSats converts a percentage of an amount of a given asset to satoshis
-func convertPercentOfAssetToSats(amount float64, price float64, percent float64) int64 {
-	amountSat := (amount / price) * btcutil.SatoshiPerBitcoin
+// requestAmount returns the exact amount of a create contract request. A
+// float amount is taken as the shortest decimal that represents it.
+func requestAmount(req *larpc.ClientCreateContractRequest) (money.Decimal, error) {
+	if req.AmountDecimal != "" {
+		return money.ParseAmount(req.Asset, req.AmountDecimal)
+	}
 
-	return int64(math.Round(amountSat / 100 * percent))
+	return money.ParseAmount(req.Asset,
+		strconv.FormatFloat(req.Amount, 'f', -1, 64))
 }
 
-func saveContract(db *bolt.DB, contractCh chan larpc.ClientContract, contract larpc.ClientContract) error {
-	err := db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(contractsBucket)
+// contractAmount returns the exact amount of a contract. Contracts created
+// before amounts were exact only have the float, which is rounded to the
+// decimals of the asset.
+func contractAmount(contract *larpc.ClientContract) (money.Decimal, error) {
+	if contract.AmountDecimal != "" {
+		return money.ParseAmount(contract.Asset, contract.AmountDecimal)
+	}
 
-		reqBytes, err := json.Marshal(contract)
-		if err != nil {
-			return err
-		}
+	return money.AmountFromFloat(contract.Asset, contract.Amount)
+}
 
-		return b.Put([]byte(contract.Uuid), reqBytes)
-	})
+// assetValue returns a percentage of an amount of a given asset in
+// millisatoshis, rounded half up
+func assetValue(amount, price, percent money.Decimal) (money.MilliSatoshi, error) {
+	value, err := money.AssetValue(amount, price, percent, money.RoundHalfUp)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("could not convert %s percent of %s at price %s: %w",
+			percent, amount, price, err)
 	}
 
-	// pass the saved contract on to the contractCh, in case someone is subscribed
-	select {
-	case contractCh <- contract:
-	default:
+	return value, nil
+}
+
+// convertPercentOfAssetToSats converts a percentage of an amount of a given
+// asset to satoshis, rounded half up
+func convertPercentOfAssetToSats(amount, price, percent money.Decimal) (int64, error) {
+	value, err := assetValue(amount, price, percent)
+	if err != nil {
+		return 0, err
+	}
+
+	return value.ToSatoshis(money.RoundHalfUp), nil
+}
+
+func saveContract(db store.Store, contractNotifier *notifier,
+	updateType larpc.ClientContractUpdate_UpdateType, contract larpc.ClientContract) error {
+	if err := db.PutContract(&contract); err != nil {
+		return err
 	}
 
+	// pass the saved contract on to the subscribers, in case someone is subscribed
+	notifyContract(contractNotifier, updateType, contract)
+
 	return nil
 }
 
+// notifyContract notifies all contract subscribers of an update
+func notifyContract(contractNotifier *notifier,
+	updateType larpc.ClientContractUpdate_UpdateType, contract larpc.ClientContract) {
+
+	contractNotifier.Notify(&larpc.ClientContractUpdate{
+		Type:     updateType,
+		Contract: &contract,
+	})
+}
+
 func (a AssetClient) CloseContract(ctx context.Context, req *larpc.ClientCloseContractRequest) (*larpc.ClientCloseContractResponse, error) {
 	log.Infoln("received close contract request")
 
@@ -187,37 +395,103 @@ func (a AssetClient) CloseContract(ctx context.Context, req *larpc.ClientCloseCo
 		return nil, fmt.Errorf("request can not be nil")
 	}
 
-	var contract larpc.ClientContract
-	err := a.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(contractsBucket)
+	if _, err := a.db.GetContract(req.Uuid); err != nil {
+		return nil, err
+	}
 
-		rawContract := b.Get([]byte(req.Uuid))
+	// the request is signed by lnd
+	if err := a.lnd.ready(); err != nil {
+		return nil, err
+	}
+	if err := a.server.ready(); err != nil {
+		return nil, err
+	}
 
-		err := json.Unmarshal(rawContract, &contract)
-		if err != nil {
-			return fmt.Errorf("could not unmarshal contract: %w", err)
-		}
+	sig, err := a.newSignature()
+	if err != nil {
+		return nil, err
+	}
+	serverReq := &larpc.ServerCloseContractRequest{
+		Uuid:      req.Uuid,
+		Signature: sig,
+	}
+	if err := a.sign(ctx, sig, serverReq.SigningPayload()); err != nil {
+		return nil, err
+	}
 
-		return nil
-	})
+	// the status changes are made on the stored contract inside a
+	// transaction, so a rebalance completing meanwhile is not lost
+	var prior larpc.ContractStatus
+	err = updateContract(a.db, a.contractNotifier, req.Uuid,
+		larpc.ClientContractUpdate_CLOSING,
+		func(tx store.Tx, contract *larpc.ClientContract) error {
+			// a contract already closing was interrupted while
+			// closing, ie by a crash, and we try again
+			prior = contract.Status
+			if prior == larpc.ContractStatus_CLOSING {
+				prior = previousStatus(contract)
+				return nil
+			}
+
+			// the server would not know about the rebalance
+			latest, err := tx.LatestRebalance(contract.Uuid)
+			if err != nil {
+				return err
+			}
+			if latest != nil && latest.State == larpc.RebalanceState_REBALANCE_PENDING {
+				return status.Errorf(codes.FailedPrecondition,
+					"contract %s has rebalance %d in progress", contract.Uuid,
+					latest.Id)
+			}
+
+			err = transition(contract, larpc.ContractStatus_CLOSING, "")
+			if err != nil {
+				return status.Error(codes.FailedPrecondition, err.Error())
+			}
+			return nil
+		})
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = a.server.server.CloseContract(ctx,
-		&larpc.ServerCloseContractRequest{
-		Uuid: req.Uuid,
-	})
+	res, err := a.server.server.CloseContract(ctx, serverReq)
+	if err == nil {
+		// an unverified response could be forged, so we do not
+		// consider the contract closed
+		err = a.verifyResponse(ctx, sig, res.Signature,
+			res.SigningPayload(req.Uuid))
+	}
 	if err != nil {
+		log.WithError(err).WithField("uuid", req.Uuid).
+			Error("could not close contract with server")
+
+		// the contract is not closed at the server, so we go back to
+		// the status we had before trying to close it
+		updateType := larpc.ClientContractUpdate_OPENED
+		switch prior {
+		case larpc.ContractStatus_CREATED:
+			updateType = larpc.ClientContractUpdate_CREATED
+		case larpc.ContractStatus_FAILED:
+			updateType = larpc.ClientContractUpdate_FAILED
+		}
+		err := updateContract(a.db, a.contractNotifier, req.Uuid, updateType,
+			func(tx store.Tx, contract *larpc.ClientContract) error {
+				return transition(contract, prior,
+					"server could not close contract")
+			})
+		if err != nil {
+			return nil, err
+		}
+
 		return nil, fmt.Errorf("could not close contract with server")
 	}
 
-	// delete the contract from db
-	err = a.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(contractsBucket)
-
-		return b.Delete([]byte(req.Uuid))
-	})
+	// closed contracts are kept in the db, so we have a full history
+	err = updateContract(a.db, a.contractNotifier, req.Uuid,
+		larpc.ClientContractUpdate_CLOSED,
+		func(tx store.Tx, contract *larpc.ClientContract) error {
+			return transition(contract, larpc.ContractStatus_CLOSED, "")
+		})
 	if err != nil {
 		return nil, err
 	}
@@ -225,18 +499,55 @@ func (a AssetClient) CloseContract(ctx context.Context, req *larpc.ClientCloseCo
 	return &larpc.ClientCloseContractResponse{}, nil
 }
 
+// updateContract reads the contract with uuid, changes it with fn and stores
+// it in a single transaction, so it is not overwritten with a stale copy.
+// The subscribers are notified once it is stored.
+func updateContract(db store.Store, contractNotifier *notifier, uuid string,
+	updateType larpc.ClientContractUpdate_UpdateType,
+	fn func(tx store.Tx, contract *larpc.ClientContract) error) error {
+
+	var contract *larpc.ClientContract
+	err := db.Update(func(tx store.Tx) error {
+		var err error
+		contract, err = tx.GetContract(uuid)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx, contract); err != nil {
+			return err
+		}
+
+		return tx.PutContract(contract)
+	})
+	if err != nil {
+		return err
+	}
+
+	notifyContract(contractNotifier, updateType, *contract)
+
+	return nil
+}
+
 func (a AssetClient) RequestPaymentRequest(ctx context.Context, req *larpc.ClientRequestPaymentRequestRequest) (*larpc.ClientRequestPaymentRequestResponse, error) {
 	log.Infoln("received request payment request request")
 
-	res, err := a.lncli.AddInvoice(ctx, &lnrpc.Invoice{
-		Value: req.AmountSat,
-	})
+	_, err := a.db.GetContract(req.Uuid)
+	switch {
+	case errors.Is(err, store.ErrContractNotFound):
+		return nil, status.Errorf(codes.NotFound, "contract %s not found", req.Uuid)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "could not get contract: %v", err)
+	}
+
+	payment, err := a.addContractInvoice(ctx, req.Uuid, req.AmountSat,
+		larpc.PaymentType_REBALANCE, fmt.Sprintf("contract %s", req.Uuid))
 	if err != nil {
 		return nil, err
 	}
 
 	return &larpc.ClientRequestPaymentRequestResponse{
-		PayReq: res.PaymentRequest,
+		PayReq: payment.PaymentRequest,
 	}, nil
 
 }
@@ -244,12 +555,65 @@ func (a AssetClient) RequestPaymentRequest(ctx context.Context, req *larpc.Clien
 func (a AssetClient) RequestPayment(ctx context.Context, req *larpc.ClientRequestPaymentRequest) (*larpc.ClientRequestPaymentResponse, error) {
 	log.Infoln("received request payment request")
 
-	// TODO: Check amount is correct
-	err := a.PayInvoice(req.PayReq)
+	if err := a.reconciled.ready(); err != nil {
+		return nil, err
+	}
+
+	contract, err := a.db.GetContract(req.Uuid)
+	switch {
+	case errors.Is(err, store.ErrContractNotFound):
+		return nil, status.Errorf(codes.NotFound, "contract %s not found", req.Uuid)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "could not get contract: %v", err)
+	}
+
+	invoice, price, err := a.verifyPaymentRequest(ctx, contract, req.PayReq)
 	if err != nil {
+		log.WithError(err).WithField("uuid", contract.Uuid).
+			Warn("rejected payment request")
 		return nil, err
 	}
 
+	// the payment settles part of the value of the contract, so we record
+	// it the same way as the rebalances we initiate ourselves
+	rebalance := larpc.Rebalance{
+		ContractUuid: contract.Uuid,
+		AssetPrice:   price.Float64(),
+		AmountSat:    -invoice.NumSatoshis,
+		PayReq:       req.PayReq,
+		State:        larpc.RebalanceState_REBALANCE_PENDING,
+		CreatedAt:    time.Now().Unix(),
+
+		AssetPriceDecimal: price.String(),
+	}
+	err = addRebalance(a.db, &rebalance)
+	switch {
+	case errors.Is(err, errRebalancePending):
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"contract %s has a pending rebalance", contract.Uuid)
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	_, err = a.payContractInvoice(ctx, contract.Uuid, req.PayReq,
+		larpc.PaymentType_REBALANCE, a.paymentPolicy)
+	if paymentFailed(err) {
+		// the server has to ask again with a new invoice, and we do not
+		// pay this one on our own later
+		rebalance.State = larpc.RebalanceState_REBALANCE_FAILED
+		if err := a.db.PutRebalance(&rebalance); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = completeRebalance(a.db, a.contractNotifier, rebalance)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	return &larpc.ClientRequestPaymentResponse{}, nil
 }
 
@@ -260,62 +624,139 @@ func (a AssetClient) ListContracts(ctx context.Context, req *larpc.ClientListCon
 		return nil, fmt.Errorf("request can not be nil")
 	}
 
-	var contracts []*larpc.ClientContract
-	err := a.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(contractsBucket)
-
-		return b.ForEach(func(k, v []byte) error {
-			var contract larpc.ClientContract
-			err := json.Unmarshal(v, &contract)
-			if err != nil {
-				return err
-			}
-
-			contracts = append(contracts, &contract)
-
-			return nil
-		})
-	})
+	contracts, err := a.db.ListContracts()
 	if err != nil {
 		return nil, err
 	}
 
+	if !req.IncludeInactive {
+		var active []*larpc.ClientContract
+		for _, contract := range contracts {
+			if isActive(contract) {
+				active = append(active, contract)
+			}
+		}
+		contracts = active
+	}
+
 	return &larpc.ClientListContractsResponse{
 		Contracts: contracts,
 	}, nil
 }
 
-func (a AssetClient) SubscribeClientContracts(req *larpc.
-ClientSubscribeContractsRequest, updateStream larpc.
-AssetClient_SubscribeClientContractsServer) error {
+func (a AssetClient) SubscribeClientContracts(req *larpc.ClientSubscribeContractsRequest,
+	updateStream larpc.AssetClient_SubscribeClientContractsServer) error {
 	log.Infoln("received subscribe client contracts request")
 
-	contractCh := a.contractCh
-
-	for {
-		newContract := <-contractCh
+	// subscribe before reading the snapshot, so we do not miss any
+	// updates made while we read it
+	sub := a.contractNotifier.Subscribe()
+	defer sub.Cancel()
 
-		if err := updateStream.Send(&newContract); err != nil {
+	if req.IncludeSnapshot {
+		contracts, err := a.db.ListContracts()
+		if err != nil {
 			return err
 		}
-	}
-}
 
-// PayInvoice does not exist in grpc, but is a util method defined on an AssetClient
-func (a AssetClient) PayInvoice(paymentRequest string) error {
+		for _, contract := range contracts {
+			err := updateStream.Send(&larpc.ClientContractUpdate{
+				Type:     larpc.ClientContractUpdate_SNAPSHOT,
+				Contract: contract,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
 
-	res, err := a.lncli.SendPaymentSync(context.Background(), &lnrpc.SendRequest{
-		PaymentRequest: paymentRequest,
+	return forwardUpdates(updateStream.Context(), sub, func(update interface{}) error {
+		return updateStream.Send(update.(*larpc.ClientContractUpdate))
 	})
+}
+
+// PayInvoice does not exist in grpc, but is a util method defined on an
+// AssetClient. It makes a single attempt at paying the invoice of payment
+// through the router of lnd, within the fee limit, timeout and channel given
+// by the policy, and records the progress of the payment.
+func (a AssetClient) PayInvoice(ctx context.Context, payment *larpc.Payment,
+	payReq *lnrpc.PayReq, policy paymentPolicy) error {
+
+	req, err := a.sendRequest(ctx, policy, payment.PaymentRequest, payReq)
+	var paymentErr *paymentError
+	if errors.As(err, &paymentErr) {
+		return a.failPayment(payment, paymentErr)
+	}
 	if err != nil {
 		return err
 	}
 
-	if res.PaymentError != "" {
-		return fmt.Errorf("could not send payment: %s", res.PaymentError)
+	// the payment is followed until lnd is done with it even if the caller
+	// goes away, so we know how it ended. lnd gives up after the timeout.
+	stream, err := a.router.SendPayment(context.Background(), req)
+	if err != nil {
+		return fmt.Errorf("could not send payment: %w", err)
+	}
+
+	if err := a.followPayment(stream, payment); err != nil {
+		return err
 	}
 
-	log.WithField("paymentRequest", paymentRequest).Info("paid")
+	log.WithField("paymentRequest", payment.PaymentRequest).Info("paid")
 
 	return nil
 }
+
+// addInvoice creates a new invoice with our lnd node
+func (a AssetClient) addInvoice(ctx context.Context, amountSat int64, memo string) (*lnrpc.AddInvoiceResponse, error) {
+	return a.lncli.AddInvoice(ctx, &lnrpc.Invoice{
+		Value: amountSat,
+		Memo:  memo,
+	})
+}
+
+// invoiceState looks up the state of an invoice created by our lnd node
+func (a AssetClient) invoiceState(ctx context.Context, paymentRequest string) (lnrpc.Invoice_InvoiceState, error) {
+	payReq, err := a.lncli.DecodePayReq(ctx, &lnrpc.PayReqString{
+		PayReq: paymentRequest,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	invoice, err := a.lncli.LookupInvoice(ctx, &lnrpc.PaymentHash{
+		RHashStr: payReq.PaymentHash,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not look up invoice: %w", err)
+	}
+
+	return invoice.State, nil
+}
+
+// findLndPayment returns the payment lnd has made, or is making, for the
+// given payment hash, or nil if lnd has never tried to pay it
+func (a AssetClient) findLndPayment(ctx context.Context, paymentHash string) (*lnrpc.Payment, error) {
+	res, err := a.lncli.ListPayments(ctx, &lnrpc.ListPaymentsRequest{
+		IncludeIncomplete: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list payments: %w", err)
+	}
+
+	var found *lnrpc.Payment
+	for _, payment := range res.Payments {
+		if payment.PaymentHash != paymentHash {
+			continue
+		}
+
+		// lnd keeps failed attempts around, a succeeded or in flight
+		// attempt takes precedence
+		if found == nil || payment.Status == lnrpc.Payment_SUCCEEDED ||
+			payment.Status == lnrpc.Payment_IN_FLIGHT {
+			found = payment
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,928 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/lactest"
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+	"github.com/ArcaneCryptoAS/lassets-client/oracle"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+)
+
+// testHarness serves an AssetClient on an in-memory grpc connection. The
+// client talks to a fake asset server, and pays with a fake lnd node.
+type testHarness struct {
+	t   *testing.T
+	ctx context.Context
+
+	// rpc is connected to asset
+	rpc   larpc.AssetClientClient
+	asset *AssetClient
+
+	server     *lactest.AssetServer
+	serverNode *lactest.Node
+	clientNode *lactest.Node
+	prices     *lactest.PriceSource
+
+	stop func()
+}
+
+func newTestHarness(t *testing.T) *testHarness {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	network := lactest.NewNetwork()
+	serverNode := network.NewNode("server", 10000000)
+	clientNode := network.NewNode("client", 10000000)
+
+	server := lactest.NewAssetServer(serverNode, lactest.Quote{
+		AssetPrice:    money.MustParseDecimal("10000"),
+		PercentMargin: money.MustParseDecimal("10"),
+	})
+	serverClient, stopServer, err := lactest.StartAssetServer(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prices := lactest.NewPriceSource()
+	prices.SetPrice("USD", money.MustParseDecimal("10000"))
+	priceOracle := oracle.New(0, prices)
+	priceOracle.Start(ctx)
+
+	db := store.NewMemoryStore()
+
+	serverConn := &grpcServerConnection{
+		connectionState: newConnectionState("asset server", "bufnet"),
+		server:          serverClient,
+	}
+	serverConn.setState(larpc.ConnectionState_CONNECTED, nil)
+
+	lnd := &lndConnection{
+		connectionState: newConnectionState("lnd", "bufnet"),
+		client:          clientNode,
+	}
+	go lnd.monitor(ctx)
+
+	asset := &AssetClient{
+		lncli:    clientNode,
+		router:   clientNode.Router(),
+		lnd:      lnd,
+		db:       db,
+		server:   serverConn,
+		identity: &serverIdentity{db: db, address: "bufnet"},
+		assets:   newAssetCache(serverConn, time.Minute),
+		oracle:   priceOracle,
+
+		paymentTolerance: money.MustParseDecimal("1"),
+		quotePolicy: quotePolicy{
+			maxPriceDeviation: money.MustParseDecimal("5"),
+			maxMarginPercent:  money.MustParseDecimal("50"),
+		},
+		paymentPolicy: paymentPolicy{
+			maxFeeSat:     20,
+			maxFeePercent: 1,
+			maxAttempts:   1,
+			timeout:       time.Second,
+		},
+		reconciled: newReconciliation(),
+
+		contractNotifier: newNotifier(defaultSubscriberQueueSize),
+		paymentNotifier:  newNotifier(defaultSubscriberQueueSize),
+	}
+	asset.reconciled.finish()
+
+	conn, stopClient, err := lactest.Serve(func(s *grpc.Server) {
+		larpc.RegisterAssetClientServer(s, asset)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &testHarness{
+		t:          t,
+		ctx:        ctx,
+		rpc:        larpc.NewAssetClientClient(conn),
+		asset:      asset,
+		server:     server,
+		serverNode: serverNode,
+		clientNode: clientNode,
+		prices:     prices,
+		stop: func() {
+			stopClient()
+			stopServer()
+			cancel()
+		},
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer waitCancel()
+	if err := lnd.waitConnected(waitCtx); err != nil {
+		h.stop()
+		t.Fatalf("lnd did not become ready: %v", err)
+	}
+	for {
+		if _, err := priceOracle.Price("USD"); err == nil {
+			break
+		}
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-waitCtx.Done():
+			h.stop()
+			t.Fatal("oracle did not get a price")
+		}
+	}
+
+	return h
+}
+
+// setPrice sets the price of asset, and waits for the oracle to get it
+func (h *testHarness) setPrice(asset string, value money.Decimal) {
+	h.t.Helper()
+
+	h.prices.SetPrice(asset, value)
+
+	ctx, cancel := context.WithTimeout(h.ctx, 5*time.Second)
+	defer cancel()
+	for {
+		price, err := h.asset.oracle.Price(asset)
+		if err == nil && price.Value.Cmp(value) == 0 {
+			return
+		}
+
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			h.t.Fatalf("oracle did not get the price %s", value)
+		}
+	}
+}
+
+// createContract creates a contract for 10 USD
+func (h *testHarness) createContract() *larpc.ClientContract {
+	h.t.Helper()
+
+	res, err := h.rpc.CreateContract(h.ctx, &larpc.ClientCreateContractRequest{
+		Asset:         "USD",
+		AmountDecimal: "10",
+		ContractType:  larpc.ContractType_UNFUNDED,
+	})
+	if err != nil {
+		h.t.Fatalf("could not create contract: %v", err)
+	}
+
+	return res.Contract
+}
+
+// openContract creates and opens a contract for 10 USD
+func (h *testHarness) openContract() *larpc.ClientContract {
+	h.t.Helper()
+
+	contract := h.createContract()
+	res, err := h.rpc.OpenContract(h.ctx, &larpc.ClientOpenContractRequest{
+		Uuid: contract.Uuid,
+	})
+	if err != nil {
+		h.t.Fatalf("could not open contract: %v", err)
+	}
+
+	return res.Contract
+}
+
+// setStatus moves a stored contract to the given status
+func (h *testHarness) setStatus(uuid string, to larpc.ContractStatus) {
+	h.t.Helper()
+
+	contract, err := h.asset.db.GetContract(uuid)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	contract.Status = to
+	contract.StatusHistory = append(contract.StatusHistory,
+		&larpc.ContractStatusChange{Status: to, Timestamp: time.Now().Unix()})
+
+	if err := h.asset.db.PutContract(contract); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// requireStatus fails the test if the stored contract does not have the
+// given status
+func (h *testHarness) requireStatus(uuid string, want larpc.ContractStatus) {
+	h.t.Helper()
+
+	contract, err := h.asset.db.GetContract(uuid)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if contract.Status != want {
+		h.t.Fatalf("contract is %s, want %s", contract.Status, want)
+	}
+}
+
+// requireCode fails the test unless err has the given grpc code. A code of
+// OK requires err to be nil.
+func requireCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+
+	if got := status.Code(err); got != want {
+		t.Fatalf("got code %s (%v), want %s", got, err, want)
+	}
+}
+
+// failingStore fails to list contracts
+type failingStore struct {
+	store.Store
+	err error
+}
+
+func (s failingStore) ListContracts() ([]*larpc.ClientContract, error) {
+	return nil, s.err
+}
+
+var errStoreFailed = errors.New("store failed")
+
+func TestCreateContract(t *testing.T) {
+	tests := []struct {
+		name     string
+		prepare  func(h *testHarness)
+		asset    string
+		wantCode codes.Code
+	}{
+		{
+			name:     "success",
+			asset:    "USD",
+			wantCode: codes.OK,
+		},
+		{
+			name: "server error",
+			prepare: func(h *testHarness) {
+				h.server.FailNext("NewContract",
+					status.Error(codes.ResourceExhausted, "no liquidity"))
+			},
+			asset:    "USD",
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name: "server unavailable",
+			prepare: func(h *testHarness) {
+				h.asset.server.setState(larpc.ConnectionState_DISCONNECTED,
+					errors.New("connection refused"))
+			},
+			asset:    "USD",
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "unsupported asset",
+			asset:    "NOK",
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			if test.prepare != nil {
+				test.prepare(h)
+			}
+
+			res, err := h.rpc.CreateContract(h.ctx, &larpc.ClientCreateContractRequest{
+				Asset:         test.asset,
+				AmountDecimal: "10",
+				ContractType:  larpc.ContractType_UNFUNDED,
+			})
+			requireCode(t, err, test.wantCode)
+
+			contracts, err := h.asset.db.ListContracts()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.wantCode != codes.OK {
+				if len(contracts) != 0 {
+					t.Fatalf("stored %d contracts after a failed create",
+						len(contracts))
+				}
+				return
+			}
+
+			if res.Contract.Status != larpc.ContractStatus_CREATED {
+				t.Fatalf("contract is %s", res.Contract.Status)
+			}
+			if res.Contract.AmountDecimal != "10.00" {
+				t.Fatalf("contract is for %s USD", res.Contract.AmountDecimal)
+			}
+			if len(contracts) != 1 || contracts[0].Uuid != res.Contract.Uuid {
+				t.Fatalf("contract was not stored, got %v", contracts)
+			}
+			if _, closed := h.server.Contract(res.Contract.Uuid); closed {
+				t.Fatal("contract is closed at the server")
+			}
+		})
+	}
+}
+
+func TestOpenContract(t *testing.T) {
+	tests := []struct {
+		name       string
+		prepare    func(h *testHarness, uuid string)
+		wantCode   codes.Code
+		wantStatus larpc.ContractStatus
+	}{
+		{
+			name:       "success",
+			wantCode:   codes.OK,
+			wantStatus: larpc.ContractStatus_OPEN,
+		},
+		{
+			name: "server error",
+			prepare: func(h *testHarness, uuid string) {
+				h.clientNode.FailNextPayment("no route to server")
+			},
+			wantCode:   codes.FailedPrecondition,
+			wantStatus: larpc.ContractStatus_OPENING,
+		},
+		{
+			name: "not reconciled",
+			prepare: func(h *testHarness, uuid string) {
+				h.asset.reconciled = newReconciliation()
+			},
+			wantCode:   codes.Unavailable,
+			wantStatus: larpc.ContractStatus_CREATED,
+		},
+		{
+			name: "invalid status: open",
+			prepare: func(h *testHarness, uuid string) {
+				h.setStatus(uuid, larpc.ContractStatus_OPEN)
+			},
+			wantCode:   codes.FailedPrecondition,
+			wantStatus: larpc.ContractStatus_OPEN,
+		},
+		{
+			name: "invalid status: closed",
+			prepare: func(h *testHarness, uuid string) {
+				h.setStatus(uuid, larpc.ContractStatus_CLOSING)
+				h.setStatus(uuid, larpc.ContractStatus_CLOSED)
+			},
+			wantCode:   codes.FailedPrecondition,
+			wantStatus: larpc.ContractStatus_CLOSED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			contract := h.createContract()
+			if test.prepare != nil {
+				test.prepare(h, contract.Uuid)
+			}
+			serverBalance := h.serverNode.Balance()
+
+			_, err := h.rpc.OpenContract(h.ctx, &larpc.ClientOpenContractRequest{
+				Uuid: contract.Uuid,
+			})
+			requireCode(t, err, test.wantCode)
+			h.requireStatus(contract.Uuid, test.wantStatus)
+
+			paid := h.serverNode.Balance() - serverBalance
+			switch {
+			case test.wantCode == codes.OK && paid != contract.AmountSatMargin:
+				t.Fatalf("server was paid %d sats, want the margin of %d sats",
+					paid, contract.AmountSatMargin)
+			case test.wantCode != codes.OK && paid != 0:
+				t.Fatalf("server was paid %d sats by a failed open", paid)
+			}
+		})
+	}
+}
+
+func TestOpenContractRetry(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	contract := h.createContract()
+
+	h.clientNode.FailNextPayment("no route to server")
+	_, err := h.rpc.OpenContract(h.ctx, &larpc.ClientOpenContractRequest{
+		Uuid: contract.Uuid,
+	})
+	requireCode(t, err, codes.FailedPrecondition)
+
+	_, err = h.rpc.OpenContract(h.ctx, &larpc.ClientOpenContractRequest{
+		Uuid: contract.Uuid,
+	})
+	requireCode(t, err, codes.OK)
+	h.requireStatus(contract.Uuid, larpc.ContractStatus_OPEN)
+}
+
+func TestCloseFailedContract(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// expire the margin invoice instead of the initiating invoice
+		expireMargin bool
+		wantCode     codes.Code
+		wantStatus   larpc.ContractStatus
+	}{
+		{
+			name:       "margin paid",
+			wantCode:   codes.OK,
+			wantStatus: larpc.ContractStatus_CLOSED,
+		},
+		{
+			name:         "nothing paid",
+			expireMargin: true,
+			wantCode:     codes.FailedPrecondition,
+			wantStatus:   larpc.ContractStatus_FAILED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			res, err := h.rpc.CreateContract(h.ctx, &larpc.ClientCreateContractRequest{
+				Asset:         "USD",
+				AmountDecimal: "10",
+				ContractType:  larpc.ContractType_FUNDED,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			contract := res.Contract
+
+			expired := contract.InitInvoice
+			if test.expireMargin {
+				expired = contract.MarginInvoice
+			}
+			if err := h.serverNode.ExpireInvoice(expired); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = h.rpc.OpenContract(h.ctx, &larpc.ClientOpenContractRequest{
+				Uuid: contract.Uuid,
+			})
+			if err == nil {
+				t.Fatal("opened a contract with an expired invoice")
+			}
+			h.requireStatus(contract.Uuid, larpc.ContractStatus_FAILED)
+
+			_, err = h.rpc.CloseContract(h.ctx, &larpc.ClientCloseContractRequest{
+				Uuid: contract.Uuid,
+			})
+			requireCode(t, err, test.wantCode)
+			h.requireStatus(contract.Uuid, test.wantStatus)
+
+			_, closed := h.server.Contract(contract.Uuid)
+			if closed != (test.wantCode == codes.OK) {
+				t.Fatalf("contract closed at the server: %v", closed)
+			}
+		})
+	}
+}
+
+func TestCloseContract(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// open the contract before closing it, instead of only creating
+		// it
+		open       bool
+		prepare    func(h *testHarness, uuid string)
+		wantErr    bool
+		wantCode   codes.Code
+		wantStatus larpc.ContractStatus
+	}{
+		{
+			name:       "success",
+			open:       true,
+			wantStatus: larpc.ContractStatus_CLOSED,
+		},
+		{
+			name:       "created contract",
+			open:       false,
+			wantStatus: larpc.ContractStatus_CLOSED,
+		},
+		{
+			name: "server error",
+			open: true,
+			prepare: func(h *testHarness, uuid string) {
+				h.server.FailNext("CloseContract",
+					status.Error(codes.Internal, "database is down"))
+			},
+			wantErr:    true,
+			wantStatus: larpc.ContractStatus_OPEN,
+		},
+		{
+			name: "server error on created contract",
+			open: false,
+			prepare: func(h *testHarness, uuid string) {
+				h.server.FailNext("CloseContract",
+					status.Error(codes.Internal, "database is down"))
+			},
+			wantErr:    true,
+			wantStatus: larpc.ContractStatus_CREATED,
+		},
+		{
+			name: "interrupted close is retried",
+			open: true,
+			prepare: func(h *testHarness, uuid string) {
+				h.setStatus(uuid, larpc.ContractStatus_CLOSING)
+			},
+			wantStatus: larpc.ContractStatus_CLOSED,
+		},
+		{
+			name: "interrupted close fails again",
+			open: true,
+			prepare: func(h *testHarness, uuid string) {
+				h.setStatus(uuid, larpc.ContractStatus_CLOSING)
+				h.server.FailNext("CloseContract",
+					status.Error(codes.Internal, "database is down"))
+			},
+			wantErr:    true,
+			wantStatus: larpc.ContractStatus_OPEN,
+		},
+		{
+			name: "rebalance in progress",
+			open: true,
+			prepare: func(h *testHarness, uuid string) {
+				err := h.asset.db.PutRebalance(&larpc.Rebalance{
+					ContractUuid: uuid,
+					Id:           1,
+					State:        larpc.RebalanceState_REBALANCE_PENDING,
+				})
+				if err != nil {
+					h.t.Fatal(err)
+				}
+			},
+			wantErr:    true,
+			wantCode:   codes.FailedPrecondition,
+			wantStatus: larpc.ContractStatus_OPEN,
+		},
+		{
+			name: "invalid status: closed",
+			open: true,
+			prepare: func(h *testHarness, uuid string) {
+				h.setStatus(uuid, larpc.ContractStatus_CLOSING)
+				h.setStatus(uuid, larpc.ContractStatus_CLOSED)
+			},
+			wantErr:    true,
+			wantCode:   codes.FailedPrecondition,
+			wantStatus: larpc.ContractStatus_CLOSED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			var contract *larpc.ClientContract
+			if test.open {
+				contract = h.openContract()
+			} else {
+				contract = h.createContract()
+			}
+			if test.prepare != nil {
+				test.prepare(h, contract.Uuid)
+			}
+
+			_, err := h.rpc.CloseContract(h.ctx, &larpc.ClientCloseContractRequest{
+				Uuid: contract.Uuid,
+			})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if test.wantCode != codes.OK {
+				requireCode(t, err, test.wantCode)
+			}
+			h.requireStatus(contract.Uuid, test.wantStatus)
+
+			// the server only closes the contract if we succeed
+			_, closed := h.server.Contract(contract.Uuid)
+			if closed == test.wantErr {
+				t.Fatalf("contract closed at the server: %v", closed)
+			}
+		})
+	}
+}
+
+func TestCloseContractKeepsUpdates(t *testing.T) {
+	tests := []struct {
+		name      string
+		serverErr error
+	}{
+		{
+			name: "closed",
+		},
+		{
+			name:      "server error",
+			serverErr: status.Error(codes.Internal, "database is down"),
+		},
+	}
+
+	for _, test := range tests {
+		serverErr := test.serverErr
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			contract := h.openContract()
+
+			// the contract is changed while the server closes it
+			h.server.OnNext("CloseContract", func() error {
+				stored, err := h.asset.db.GetContract(contract.Uuid)
+				if err != nil {
+					return err
+				}
+				stored.AmountSat = 12345
+				if err := h.asset.db.PutContract(stored); err != nil {
+					return err
+				}
+
+				return serverErr
+			})
+
+			_, err := h.rpc.CloseContract(h.ctx, &larpc.ClientCloseContractRequest{
+				Uuid: contract.Uuid,
+			})
+			if (err != nil) != (serverErr != nil) {
+				t.Fatalf("got error %v, want error %v", err, serverErr != nil)
+			}
+
+			stored, err := h.asset.db.GetContract(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.AmountSat != 12345 {
+				t.Fatalf("contract has %d sats, the change was lost",
+					stored.AmountSat)
+			}
+		})
+	}
+}
+
+func TestRequestPayment(t *testing.T) {
+	tests := []struct {
+		name      string
+		prepare   func(h *testHarness)
+		wantCode  codes.Code
+		wantState larpc.RebalanceState
+	}{
+		{
+			name:      "success",
+			wantCode:  codes.OK,
+			wantState: larpc.RebalanceState_REBALANCE_COMPLETED,
+		},
+		{
+			name: "payment fails",
+			prepare: func(h *testHarness) {
+				h.clientNode.FailNextPayment("no route to server")
+			},
+			wantCode:  codes.FailedPrecondition,
+			wantState: larpc.RebalanceState_REBALANCE_FAILED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			contract := h.openContract()
+
+			// the price went up, so we owe the server 20000 sats
+			h.setPrice("USD", money.MustParseDecimal("12500"))
+
+			requestPayment := func() error {
+				invoice, err := h.serverNode.AddInvoice(h.ctx, &lnrpc.Invoice{
+					Value: 20000,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_, err = h.rpc.RequestPayment(h.ctx, &larpc.ClientRequestPaymentRequest{
+					Uuid:   contract.Uuid,
+					PayReq: invoice.PaymentRequest,
+				})
+				return err
+			}
+
+			if test.prepare != nil {
+				test.prepare(h)
+			}
+			requireCode(t, requestPayment(), test.wantCode)
+
+			latest, err := h.asset.db.LatestRebalance(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if latest.State != test.wantState {
+				t.Fatalf("rebalance is %s, want %s", latest.State, test.wantState)
+			}
+			if test.wantCode == codes.OK {
+				return
+			}
+
+			// the failed rebalance does not keep the server from asking
+			// again
+			requireCode(t, requestPayment(), codes.OK)
+
+			stored, err := h.asset.db.GetContract(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.AmountSat != 80000 {
+				t.Fatalf("contract has %d sats, want 80000", stored.AmountSat)
+			}
+		})
+	}
+}
+
+func TestListContracts(t *testing.T) {
+	tests := []struct {
+		name            string
+		includeInactive bool
+		failStore       bool
+		wantCode        codes.Code
+		wantStatuses    []larpc.ContractStatus
+	}{
+		{
+			name:     "success",
+			wantCode: codes.OK,
+			wantStatuses: []larpc.ContractStatus{
+				larpc.ContractStatus_CREATED,
+				larpc.ContractStatus_OPEN,
+			},
+		},
+		{
+			name:            "invalid status: inactive included",
+			includeInactive: true,
+			wantCode:        codes.OK,
+			wantStatuses: []larpc.ContractStatus{
+				larpc.ContractStatus_CREATED,
+				larpc.ContractStatus_OPEN,
+				larpc.ContractStatus_CLOSED,
+			},
+		},
+		{
+			name:      "server error",
+			failStore: true,
+			wantCode:  codes.Unknown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			h.createContract()
+			h.openContract()
+			closed := h.openContract()
+			_, err := h.rpc.CloseContract(h.ctx, &larpc.ClientCloseContractRequest{
+				Uuid: closed.Uuid,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.failStore {
+				h.asset.db = failingStore{Store: h.asset.db, err: errStoreFailed}
+			}
+
+			res, err := h.rpc.ListContracts(h.ctx, &larpc.ClientListContractsRequest{
+				IncludeInactive: test.includeInactive,
+			})
+			requireCode(t, err, test.wantCode)
+			if err != nil {
+				return
+			}
+
+			got := make(map[larpc.ContractStatus]int)
+			for _, contract := range res.Contracts {
+				got[contract.Status]++
+			}
+			if len(res.Contracts) != len(test.wantStatuses) {
+				t.Fatalf("got contracts with statuses %v, want %v", got,
+					test.wantStatuses)
+			}
+			for _, want := range test.wantStatuses {
+				if got[want] != 1 {
+					t.Fatalf("got contracts with statuses %v, want %v", got,
+						test.wantStatuses)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscribeClientContracts(t *testing.T) {
+	tests := []struct {
+		name      string
+		failStore bool
+
+		// what is done after subscribing, and the updates it should cause
+		act         func(h *testHarness, existing *larpc.ClientContract)
+		wantUpdates []larpc.ClientContractUpdate_UpdateType
+		wantCode    codes.Code
+	}{
+		{
+			name: "success",
+			act: func(h *testHarness, existing *larpc.ClientContract) {
+				h.createContract()
+			},
+			wantUpdates: []larpc.ClientContractUpdate_UpdateType{
+				larpc.ClientContractUpdate_SNAPSHOT,
+				larpc.ClientContractUpdate_CREATED,
+			},
+		},
+		{
+			name:      "server error",
+			failStore: true,
+			wantCode:  codes.Unknown,
+		},
+		{
+			// a rejected open does not change the contract, so the
+			// next update is the one of the contract created after it
+			name: "invalid status: failed",
+			act: func(h *testHarness, existing *larpc.ClientContract) {
+				h.setStatus(existing.Uuid, larpc.ContractStatus_FAILED)
+				_, err := h.rpc.OpenContract(h.ctx, &larpc.ClientOpenContractRequest{
+					Uuid: existing.Uuid,
+				})
+				requireCode(h.t, err, codes.FailedPrecondition)
+
+				h.createContract()
+			},
+			wantUpdates: []larpc.ClientContractUpdate_UpdateType{
+				larpc.ClientContractUpdate_SNAPSHOT,
+				larpc.ClientContractUpdate_CREATED,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			existing := h.createContract()
+			if test.failStore {
+				h.asset.db = failingStore{Store: h.asset.db, err: errStoreFailed}
+			}
+
+			ctx, cancel := context.WithTimeout(h.ctx, 5*time.Second)
+			defer cancel()
+
+			// the snapshot is read after subscribing, so once we have
+			// received it we do not miss any updates
+			stream, err := h.rpc.SubscribeClientContracts(ctx,
+				&larpc.ClientSubscribeContractsRequest{IncludeSnapshot: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var updates []*larpc.ClientContractUpdate
+			update, err := stream.Recv()
+			requireCode(t, err, test.wantCode)
+			if err != nil {
+				return
+			}
+			updates = append(updates, update)
+
+			test.act(h, existing)
+
+			for len(updates) < len(test.wantUpdates) {
+				update, err := stream.Recv()
+				if err != nil {
+					t.Fatalf("could not receive update: %v", err)
+				}
+				updates = append(updates, update)
+			}
+
+			for i, want := range test.wantUpdates {
+				if updates[i].Type != want {
+					t.Fatalf("update %d is %s, want %s", i, updates[i].Type, want)
+				}
+			}
+			if updates[0].Contract.Uuid != existing.Uuid {
+				t.Fatalf("snapshot is of contract %s, want %s",
+					updates[0].Contract.Uuid, existing.Uuid)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/util"
+)
+
+const (
+	defaultConfigFilename = "lacd.conf"
+
+	// settings can be given as environment variables, named by the flag
+	// prefixed with this, ie LACD_NETWORK
+	envPrefix = "LACD_"
+)
+
+// where the value of a setting came from, in order of precedence
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+var validNetworks = []string{"regtest", "testnet", "mainnet", "simnet"}
+
+// config is the effective configuration of lacd, and where every value
+// came from
+type config struct {
+	path   string
+	values []*larpc.ConfigValue
+}
+
+// loadConfig applies settings from the environment and the config file to
+// every flag not set on the command line, so flags take precedence over
+// environment variables, which take precedence over the config file. The
+// resulting configuration is validated before it is returned.
+func loadConfig(c *cli.Context) (*config, error) {
+	sources := make(map[string]string)
+	for _, f := range c.App.Flags {
+		if c.IsSet(f.GetName()) {
+			sources[f.GetName()] = sourceFlag
+		}
+	}
+
+	// laddir and the config file can not come from the config file itself
+	for _, name := range []string{flag_laddir, flag_configfile} {
+		if sources[name] != "" {
+			continue
+		}
+		if value, ok := os.LookupEnv(envName(name)); ok {
+			if err := c.Set(name, value); err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", envName(name), value, err)
+			}
+			sources[name] = sourceEnv
+		}
+	}
+
+	configPath := util.CleanAndExpandPath(c.String(flag_configfile))
+	if configPath == "" {
+		configPath = path.Join(util.CleanAndExpandPath(c.String(flag_laddir)),
+			defaultConfigFilename)
+	}
+
+	fileValues, err := readConfigFile(configPath)
+	switch {
+	// only a config file that was asked for explicitly has to exist
+	case os.IsNotExist(err) && c.String(flag_configfile) == "":
+	case err != nil:
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	for name := range fileValues {
+		if name == flag_laddir || name == flag_configfile {
+			return nil, fmt.Errorf("%s can not be set in the config file", name)
+		}
+		if !hasFlag(c, name) {
+			return nil, fmt.Errorf("unknown setting %q in config file", name)
+		}
+	}
+
+	for _, f := range c.App.Flags {
+		name := f.GetName()
+		if sources[name] != "" {
+			continue
+		}
+
+		if value, ok := os.LookupEnv(envName(name)); ok {
+			if err := c.Set(name, value); err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", envName(name), value, err)
+			}
+			sources[name] = sourceEnv
+			continue
+		}
+
+		if value, ok := fileValues[name]; ok {
+			if err := c.Set(name, value); err != nil {
+				return nil, fmt.Errorf("invalid %s %q in config file: %w", name, value, err)
+			}
+			sources[name] = sourceFile
+			continue
+		}
+
+		sources[name] = sourceDefault
+	}
+
+	if err := validateConfig(c); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	cfg := &config{path: configPath}
+	for _, f := range c.App.Flags {
+		cfg.values = append(cfg.values, &larpc.ConfigValue{
+			Name:   f.GetName(),
+			Value:  redact(flagValue(c, f)),
+			Source: sources[f.GetName()],
+		})
+	}
+
+	sort.Slice(cfg.values, func(i, j int) bool {
+		return cfg.values[i].Name < cfg.values[j].Name
+	})
+
+	return cfg, nil
+}
+
+func (a AssetClient) GetConfig(ctx context.Context, req *larpc.ClientGetConfigRequest) (*larpc.ClientGetConfigResponse, error) {
+	log.Infoln("received get config request")
+
+	return &larpc.ClientGetConfigResponse{
+		ConfigFile: a.config.path,
+		Values:     a.config.values,
+	}, nil
+}
+
+// readConfigFile reads settings from an INI style file, with one
+// `name = value` per line. Comments start with # or ;, and section headers
+// are ignored.
+func readConfigFile(configPath string) (map[string]string, error) {
+	file, err := os.Open(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") ||
+			strings.HasPrefix(line, ";") || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected name = value", configPath, lineNum)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		if _, ok := values[name]; ok {
+			return nil, fmt.Errorf("%s:%d: %s is set twice", configPath, lineNum, name)
+		}
+
+		values[name] = strings.Trim(strings.TrimSpace(parts[1]), `"`)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// validateConfig checks every setting that can be invalid
+func validateConfig(c *cli.Context) error {
+	network := c.String(flag_network)
+	if !contains(validNetworks, network) {
+		return fmt.Errorf("%s must be one of %s, got %q", flag_network,
+			strings.Join(validNetworks, " | "), network)
+	}
+
+	for _, name := range []string{flag_port, flag_rest_port} {
+		if port := c.Int(name); port < 1 || port > 65535 {
+			return fmt.Errorf("%s must be between 1 and 65535, got %d", name, port)
+		}
+	}
+	if c.Int(flag_port) == c.Int(flag_rest_port) {
+		return fmt.Errorf("%s and %s can not be the same", flag_port, flag_rest_port)
+	}
+
+	for _, name := range []string{flag_netaddress, flag_serveraddress, flag_lndrpchost} {
+		// without a net address the server reaches us over the push channel
+		if name == flag_netaddress && c.String(name) == "" {
+			continue
+		}
+		if err := validateHostPort(c.String(name)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if pubkey := c.String(flag_serverpubkey); pubkey != "" {
+		if err := validatePubkey(pubkey); err != nil {
+			return fmt.Errorf("%s: %w", flag_serverpubkey, err)
+		}
+	}
+
+	priceServer, err := url.Parse(c.String(flag_priceserver_address))
+	if err != nil || (priceServer.Scheme != "http" && priceServer.Scheme != "https") ||
+		priceServer.Host == "" {
+		return fmt.Errorf("%s must be a http(s) url, got %q",
+			flag_priceserver_address, c.String(flag_priceserver_address))
+	}
+
+	for _, name := range []string{flag_rebalancefrequency, flag_expiredretention,
+		flag_maxfeesat, flag_outgoingchanid} {
+		if c.Int(name) < 0 {
+			return fmt.Errorf("%s can not be negative", name)
+		}
+	}
+
+	for _, name := range []string{flag_paymenttolerance, flag_maxpricedeviation,
+		flag_maxfeepercent} {
+		if c.Float64(name) < 0 {
+			return fmt.Errorf("%s can not be negative", name)
+		}
+	}
+	if c.Float64(flag_maxmarginpercent) <= 0 {
+		return fmt.Errorf("%s must be positive", flag_maxmarginpercent)
+	}
+	for _, name := range []string{flag_paymenttimeout, flag_paymentattempts} {
+		if c.Int(name) < 1 {
+			return fmt.Errorf("%s must be at least 1", name)
+		}
+	}
+	if c.Int(flag_outgoingchanid) != 0 && c.Bool(flag_pinserverchannel) {
+		return fmt.Errorf("%s and %s can not be combined", flag_outgoingchanid,
+			flag_pinserverchannel)
+	}
+
+	for _, name := range []string{flag_laddir, flag_lnddir} {
+		if c.String(name) == "" {
+			return fmt.Errorf("%s can not be empty", name)
+		}
+	}
+
+	return nil
+}
+
+// validateHostPort checks that address is a host:port with a valid port
+func validateHostPort(address string) error {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		return fmt.Errorf("missing host in %q", address)
+	}
+
+	port, err := strconv.Atoi(portString)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port in %q", address)
+	}
+
+	return nil
+}
+
+// flagValue returns the value of a flag as a string
+func flagValue(c *cli.Context, f cli.Flag) string {
+	name := f.GetName()
+
+	switch f.(type) {
+	case cli.IntFlag:
+		return strconv.Itoa(c.Int(name))
+	case cli.Float64Flag:
+		return strconv.FormatFloat(c.Float64(name), 'f', -1, 64)
+	case cli.BoolFlag:
+		return strconv.FormatBool(c.Bool(name))
+	case cli.BoolTFlag:
+		return strconv.FormatBool(c.BoolT(name))
+	}
+
+	return c.String(name)
+}
+
+// redact hides passwords in urls, so the configuration can be shown to
+// anyone allowed to read it
+func redact(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+
+	return u.String()
+}
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(flagName)
+}
+
+func hasFlag(c *cli.Context, name string) bool {
+	for _, f := range c.App.Flags {
+		if f.GetName() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli"
+)
+
+// runLoadConfig loads the configuration of lacd started with args, with
+// laddir in a temporary directory that has the given config file
+func runLoadConfig(t *testing.T, configFile string, args ...string) (*config, error) {
+	dir, err := ioutil.TempDir("", "lacconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if configFile != "" {
+		err := ioutil.WriteFile(filepath.Join(dir, defaultConfigFilename),
+			[]byte(configFile), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cfg *config
+	app := newApp()
+	app.Action = func(c *cli.Context) error {
+		cfg, err = loadConfig(c)
+		return err
+	}
+
+	args = append([]string{"lacd", "--" + flag_laddir + "=" + dir}, args...)
+	if err := app.Run(args); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		env        string
+		configFile string
+		wantValue  string
+		wantSource string
+		wantErr    bool
+	}{
+		{
+			name:       "default",
+			wantValue:  defaultNetwork,
+			wantSource: sourceDefault,
+		},
+		{
+			name:       "file",
+			configFile: "network = testnet\n",
+			wantValue:  "testnet",
+			wantSource: sourceFile,
+		},
+		{
+			name:       "env over file",
+			env:        "mainnet",
+			configFile: "network = testnet\n",
+			wantValue:  "mainnet",
+			wantSource: sourceEnv,
+		},
+		{
+			name:       "flag over env and file",
+			args:       []string{"--network=simnet"},
+			env:        "mainnet",
+			configFile: "network = testnet\n",
+			wantValue:  "simnet",
+			wantSource: sourceFlag,
+		},
+		{
+			name:    "invalid network",
+			env:     "mainnet",
+			args:    []string{"--network=signet"},
+			wantErr: true,
+		},
+		{
+			name:       "unknown setting in file",
+			configFile: "netwrok = testnet\n",
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			os.Unsetenv(envName(flag_network))
+			if test.env != "" {
+				os.Setenv(envName(flag_network), test.env)
+			}
+			defer os.Unsetenv(envName(flag_network))
+
+			cfg, err := runLoadConfig(t, test.configFile, test.args...)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, value := range cfg.values {
+				if value.Name != flag_network {
+					continue
+				}
+
+				if value.Value != test.wantValue || value.Source != test.wantSource {
+					t.Fatalf("got %s from %s, want %s from %s", value.Value,
+						value.Source, test.wantValue, test.wantSource)
+				}
+				return
+			}
+			t.Fatalf("%s is missing from the configuration", flag_network)
+		})
+	}
+}
+
+func TestNetworkUsage(t *testing.T) {
+	for _, f := range newApp().Flags {
+		flag, ok := f.(cli.StringFlag)
+		if !ok || flag.Name != flag_network {
+			continue
+		}
+
+		for _, network := range validNetworks {
+			if !strings.Contains(flag.Usage, network) {
+				t.Errorf("usage %q does not list %s", flag.Usage, network)
+			}
+		}
+		return
+	}
+
+	t.Fatalf("no %s flag", flag_network)
+}
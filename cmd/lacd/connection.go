@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// connectionState tracks the state of a connection we keep retrying, so
+// operations that need it can be rejected while it is down
+type connectionState struct {
+	// what we are connected to, used in logs and errors
+	name    string
+	address string
+
+	mu            sync.Mutex
+	state         larpc.ConnectionState
+	since         time.Time
+	lastConnected time.Time
+	lastError     error
+
+	// closed and replaced every time the state changes
+	changed chan struct{}
+}
+
+func newConnectionState(name, address string) *connectionState {
+	return &connectionState{
+		name:    name,
+		address: address,
+		state:   larpc.ConnectionState_CONNECTING,
+		since:   time.Now(),
+		changed: make(chan struct{}),
+	}
+}
+
+// setState updates the state of the connection. err is why the connection
+// is not usable, and is nil if it is.
+func (c *connectionState) setState(state larpc.ConnectionState, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.lastError = err
+	}
+	if state == larpc.ConnectionState_CONNECTED {
+		c.lastConnected = time.Now()
+	}
+
+	if state == c.state {
+		return
+	}
+
+	switch state {
+	case larpc.ConnectionState_CONNECTED:
+		log.WithField("address", c.address).Infof("connected to %s", c.name)
+	case larpc.ConnectionState_NOT_READY:
+		log.WithError(err).WithField("address", c.address).
+			Warnf("%s is not ready", c.name)
+	case larpc.ConnectionState_DISCONNECTED:
+		log.WithError(err).WithField("address", c.address).
+			Warnf("%s is unavailable, reconnecting", c.name)
+	}
+
+	c.state = state
+	c.since = time.Now()
+
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// ready returns an Unavailable error if the connection is known to be
+// unusable. While we are still connecting requests are let through, and wait
+// for the connection.
+func (c *connectionState) ready() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case larpc.ConnectionState_DISCONNECTED:
+		return status.Errorf(codes.Unavailable, "%s is unavailable: %v",
+			c.name, c.lastError)
+	case larpc.ConnectionState_NOT_READY:
+		return status.Errorf(codes.Unavailable, "%s is not ready: %v",
+			c.name, c.lastError)
+	}
+
+	return nil
+}
+
+// waitConnected blocks until the connection is usable, or ctx is canceled
+func (c *connectionState) waitConnected(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		state, changed := c.state, c.changed
+		c.mu.Unlock()
+
+		if state == larpc.ConnectionState_CONNECTED {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// status returns the current state of the connection
+func (c *connectionState) status() *larpc.ConnectionStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := &larpc.ConnectionStatus{
+		Address: c.address,
+		State:   c.state,
+		Since:   c.since.Unix(),
+	}
+	if !c.lastConnected.IsZero() {
+		res.LastConnected = c.lastConnected.Unix()
+	}
+	if c.lastError != nil {
+		res.LastError = c.lastError.Error()
+	}
+
+	return res
+}
+
+func (a AssetClient) GetStatus(ctx context.Context, req *larpc.ClientGetStatusRequest) (*larpc.ClientGetStatusResponse, error) {
+	log.Infoln("received get status request")
+
+	return &larpc.ClientGetStatusResponse{
+		Server: a.server.status(),
+		Lnd:    a.lnd.status(),
+	}, nil
+}
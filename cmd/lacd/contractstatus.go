@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// validTransitions lists the statuses a contract can move to from each
+// status. All status changes go through transition, which enforces this.
+var validTransitions = map[larpc.ContractStatus][]larpc.ContractStatus{
+	larpc.ContractStatus_CREATED: {
+		larpc.ContractStatus_OPENING,
+		larpc.ContractStatus_CLOSING,
+		larpc.ContractStatus_EXPIRED,
+		larpc.ContractStatus_FAILED,
+	},
+	larpc.ContractStatus_OPENING: {
+		larpc.ContractStatus_OPEN,
+		larpc.ContractStatus_FAILED,
+	},
+	larpc.ContractStatus_OPEN: {
+		larpc.ContractStatus_CLOSING,
+	},
+	// if the server refuses to close the contract, it goes back to the
+	// status it had before
+	larpc.ContractStatus_CLOSING: {
+		larpc.ContractStatus_CLOSED,
+		larpc.ContractStatus_OPEN,
+		larpc.ContractStatus_CREATED,
+		larpc.ContractStatus_FAILED,
+	},
+	larpc.ContractStatus_CLOSED:  {},
+	larpc.ContractStatus_FAILED:  {},
+	larpc.ContractStatus_EXPIRED: {},
+}
+
+// canTransition returns true if a contract can move from one status to
+// another
+func canTransition(from, to larpc.ContractStatus) bool {
+	for _, status := range validTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canCloseFailed returns true if the contract failed to open after its
+// margin was paid. The server holds the margin, so the contract must be
+// closed to get it back.
+func canCloseFailed(contract *larpc.ClientContract) bool {
+	return contract.Status == larpc.ContractStatus_FAILED && contract.MarginPaid
+}
+
+// transition moves the contract to a new status, and records when and why
+// it happened. It returns an error if the transition is not allowed.
+func transition(contract *larpc.ClientContract, to larpc.ContractStatus, reason string) error {
+	closingFailed := to == larpc.ContractStatus_CLOSING && canCloseFailed(contract)
+	if !canTransition(contract.Status, to) && !closingFailed {
+		return fmt.Errorf("contract %s can not go from %s to %s",
+			contract.Uuid, contract.Status, to)
+	}
+
+	log.WithField("uuid", contract.Uuid).
+		Infof("contract status %s -> %s", contract.Status, to)
+
+	contract.Status = to
+	contract.StatusHistory = append(contract.StatusHistory, &larpc.ContractStatusChange{
+		Status:    to,
+		Timestamp: time.Now().Unix(),
+		Reason:    reason,
+	})
+
+	return nil
+}
+
+// isActive returns true if the contract has not reached a final status
+func isActive(contract *larpc.ClientContract) bool {
+	return len(validTransitions[contract.Status]) > 0 || canCloseFailed(contract)
+}
+
+// previousStatus returns the status the contract had before its current
+// status, according to its history
+func previousStatus(contract *larpc.ClientContract) larpc.ContractStatus {
+	history := contract.StatusHistory
+	if len(history) < 2 {
+		return larpc.ContractStatus_CREATED
+	}
+
+	return history[len(history)-2].Status
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+const (
+	// how often we look for expired contracts
+	defaultExpiryCheckInterval = 1 * time.Minute
+
+	// contracts are not opened this close to their expiry, as the invoices
+	// could expire while we are paying them
+	openExpiryMargin = 30 * time.Second
+)
+
+// invoiceExpiry returns when an invoice expires
+func invoiceExpiry(payReq *lnrpc.PayReq) time.Time {
+	return time.Unix(payReq.Timestamp+payReq.Expiry, 0)
+}
+
+// expiresSoon returns true if the contract can no longer be opened, as its
+// invoices have expired or are about to
+func expiresSoon(contract *larpc.ClientContract) bool {
+	if contract.ExpiresAt == 0 {
+		return false
+	}
+
+	return !time.Now().Add(openExpiryMargin).Before(time.Unix(contract.ExpiresAt, 0))
+}
+
+// errContractExpired is returned when opening a contract whose invoices
+// have expired. A new contract has to be created, with a fresh quote.
+func errContractExpired(contract *larpc.ClientContract) error {
+	return status.Errorf(codes.FailedPrecondition,
+		"contract %s expires at %s and can no longer be opened, create a new contract to get a fresh quote",
+		contract.Uuid, time.Unix(contract.ExpiresAt, 0).Format(time.RFC3339))
+}
+
+// expireContract marks a contract that was never opened as expired
+func (a AssetClient) expireContract(contract *larpc.ClientContract) error {
+	err := transition(contract, larpc.ContractStatus_EXPIRED,
+		"invoices expired before the contract was opened")
+	if err != nil {
+		return err
+	}
+
+	return saveContract(a.db, a.contractNotifier,
+		larpc.ClientContractUpdate_EXPIRED, *contract)
+}
+
+// runContractJanitor expires contracts that were never opened, and removes
+// expired contracts once they are older than retention, until ctx is
+// canceled. A retention of 0 keeps expired contracts forever.
+func (a AssetClient) runContractJanitor(ctx context.Context, interval,
+	retention time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.expireContracts(ctx); err != nil {
+			log.WithError(err).Error("could not expire contracts")
+		}
+
+		if retention > 0 {
+			if err := a.removeExpiredContracts(retention); err != nil {
+				log.WithError(err).Error("could not remove expired contracts")
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// expireContracts marks every created contract whose invoices have expired
+// as expired
+func (a AssetClient) expireContracts(ctx context.Context) error {
+	contracts, err := a.db.ListContracts()
+	if err != nil {
+		return err
+	}
+
+	for _, contract := range contracts {
+		if contract.Status != larpc.ContractStatus_CREATED {
+			continue
+		}
+
+		// contracts created before we tracked expiry get it from their
+		// invoice
+		if contract.ExpiresAt == 0 {
+			payReq, err := a.lncli.DecodePayReq(ctx, &lnrpc.PayReqString{
+				PayReq: contract.MarginInvoice,
+			})
+			if err != nil {
+				log.WithError(err).WithField("uuid", contract.Uuid).
+					Error("could not decode margin invoice")
+				continue
+			}
+			contract.ExpiresAt = invoiceExpiry(payReq).Unix()
+		}
+
+		if time.Now().Unix() < contract.ExpiresAt {
+			continue
+		}
+
+		if err := a.expireContract(contract); err != nil {
+			return fmt.Errorf("could not expire contract %s: %w", contract.Uuid, err)
+		}
+	}
+
+	return nil
+}
+
+// removeExpiredContracts deletes contracts that have been expired for longer
+// than retention. Expired contracts were never opened, so there are no
+// payments or rebalances to keep.
+func (a AssetClient) removeExpiredContracts(retention time.Duration) error {
+	contracts, err := a.db.ListContracts()
+	if err != nil {
+		return err
+	}
+
+	for _, contract := range contracts {
+		if contract.Status != larpc.ContractStatus_EXPIRED {
+			continue
+		}
+
+		expiredAt := time.Unix(statusChangedAt(contract), 0)
+		if time.Since(expiredAt) < retention {
+			continue
+		}
+
+		if err := a.db.DeleteContract(contract.Uuid); err != nil {
+			return err
+		}
+
+		log.WithField("uuid", contract.Uuid).Info("removed expired contract")
+		notifyContract(a.contractNotifier, larpc.ClientContractUpdate_REMOVED,
+			*contract)
+	}
+
+	return nil
+}
+
+// statusChangedAt returns the unix timestamp of when the contract entered
+// its current status
+func statusChangedAt(contract *larpc.ClientContract) int64 {
+	for i := len(contract.StatusHistory) - 1; i >= 0; i-- {
+		if contract.StatusHistory[i].Status == contract.Status {
+			return contract.StatusHistory[i].Timestamp
+		}
+	}
+
+	return 0
+}
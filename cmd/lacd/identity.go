@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+)
+
+// serverIdentity pins the lightning node of the asset server, so we only
+// pay invoices created by it. The node is either configured, or pinned from
+// the first invoice the server sends us.
+type serverIdentity struct {
+	db      store.Store
+	address string
+
+	// the pubkey given in the config, which takes precedence over a
+	// pinned one
+	configured string
+
+	// serializes pinning on first use
+	mu sync.Mutex
+}
+
+// newServerIdentity creates the identity of the server at address. If no
+// node is configured or pinned yet, but we already have contracts with the
+// server, the node of the first contract is pinned, as that is the node we
+// first trusted.
+func newServerIdentity(db store.Store, address, configured string) (*serverIdentity, error) {
+	s := &serverIdentity{
+		db:         db,
+		address:    address,
+		configured: configured,
+	}
+
+	if configured != "" {
+		return s, nil
+	}
+
+	// after a reset we wait for the next invoice, even if we have contracts
+	pinned, err := getServerIdentity(db)
+	if err != nil || pinned != nil {
+		return s, err
+	}
+
+	contracts, err := db.ListContracts()
+	if err != nil {
+		return nil, err
+	}
+
+	var first *larpc.ClientContract
+	for _, contract := range contracts {
+		if contract.ServerPubkey == "" {
+			continue
+		}
+		if first == nil || createdAt(contract) < createdAt(first) {
+			first = contract
+		}
+	}
+	if first == nil {
+		return s, nil
+	}
+
+	log.WithField("pubkey", first.ServerPubkey).
+		Info("pinning server node of existing contracts")
+
+	return s, s.pin(first.ServerPubkey)
+}
+
+// createdAt returns the unix timestamp of when the contract was created, or
+// 0 if the contract is older than its status history
+func createdAt(contract *larpc.ClientContract) int64 {
+	if len(contract.StatusHistory) == 0 {
+		return 0
+	}
+
+	return contract.StatusHistory[0].Timestamp
+}
+
+// get returns the identity of the server, or nil if it is not known yet
+func (s *serverIdentity) get() (*larpc.ServerIdentity, error) {
+	if s.configured != "" {
+		return &larpc.ServerIdentity{
+			Pubkey:        s.configured,
+			Source:        larpc.IdentitySource_CONFIGURED,
+			ServerAddress: s.address,
+		}, nil
+	}
+
+	identity, err := getServerIdentity(s.db)
+	if err != nil || identity.GetPubkey() == "" {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// verify checks that pubkey, which created an invoice or signed a response,
+// is the node of the server, and returns a PermissionDenied error if not. If
+// we do not know the node of the server yet, pubkey is pinned as it.
+func (s *serverIdentity) verify(pubkey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	identity, err := s.get()
+	if err != nil {
+		return err
+	}
+
+	if identity == nil {
+		log.WithField("pubkey", pubkey).
+			Warn("pinning server node on first use")
+		return s.pin(pubkey)
+	}
+
+	if identity.Pubkey != pubkey {
+		return &wrongNodeError{got: pubkey, want: identity.Pubkey}
+	}
+
+	return nil
+}
+
+// wrongNodeError is returned when an invoice or a signature is not from the
+// node of the server. rpcs return it as a PermissionDenied status.
+type wrongNodeError struct {
+	got  string
+	want string
+}
+
+func (e *wrongNodeError) Error() string {
+	return fmt.Sprintf("got node %s, but the server node is %s. If the "+
+		"server has changed its node, reset the pinned node with "+
+		"laccli resetserveridentity", e.got, e.want)
+}
+
+// GRPCStatus is used by grpc to turn the error into a status
+func (e *wrongNodeError) GRPCStatus() *status.Status {
+	return status.New(codes.PermissionDenied, e.Error())
+}
+
+// pin stores pubkey as the node of the server
+func (s *serverIdentity) pin(pubkey string) error {
+	identity := larpc.ServerIdentity{
+		Pubkey:        pubkey,
+		Source:        larpc.IdentitySource_TRUST_ON_FIRST_USE,
+		PinnedAt:      time.Now().Unix(),
+		ServerAddress: s.address,
+	}
+
+	return s.db.PutServerIdentity(&identity)
+}
+
+// reset forgets the pinned node of the server, and returns it
+func (s *serverIdentity) reset() (*larpc.ServerIdentity, error) {
+	if s.configured != "" {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"the server node is configured with --%s, and can not be reset",
+			flag_serverpubkey)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+
+	// an empty identity is kept, so we do not pin the node of our existing
+	// contracts again when we are restarted
+	if err := s.db.PutServerIdentity(&larpc.ServerIdentity{}); err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// getServerIdentity reads the pinned identity of the server from the
+// database, or nil if none was ever pinned. The identity has no pubkey if it
+// was reset.
+func getServerIdentity(db store.Store) (*larpc.ServerIdentity, error) {
+	identity, err := db.GetServerIdentity()
+	if err != nil {
+		return nil, fmt.Errorf("could not read server identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// validatePubkey checks that pubkey is a hex encoded compressed public key
+func validatePubkey(pubkey string) error {
+	decoded, err := hex.DecodeString(pubkey)
+	if err != nil {
+		return fmt.Errorf("not hex encoded: %w", err)
+	}
+	if len(decoded) != 33 || (decoded[0] != 0x02 && decoded[0] != 0x03) {
+		return fmt.Errorf("not a compressed public key")
+	}
+
+	return nil
+}
+
+func (a AssetClient) GetServerIdentity(ctx context.Context, req *larpc.ClientGetServerIdentityRequest) (*larpc.ClientGetServerIdentityResponse, error) {
+	log.Infoln("received get server identity request")
+
+	identity, err := a.identity.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return &larpc.ClientGetServerIdentityResponse{
+		Identity: identity,
+	}, nil
+}
+
+func (a AssetClient) ResetServerIdentity(ctx context.Context, req *larpc.ClientResetServerIdentityRequest) (*larpc.ClientResetServerIdentityResponse, error) {
+	log.Infoln("received reset server identity request")
+
+	previous, err := a.identity.reset()
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("pubkey", previous.GetPubkey()).Warn("reset server node")
+
+	return &larpc.ClientResetServerIdentityResponse{
+		Previous: previous,
+	}, nil
+}
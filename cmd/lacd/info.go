@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/ArcaneCryptoAS/lassets-client/build"
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/oracle"
+)
+
+func (a AssetClient) GetInfo(ctx context.Context, req *larpc.ClientGetInfoRequest) (*larpc.ClientGetInfoResponse, error) {
+	log.Infoln("received get info request")
+
+	res := &larpc.ClientGetInfoResponse{
+		Version:       build.Version(),
+		Network:       a.network,
+		ServerAddress: a.server.address,
+	}
+
+	if info := a.lnd.latestInfo(); info != nil {
+		res.LndAlias = info.Alias
+		res.LndPubkey = info.IdentityPubkey
+		res.LndVersion = info.Version
+	}
+
+	contracts, err := a.db.ListContracts()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contract := range contracts {
+		if contract.Status == larpc.ContractStatus_OPEN {
+			res.NumOpenContracts++
+			res.TotalContractSat += contract.AmountSat
+		}
+		if isActive(contract) && contract.MarginPaid {
+			res.TotalMarginSat += contract.AmountSatMargin
+		}
+	}
+
+	for asset, price := range a.oracle.Prices() {
+		// the oracle tells us whether it considers the price too old
+		_, err := a.oracle.Price(asset)
+
+		res.Prices = append(res.Prices, &larpc.PriceInfo{
+			Asset:      asset,
+			Value:      price.Value.Float64(),
+			Timestamp:  price.Timestamp.Unix(),
+			AgeSeconds: int64(price.Age() / time.Second),
+			Stale:      errors.Is(err, oracle.ErrStalePrice),
+
+			ValueDecimal: price.Value.String(),
+		})
+	}
+
+	sort.Slice(res.Prices, func(i, j int) bool {
+		return res.Prices[i].Asset < res.Prices[j].Asset
+	})
+
+	return res, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/util"
+)
+
+const (
+	// how often we check that lnd is still ready while it is
+	lndCheckInterval = 15 * time.Second
+	lndCheckTimeout  = 10 * time.Second
+
+	// how often we check lnd while it is not ready
+	lndMinBackoff = 1 * time.Second
+	lndMaxBackoff = 30 * time.Second
+)
+
+// lndConnection tracks whether lnd can be used. lnd is ready once it is
+// reachable, unlocked and synced to chain, and we keep checking that it
+// stays that way.
+type lndConnection struct {
+	*connectionState
+
+	client lnrpc.LightningClient
+	router routerrpc.RouterClient
+
+	// the latest info we got from lnd, nil until we have reached it. It is
+	// guarded by the lock of the connection state.
+	info *lnrpc.GetInfoResponse
+}
+
+// connectToLnd connects to lnd, waiting for its certificate and macaroon
+// to be created if lnd has not been started or initialized yet
+func connectToLnd(ctx context.Context, lndDir, lndHost,
+	network string) (*lndConnection, error) {
+
+	backoff := lndMinBackoff
+
+	for {
+		conn, err := util.ConnectToLnd(lndDir, lndHost, network)
+		if err == nil {
+			return &lndConnection{
+				connectionState: newConnectionState("lnd", lndHost),
+				client:          lnrpc.NewLightningClient(conn),
+				router:          routerrpc.NewRouterClient(conn),
+			}, nil
+		}
+
+		log.WithError(err).Warnf("waiting for lnd, retrying in %s", backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > lndMaxBackoff {
+			backoff = lndMaxBackoff
+		}
+	}
+}
+
+// monitor keeps the state of the connection up to date until ctx is
+// canceled. lnd is checked often while it is not ready, so we notice as
+// soon as it is.
+func (l *lndConnection) monitor(ctx context.Context) {
+	backoff := lndMinBackoff
+
+	for {
+		l.check(ctx)
+
+		interval := lndCheckInterval
+		if l.ready() != nil {
+			interval = backoff
+
+			backoff *= 2
+			if backoff > lndMaxBackoff {
+				backoff = lndMaxBackoff
+			}
+		} else {
+			backoff = lndMinBackoff
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check asks lnd whether it is unlocked and synced to chain
+func (l *lndConnection) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, lndCheckTimeout)
+	defer cancel()
+
+	info, err := l.client.GetInfo(ctx, &lnrpc.GetInfoRequest{})
+	if err == nil {
+		l.mu.Lock()
+		l.info = info
+		l.mu.Unlock()
+	}
+
+	switch {
+	case ctx.Err() != nil && ctx.Err() != context.DeadlineExceeded:
+		// we are shutting down
+
+	// a locked lnd only serves the wallet unlocker
+	case status.Code(err) == codes.Unimplemented:
+		l.setState(larpc.ConnectionState_NOT_READY,
+			errors.New("wallet is locked"))
+
+	case err != nil:
+		l.setState(larpc.ConnectionState_DISCONNECTED, err)
+
+	case !info.SyncedToChain:
+		l.setState(larpc.ConnectionState_NOT_READY,
+			fmt.Errorf("not synced to chain, at block %d", info.BlockHeight))
+
+	default:
+		l.setState(larpc.ConnectionState_CONNECTED, nil)
+	}
+}
+
+// latestInfo returns the latest info we got from lnd, or nil if we have not
+// reached lnd yet
+func (l *lndConnection) latestInfo() *lnrpc.GetInfoResponse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.info
+}
+
+// nodePubkey returns the identity pubkey of lnd, or an empty string if we
+// have not reached lnd yet
+func (l *lndConnection) nodePubkey() string {
+	return l.latestInfo().GetIdentityPubkey()
+}
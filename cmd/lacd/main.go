@@ -2,54 +2,73 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"path"
+	"strings"
 	"time"
 
-	"github.com/gorilla/mux"
-	"github.com/improbable-eng/grpc-web/go/grpcweb"
-	"google.golang.org/grpc/credentials"
-
 	"github.com/ArcaneCryptoAS/lassets-client/util"
 
-	"github.com/boltdb/bolt"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 	"google.golang.org/grpc"
 
 	"github.com/ArcaneCryptoAS/lassets-client/build"
 	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+	"github.com/ArcaneCryptoAS/lassets-client/oracle"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
 )
 
 var (
-	contractsBucket = []byte("contracts")
-	defaultDBName   = "laclient.db"
+	defaultDBName = "laclient.db"
 )
 
 var (
 	defaultClientPort         = 10456
 	defaultRestPort           = 8081
+	defaultRebalanceFrequency = 60
 	defaultClientDir          = util.CleanAndExpandPath("~/.lac")
 	defaultNetwork            = "regtest"
-	defaultNetAddress         = "localhost:10456"
 	defaultPriceserverAddress = "http://127.0.0.1:3001"
 
 	defaultLndDir     = util.CleanAndExpandPath("~/.lnd")
 	defaultLndRPCPort = "localhost:10011"
 
 	defaultServerAddress = "lightningassets.arcane.no:10455"
-)
 
-var (
-	// here we store the latest prices
-	price = map[string]float64{
-		"USD": 0.00,
-		"NOK": 0.00,
-	}
+	// prices older than this are not used when creating contracts
+	defaultMaxPriceAge = 1 * time.Minute
+
+	// how many percent payments requested by the server may differ from
+	// what we calculate we owe
+	defaultPaymentTolerance = 1.0
+
+	// how many percent the price of the server may differ from ours when
+	// creating contracts
+	defaultMaxPriceDeviation = 2.0
+
+	// the highest margin we pay, in percent of the contract amount
+	defaultMaxMarginPercent = 50.0
+
+	// rebalances smaller than this amount of sats are postponed
+	defaultMinRebalanceAmount int64 = 10
+
+	// how long contracts that expired before they were opened are kept
+	defaultExpiredRetention = 7 * 24
+
+	// the highest routing fee we pay, the lower of the two applies
+	defaultMaxFeeSat     = 1000
+	defaultMaxFeePercent = 1.0
+
+	// how many seconds a single attempt to pay an invoice may take
+	defaultPaymentTimeout = 60
+
+	// how many times we try to pay an invoice before giving up
+	defaultPaymentAttempts = 3
 )
 
 // define possible flag names here
@@ -65,11 +84,34 @@ const (
 	flag_priceserver_address = "priceserver_address"
 	flag_serveraddress       = "serveraddress"
 	flag_insecureserver      = "insecureserver"
+	flag_paymenttolerance    = "paymenttolerance"
+	flag_maxpricedeviation   = "maxpricedeviation"
+	flag_maxmarginpercent    = "maxmarginpercent"
+	flag_nomacaroons         = "nomacaroons"
+	flag_configfile          = "configfile"
+	flag_expiredretention    = "expiredretention"
+	flag_maxfeesat           = "maxfeesat"
+	flag_maxfeepercent       = "maxfeepercent"
+	flag_paymenttimeout      = "paymenttimeout"
+	flag_outgoingchanid      = "outgoingchanid"
+	flag_pinserverchannel    = "pinserverchannel"
+	flag_paymentattempts     = "paymentattempts"
+	flag_serverpubkey        = "serverpubkey"
 )
 
 var log = logrus.New()
 
 func main() {
+	app := newApp()
+	app.Action = runClient
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatalf("[lad]: %v", err)
+	}
+}
+
+// newApp creates the command line app of lacd, with all of its flags
+func newApp() *cli.App {
 	app := cli.NewApp()
 	app.Name = "ladclient"
 	app.Version = build.Version()
@@ -91,19 +133,28 @@ func main() {
 			Usage: "the location of lad dir",
 			Value: defaultClientDir,
 		},
+		cli.StringFlag{
+			Name:  flag_configfile,
+			Usage: "path to the config file, defaults to " + defaultConfigFilename + " in laddir",
+		},
 		cli.StringFlag{
 			Name:  flag_network,
-			Usage: "which bitcoin network to run on, regtest | testnet | mainnet",
+			Usage: "which bitcoin network to run on, " + strings.Join(validNetworks, " | "),
 			Value: defaultNetwork,
 		},
 		cli.IntFlag{
 			Name:  flag_rebalancefrequency,
-			Usage: "how often to rebalance channels",
+			Usage: "how often to rebalance contracts, in seconds. 0 disables rebalancing",
+			Value: defaultRebalanceFrequency,
+		},
+		cli.IntFlag{
+			Name:  flag_expiredretention,
+			Usage: "how many hours contracts that expired before they were opened are kept. 0 keeps them forever",
+			Value: defaultExpiredRetention,
 		},
 		cli.StringFlag{
 			Name:  flag_netaddress,
-			Usage: "our ip-address, that other hosts can reach us at",
-			Value: defaultNetAddress,
+			Usage: "the host:port the asset server can reach us at. If empty, the server reaches us over a push channel we open to it, so we do not need to be reachable",
 		},
 		cli.StringFlag{
 			Name:  flag_priceserver_address,
@@ -115,10 +166,61 @@ func main() {
 			Usage: "the host:port the asset server is running on",
 			Value: defaultServerAddress,
 		},
+		cli.StringFlag{
+			Name:  flag_serverpubkey,
+			Usage: "the pubkey of the lightning node of the asset server. If empty, the node that creates the first invoice we get from the server is pinned",
+		},
 		cli.BoolTFlag{
 			Name:  flag_insecureserver,
 			Usage: "whether the connection to the server should use TLS or not",
 		},
+		cli.Float64Flag{
+			Name:  flag_paymenttolerance,
+			Usage: "how many percent a payment requested by the server may differ from the amount we calculate we owe",
+			Value: defaultPaymentTolerance,
+		},
+		cli.BoolFlag{
+			Name:  flag_nomacaroons,
+			Usage: "disable macaroon authentication of the rpc interface, TLS is still used",
+		},
+		cli.Float64Flag{
+			Name:  flag_maxpricedeviation,
+			Usage: "how many percent the price the server quotes when creating a contract may differ from our price",
+			Value: defaultMaxPriceDeviation,
+		},
+		cli.Float64Flag{
+			Name:  flag_maxmarginpercent,
+			Usage: "the highest margin, in percent of the contract amount, we accept when creating a contract",
+			Value: defaultMaxMarginPercent,
+		},
+		cli.IntFlag{
+			Name:  flag_maxfeesat,
+			Usage: "the highest routing fee, in sats, we pay for a payment. 0 disables the limit",
+			Value: defaultMaxFeeSat,
+		},
+		cli.Float64Flag{
+			Name:  flag_maxfeepercent,
+			Usage: "the highest routing fee, in percent of the amount, we pay for a payment. 0 disables the limit",
+			Value: defaultMaxFeePercent,
+		},
+		cli.IntFlag{
+			Name:  flag_paymenttimeout,
+			Usage: "how many seconds lnd may spend on a single attempt to pay an invoice before giving up",
+			Value: defaultPaymentTimeout,
+		},
+		cli.IntFlag{
+			Name:  flag_outgoingchanid,
+			Usage: "the id of the channel payments must leave through. 0 lets lnd choose",
+		},
+		cli.BoolFlag{
+			Name:  flag_pinserverchannel,
+			Usage: "pay the asset server through one of our channels with it",
+		},
+		cli.IntFlag{
+			Name:  flag_paymentattempts,
+			Usage: "how many times we try to pay an invoice before giving up",
+			Value: defaultPaymentAttempts,
+		},
 
 		// flags specific to connecting to lnd
 		cli.StringFlag{
@@ -132,84 +234,194 @@ func main() {
 			Value: defaultLndRPCPort,
 		},
 	}
-	app.Action = runClient
 
-	if err := app.Run(os.Args); err != nil {
-		log.Fatalf("[lad]: %v", err)
-	}
+	return app
 }
 
 func runClient(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
 	// create lightning asset client dir, used for saving tls and db files
-	ladDir := c.String(flag_laddir)
+	ladDir := util.CleanAndExpandPath(c.String(flag_laddir))
 	if _, err := os.Stat(ladDir); os.IsNotExist(err) {
 		os.Mkdir(ladDir, os.ModePerm) // 0777 permission
 	}
 
-	db, err := bolt.Open(path.Join(ladDir, defaultDBName), 0600, &bolt.Options{
-		Timeout: 1 * time.Second,
-	})
+	db, err := store.OpenBolt(path.Join(ladDir, defaultDBName))
 	if err != nil {
-		return fmt.Errorf("could not open database: %w", err)
+		return err
 	}
 	defer db.Close()
 
-	err = createBucketsIfNotExist(db)
-	if err != nil {
-		return err
-	}
+	// create notifiers that new contracts and new payments are sent to
+	contractNotifier := newNotifier(defaultSubscriberQueueSize)
+	paymentNotifier := newNotifier(defaultSubscriberQueueSize)
 
-	// connect to lnd
-	lncli, err := util.ConnectToLnd(
-		c.String(flag_lnddir),
-		c.String(flag_lndrpchost),
-		c.String(flag_network),
-	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// we start even if lnd is not ready, and wait for it to be unlocked
+	// and synced before using it
+	lnd, err := connectToLnd(ctx, c.String(flag_lnddir),
+		c.String(flag_lndrpchost), c.String(flag_network))
 	if err != nil {
 		return fmt.Errorf("could not connect to lnd: %w", err)
 	}
+	go lnd.monitor(ctx)
 
-	// create channel that new contracts and new payments are sent to
-	contractCh := make(chan larpc.ClientContract)
-	paymentCh := make(chan larpc.Payment)
-
+	// we start even if the server is down, and keep reconnecting to it
 	ladServer, cleanup, err := newServerConnection(c.String(
 		flag_serveraddress), c.Bool(flag_insecureserver), "")
 	if err != nil {
 		return fmt.Errorf("could not connect to asset server: %w", err)
 	}
 	defer cleanup()
+	go ladServer.monitor(ctx)
+
+	// we only pay invoices created by the node of the server
+	identity, err := newServerIdentity(db, c.String(flag_serveraddress),
+		c.String(flag_serverpubkey))
+	if err != nil {
+		return fmt.Errorf("could not load server identity: %w", err)
+	}
 
-	// TODO: Add bitmex websocket that listens to the price
+	// start listening to the price feeds
+	priceOracle := oracle.New(defaultMaxPriceAge,
+		oracle.NewBitmexSource(),
+		oracle.NewHTTPSource(c.String(flag_priceserver_address)),
+	)
+	priceOracle.Start(ctx)
+
+	paymentTolerance, err := decimalFlag(c, flag_paymenttolerance)
+	if err != nil {
+		return err
+	}
+	maxPriceDeviation, err := decimalFlag(c, flag_maxpricedeviation)
+	if err != nil {
+		return err
+	}
+	maxMarginPercent, err := decimalFlag(c, flag_maxmarginpercent)
+	if err != nil {
+		return err
+	}
 
 	assetServer := AssetClient{
-		lncli:          lncli,
-		db:             db,
-		port:           c.Int(flag_port),
-		netAddress:     c.String(flag_netaddress),
-		server:         ladServer,
-
-		contractCh: contractCh,
-		paymentsCh: paymentCh,
+		lncli:      lnd.client,
+		router:     lnd.router,
+		lnd:        lnd,
+		db:         db,
+		port:       c.Int(flag_port),
+		network:    c.String(flag_network),
+		netAddress: c.String(flag_netaddress),
+		server:     ladServer,
+		identity:   identity,
+		assets:     newAssetCache(ladServer, defaultAssetCacheTTL),
+		oracle:     priceOracle,
+		config:     cfg,
+
+		paymentTolerance: paymentTolerance,
+		quotePolicy: quotePolicy{
+			maxPriceDeviation: maxPriceDeviation,
+			maxMarginPercent:  maxMarginPercent,
+		},
+		paymentPolicy: paymentPolicy{
+			maxFeeSat:        int64(c.Int(flag_maxfeesat)),
+			maxFeePercent:    c.Float64(flag_maxfeepercent),
+			timeout:          time.Duration(c.Int(flag_paymenttimeout)) * time.Second,
+			outgoingChanID:   uint64(c.Int(flag_outgoingchanid)),
+			pinToDestination: c.Bool(flag_pinserverchannel),
+			maxAttempts:      c.Int(flag_paymentattempts),
+		},
+
+		reconciled: newReconciliation(),
+
+		contractNotifier: contractNotifier,
+		paymentNotifier:  paymentNotifier,
+	}
+
+	go func() {
+		if err := lnd.waitConnected(ctx); err != nil {
+			return
+		}
+
+		// finish what we were doing if we were stopped while paying
+		if err := assetServer.runReconcile(ctx); err != nil {
+			return
+		}
+
+		// keep track of when the invoices we create are paid
+		assetServer.runInvoiceWatcher(ctx)
+	}()
+
+	// without a public address, the server can only reach us over the
+	// push channel
+	if assetServer.netAddress == "" {
+		go assetServer.runPushChannel(ctx)
+	}
+
+	// expire contracts that were never opened, and clean them up
+	go assetServer.runContractJanitor(ctx, defaultExpiryCheckInterval,
+		time.Duration(c.Int(flag_expiredretention))*time.Hour)
+
+	if frequency := c.Int(flag_rebalancefrequency); frequency > 0 {
+		rebalancer := rebalancer{
+			client:       assetServer,
+			interval:     time.Duration(frequency) * time.Second,
+			minAmountSat: defaultMinRebalanceAmount,
+		}
+		go rebalancer.Start(ctx)
+	}
+
+	// all rpc connections are encrypted, and authenticated with macaroons
+	certPath := path.Join(ladDir, defaultTLSCertFilename)
+	keyPath := path.Join(ladDir, defaultTLSKeyFilename)
+	tlsCreds, err := loadTLSCredentials(certPath, keyPath)
+	if err != nil {
+		return err
+	}
+
+	serverOpts := []grpc.ServerOption{grpc.Creds(tlsCreds)}
+
+	if !c.Bool(flag_nomacaroons) {
+		macaroonService, err := newMacaroonService(ctx, ladDir)
+		if err != nil {
+			return err
+		}
+		defer macaroonService.Close()
+
+		// the server only calls us directly if we have a public address
+		allowServerRPCs := assetServer.netAddress != ""
+
+		serverOpts = append(serverOpts,
+			grpc.UnaryInterceptor(unaryAuthInterceptor(macaroonService,
+				allowServerRPCs)),
+			grpc.StreamInterceptor(streamAuthInterceptor(macaroonService,
+				allowServerRPCs)),
+		)
+	} else {
+		log.Warn("macaroons are disabled, anyone who can reach the rpc " +
+			"interface can use it")
 	}
 
 	// create grpc server that listens to grpc requests
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(serverOpts...)
 	larpc.RegisterAssetClientServer(grpcServer, &assetServer)
 
-	// start webserver that uses normal http / http2, used for communicating with front-end
-	go func() {
-		wrappedGrpc := grpcweb.WrapServer(grpcServer)
-		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			wrappedGrpc.ServeHTTP(w, r)
-		})
-
-		router := mux.NewRouter()
-		router.Use(headerMiddleware)
-		router.PathPrefix("/").Handler(handler)
+	// start webserver that uses normal http / http2, used for communicating
+	// with front-end and REST clients
+	router, err := newRESTRouter(ctx, grpcServer,
+		fmt.Sprintf("localhost:%d", c.Int(flag_port)), certPath)
+	if err != nil {
+		return err
+	}
 
+	go func() {
 		log.Infoln("rest server listening on port", c.Int(flag_rest_port))
-		res := http.ListenAndServe(fmt.Sprintf(":%d", c.Int(flag_rest_port)), router)
+		res := http.ListenAndServeTLS(fmt.Sprintf(":%d", c.Int(flag_rest_port)),
+			certPath, keyPath, router)
 		log.Fatal(res)
 	}()
 
@@ -236,74 +448,19 @@ func headerMiddleware(next http.Handler) http.Handler {
 		w.Header().Add("Access-Control-Allow-Origin", "*")
 		w.Header().Add("Access-Control-Allow-Headers", "x-grpc-web")
 		w.Header().Add("Access-Control-Allow-Headers", "content-type")
+		w.Header().Add("Access-Control-Allow-Headers", "macaroon")
+		w.Header().Add("Access-Control-Allow-Headers", "grpc-metadata-macaroon")
 		next.ServeHTTP(w, r)
 	})
 }
 
-func createBucketsIfNotExist(db *bolt.DB) error {
-	// create bucket if it doesnt exist
-	return db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists(contractsBucket)
-		if err != nil {
-			return fmt.Errorf("could not create bucket: %w", err)
-		}
-		// add additional buckets here
-		return nil
-	})
-}
-
-type grpcServerConnection struct {
-	server larpc.AssetServerClient
-	conn   *grpc.ClientConn
-}
-
-// newServerConnection opens a connection to the swap server.
-func newServerConnection(address string, insecure bool,
-	tlsPath string) (*grpcServerConnection, func(), error) {
-
-	// Create a dial options array.
-	var opts []grpc.DialOption
-
-	// There are three options to connect to a swap server, either insecure,
-	// using a self-signed certificate or with a certificate signed by a
-	// public CA.
-	switch {
-	case insecure:
-		opts = append(opts, grpc.WithInsecure())
-
-	case tlsPath != "":
-		// Load the specified TLS certificate and build
-		// transport credentials
-		creds, err := credentials.NewClientTLSFromFile(tlsPath, "")
-		if err != nil {
-			return nil, nil, err
-		}
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-
-	default:
-		creds := credentials.NewTLS(&tls.Config{})
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	}
-
-	withTimeout, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	serverConn, err := grpc.DialContext(withTimeout, address, opts...)
+// decimalFlag returns the value of a float flag as an exact decimal, ie 0.1
+// instead of the float nearest to it
+func decimalFlag(c *cli.Context, name string) (money.Decimal, error) {
+	d, err := money.DecimalFromFloat(c.Float64(name))
 	if err != nil {
-		return nil, nil, fmt.Errorf("unable to connect to RPC server: %v",
-			err)
-	}
-
-	server := larpc.NewAssetServerClient(serverConn)
-
-	cleanUp := func() {
-		serverConn.Close()
-	}
-
-	conn := &grpcServerConnection{
-		server: server,
-		conn:   serverConn,
+		return money.Decimal{}, fmt.Errorf("invalid %s: %w", name, err)
 	}
 
-	return conn, cleanUp, nil
+	return d, nil
 }
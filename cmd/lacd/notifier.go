@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultSubscriberQueueSize is how many updates can be queued for a single
+// subscriber before it is considered too slow, and is unsubscribed
+const defaultSubscriberQueueSize = 100
+
+// notifier fans out every update it is notified of to all of its
+// subscribers. Each subscriber has its own buffered queue, so a slow
+// subscriber never blocks the notifier or the other subscribers.
+type notifier struct {
+	queueSize int
+
+	mu          sync.Mutex
+	nextID      uint64
+	subscribers map[uint64]*subscription
+}
+
+func newNotifier(queueSize int) *notifier {
+	return &notifier{
+		queueSize:   queueSize,
+		subscribers: make(map[uint64]*subscription),
+	}
+}
+
+// subscription is a single subscriber of a notifier
+type subscription struct {
+	id       uint64
+	notifier *notifier
+
+	updates chan interface{}
+
+	// overflowed is set if the subscriber did not keep up with the updates,
+	// and was unsubscribed because of it
+	overflowed bool
+}
+
+// Subscribe registers a new subscriber. Cancel must be called on the
+// subscription when the subscriber is done.
+func (n *notifier) Subscribe() *subscription {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.nextID++
+	sub := &subscription{
+		id:       n.nextID,
+		notifier: n,
+		updates:  make(chan interface{}, n.queueSize),
+	}
+	n.subscribers[sub.id] = sub
+
+	return sub
+}
+
+// Notify queues update for all subscribers. Subscribers with a full queue
+// are unsubscribed, instead of silently missing the update.
+func (n *notifier) Notify(update interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for id, sub := range n.subscribers {
+		select {
+		case sub.updates <- update:
+		default:
+			log.WithField("subscriber", id).
+				Warn("subscriber is not keeping up, unsubscribing")
+
+			sub.overflowed = true
+			n.remove(sub)
+		}
+	}
+}
+
+// NumSubscribers returns the number of active subscribers
+func (n *notifier) NumSubscribers() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return len(n.subscribers)
+}
+
+// remove unsubscribes sub and closes its queue. The caller must hold the
+// lock of the notifier.
+func (n *notifier) remove(sub *subscription) {
+	if _, ok := n.subscribers[sub.id]; !ok {
+		return
+	}
+
+	delete(n.subscribers, sub.id)
+	close(sub.updates)
+}
+
+// Updates returns the queue of updates of the subscription. It is closed
+// when the subscription is canceled.
+func (s *subscription) Updates() <-chan interface{} {
+	return s.updates
+}
+
+// Overflowed returns true if the subscription was canceled because the
+// subscriber did not keep up
+func (s *subscription) Overflowed() bool {
+	s.notifier.mu.Lock()
+	defer s.notifier.mu.Unlock()
+
+	return s.overflowed
+}
+
+// Cancel unsubscribes from the notifier. It is safe to call multiple times.
+func (s *subscription) Cancel() {
+	s.notifier.mu.Lock()
+	defer s.notifier.mu.Unlock()
+
+	s.notifier.remove(s)
+}
+
+// forwardUpdates sends every update of sub with send, until ctx is canceled
+// or the subscription ends
+func forwardUpdates(ctx context.Context, sub *subscription,
+	send func(update interface{}) error) error {
+
+	for {
+		select {
+		case update, ok := <-sub.Updates():
+			if !ok {
+				if sub.Overflowed() {
+					return status.Error(codes.ResourceExhausted,
+						"subscriber did not keep up with updates")
+				}
+				return nil
+			}
+
+			if err := send(update); err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
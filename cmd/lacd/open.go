@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+const (
+	// how long we wait before retrying a failed reconcile, doubled after
+	// every failure
+	reconcileMinBackoff = 1 * time.Second
+	reconcileMaxBackoff = 1 * time.Minute
+)
+
+// payOpeningInvoices pays the invoices of a contract we are opening that are
+// not paid yet. The contract is saved after every payment, so a failed or
+// interrupted open can be retried without paying anything twice. If an
+// invoice expired before we paid it, the contract can never be opened, and
+// is marked as failed.
+func (a AssetClient) payOpeningInvoices(ctx context.Context,
+	contract *larpc.ClientContract, policy paymentPolicy) error {
+	if !contract.MarginPaid {
+		payment, err := a.payContractInvoice(ctx, contract.Uuid,
+			contract.MarginInvoice, larpc.PaymentType_MARGIN, policy)
+		if err != nil {
+			return a.failIfExpired(ctx, contract, contract.MarginInvoice, err)
+		}
+
+		contract.MarginPaid = true
+		contract.MarginPaymentHash = payment.PaymentHash
+
+		err = saveContract(a.db, a.contractNotifier,
+			larpc.ClientContractUpdate_OPENING, *contract)
+		if err != nil {
+			return fmt.Errorf("could not save contract in DB: %w", err)
+		}
+	}
+
+	if contract.ContractType == larpc.ContractType_FUNDED && !contract.InitPaid {
+		payment, err := a.payContractInvoice(ctx, contract.Uuid,
+			contract.InitInvoice, larpc.PaymentType_INIT, policy)
+		if err != nil {
+			return a.failIfExpired(ctx, contract, contract.InitInvoice, err)
+		}
+
+		contract.InitPaid = true
+		contract.InitPaymentHash = payment.PaymentHash
+
+		err = saveContract(a.db, a.contractNotifier,
+			larpc.ClientContractUpdate_OPENING, *contract)
+		if err != nil {
+			return fmt.Errorf("could not save contract in DB: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// failIfExpired marks the contract as failed if the invoice we could not
+// pay has expired, and returns the payment error
+func (a AssetClient) failIfExpired(ctx context.Context, contract *larpc.ClientContract,
+	paymentRequest string, paymentErr error) error {
+
+	payReq, err := a.lncli.DecodePayReq(ctx, &lnrpc.PayReqString{
+		PayReq: paymentRequest,
+	})
+	if err != nil {
+		log.WithError(err).Error("could not decode payment request")
+		return paymentErr
+	}
+
+	if time.Now().Unix() < payReq.Timestamp+payReq.Expiry {
+		return paymentErr
+	}
+
+	reason := fmt.Sprintf("invoice expired before it was paid: %v", paymentErr)
+	if err := transition(contract, larpc.ContractStatus_FAILED, reason); err != nil {
+		return err
+	}
+
+	err = saveContract(a.db, a.contractNotifier,
+		larpc.ClientContractUpdate_FAILED, *contract)
+	if err != nil {
+		log.WithError(err).Error("could not save failed contract")
+	}
+
+	return paymentErr
+}
+
+// openingInvoicesPaid returns true if all invoices needed to open the
+// contract are paid
+func openingInvoicesPaid(contract *larpc.ClientContract) bool {
+	if contract.ContractType == larpc.ContractType_FUNDED && !contract.InitPaid {
+		return false
+	}
+
+	return contract.MarginPaid
+}
+
+// reconcileOpeningContracts finishes opening contracts whose invoices were
+// paid while we were interrupted, based on the payments we have recorded.
+// Contracts that still have unpaid invoices are left for the user to retry.
+func (a AssetClient) reconcileOpeningContracts() error {
+	contracts, err := a.db.ListContracts()
+	if err != nil {
+		return err
+	}
+
+	for _, contract := range contracts {
+		if contract.Status != larpc.ContractStatus_OPENING {
+			continue
+		}
+
+		if err := a.reconcileOpeningContract(contract); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a AssetClient) reconcileOpeningContract(contract *larpc.ClientContract) error {
+	// isPaid checks if we have recorded a settled payment with the hash
+	isPaid := func(hash string) (bool, error) {
+		if hash == "" {
+			return false, nil
+		}
+
+		payment, err := a.db.GetPayment(hash)
+		if err != nil {
+			return false, err
+		}
+
+		return payment != nil && payment.Settled, nil
+	}
+
+	var err error
+	if !contract.MarginPaid {
+		if contract.MarginPaid, err = isPaid(contract.MarginPaymentHash); err != nil {
+			return err
+		}
+	}
+	if !contract.InitPaid {
+		if contract.InitPaid, err = isPaid(contract.InitPaymentHash); err != nil {
+			return err
+		}
+	}
+
+	if !openingInvoicesPaid(contract) {
+		log.WithField("uuid", contract.Uuid).
+			Warn("contract is partially opened, retry opening it")
+
+		return saveContract(a.db, a.contractNotifier,
+			larpc.ClientContractUpdate_OPENING, *contract)
+	}
+
+	err = transition(contract, larpc.ContractStatus_OPEN,
+		"invoices were paid while opening was interrupted")
+	if err != nil {
+		return err
+	}
+
+	return saveContract(a.db, a.contractNotifier,
+		larpc.ClientContractUpdate_OPENED, *contract)
+}
+
+// reconcile catches up on payments and contracts that were interrupted by a
+// crash or restart. Until it has succeeded, reconciled holds off opening
+// contracts and paying the server.
+func (a AssetClient) reconcile(ctx context.Context) error {
+	if err := a.reconcileOutboundPayments(ctx); err != nil {
+		return fmt.Errorf("could not reconcile payments: %w", err)
+	}
+
+	if err := a.reconcileOpeningContracts(); err != nil {
+		return fmt.Errorf("could not reconcile contracts: %w", err)
+	}
+
+	return nil
+}
+
+// runReconcile runs reconcile until it succeeds, backing off between
+// attempts, and then marks the client as reconciled
+func (a AssetClient) runReconcile(ctx context.Context) error {
+	backoff := reconcileMinBackoff
+
+	for {
+		err := a.reconcile(ctx)
+		if err == nil {
+			a.reconciled.finish()
+			return nil
+		}
+
+		log.WithError(err).
+			Errorf("could not reconcile with lnd, retrying in %s", backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > reconcileMaxBackoff {
+			backoff = reconcileMaxBackoff
+		}
+	}
+}
+
+// reconciliation tracks whether we have reconciled with lnd since we
+// started. Payments made before that could pay an invoice that was already
+// paid before a crash.
+type reconciliation struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func newReconciliation() *reconciliation {
+	return &reconciliation{
+		done: make(chan struct{}),
+	}
+}
+
+// finish marks us as reconciled
+func (r *reconciliation) finish() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+}
+
+// ready returns an error if we have not reconciled yet
+func (r *reconciliation) ready() error {
+	select {
+	case <-r.done:
+		return nil
+	default:
+		return status.Error(codes.Unavailable,
+			"lacd has not reconciled interrupted payments with lnd yet")
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+const (
+	// the percentage fee cap never limits the fee below this, so small
+	// payments can still be routed
+	minPercentFeeLimitSat = 10
+
+	// how long we wait before attempting a failed payment again
+	paymentRetryDelay = 2 * time.Second
+)
+
+// paymentPolicy controls how we pay invoices
+type paymentPolicy struct {
+	// the highest routing fee we pay, in sats and in percent of the amount.
+	// The lower of the two applies, 0 disables a cap.
+	maxFeeSat     int64
+	maxFeePercent float64
+
+	// how long a single attempt to pay may take before lnd gives up
+	timeout time.Duration
+
+	// the channel payments must leave through, 0 lets lnd choose
+	outgoingChanID uint64
+
+	// if set, payments leave through one of our channels with the node we
+	// are paying, ie the asset server
+	pinToDestination bool
+
+	// how many times we try to pay an invoice before giving up
+	maxAttempts int
+}
+
+// withOptions returns the policy with the options set in an rpc request
+// applied on top
+func (p paymentPolicy) withOptions(options *larpc.PaymentOptions) paymentPolicy {
+	if options == nil {
+		return p
+	}
+
+	if options.MaxFeeSat != 0 {
+		p.maxFeeSat = options.MaxFeeSat
+	}
+	if options.MaxFeePercent != 0 {
+		p.maxFeePercent = options.MaxFeePercent
+	}
+	if options.TimeoutSeconds != 0 {
+		p.timeout = time.Duration(options.TimeoutSeconds) * time.Second
+	}
+	// an outgoing channel in the request replaces pinning configured for
+	// the daemon, and the other way around
+	if options.OutgoingChanId != 0 {
+		p.outgoingChanID = options.OutgoingChanId
+		p.pinToDestination = false
+	}
+	if options.PinToServer {
+		p.outgoingChanID = 0
+		p.pinToDestination = true
+	}
+	if options.MaxAttempts != 0 {
+		p.maxAttempts = int(options.MaxAttempts)
+	}
+
+	return p
+}
+
+// validatePaymentOptions checks the payment options of an rpc request
+func validatePaymentOptions(options *larpc.PaymentOptions) error {
+	if options == nil {
+		return nil
+	}
+
+	switch {
+	case options.MaxFeeSat < 0:
+		return status.Error(codes.InvalidArgument, "max fee can not be negative")
+	case options.MaxFeePercent < 0:
+		return status.Error(codes.InvalidArgument, "max fee percent can not be negative")
+	case options.TimeoutSeconds < 0:
+		return status.Error(codes.InvalidArgument, "timeout can not be negative")
+	case options.MaxAttempts < 0:
+		return status.Error(codes.InvalidArgument, "max attempts can not be negative")
+	case options.OutgoingChanId != 0 && options.PinToServer:
+		return status.Error(codes.InvalidArgument,
+			"an outgoing channel can not be combined with pinning to the server")
+	}
+
+	return nil
+}
+
+// feeLimit returns the highest fee we pay to send amountSat. lnd requires a
+// limit, so if neither cap is set the fee is limited to the amount itself.
+func (p paymentPolicy) feeLimit(amountSat int64) int64 {
+	limit := amountSat
+
+	if p.maxFeePercent > 0 {
+		limit = int64(math.Floor(float64(amountSat) * p.maxFeePercent / 100))
+		if limit < minPercentFeeLimitSat {
+			limit = minPercentFeeLimitSat
+		}
+	}
+
+	if p.maxFeeSat > 0 && p.maxFeeSat < limit {
+		limit = p.maxFeeSat
+	}
+
+	return limit
+}
+
+// sendRequest creates the request paying payReq according to the policy.
+// If we do not have the balance to make the payment, a paymentError is
+// returned.
+func (a AssetClient) sendRequest(ctx context.Context, policy paymentPolicy,
+	paymentRequest string, payReq *lnrpc.PayReq) (*routerrpc.SendPaymentRequest, error) {
+
+	timeout := policy.timeout
+	if timeout <= 0 {
+		timeout = time.Duration(defaultPaymentTimeout) * time.Second
+	}
+
+	req := &routerrpc.SendPaymentRequest{
+		PaymentRequest: paymentRequest,
+		TimeoutSeconds: int32(math.Ceil(timeout.Seconds())),
+		FeeLimitSat:    policy.feeLimit(payReq.NumSatoshis),
+		OutgoingChanId: policy.outgoingChanID,
+	}
+	minBalance := payReq.NumSatoshis + req.FeeLimitSat
+
+	if policy.pinToDestination {
+		chanID, err := a.channelTo(ctx, payReq, minBalance)
+		if err != nil {
+			return nil, err
+		}
+		req.OutgoingChanId = chanID
+
+		return req, nil
+	}
+
+	// lnd would fail the payment as having no route, we can tell the
+	// caller what is wrong
+	balance, err := a.lncli.ChannelBalance(ctx, &lnrpc.ChannelBalanceRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("could not get channel balance: %w", err)
+	}
+	if balance.Balance < payReq.NumSatoshis {
+		return nil, &paymentError{
+			hash:   payReq.PaymentHash,
+			reason: larpc.PaymentFailureReason_FAILURE_REASON_INSUFFICIENT_BALANCE,
+			msg: fmt.Sprintf("channel balance is %d sats, need %d",
+				balance.Balance, payReq.NumSatoshis),
+		}
+	}
+
+	return req, nil
+}
+
+// channelTo returns the active channel with the destination of payReq that
+// has the most local balance, if it has at least minBalance
+func (a AssetClient) channelTo(ctx context.Context, payReq *lnrpc.PayReq,
+	minBalance int64) (uint64, error) {
+
+	res, err := a.lncli.ListChannels(ctx, &lnrpc.ListChannelsRequest{
+		ActiveOnly: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not list channels: %w", err)
+	}
+
+	var best *lnrpc.Channel
+	for _, channel := range res.Channels {
+		if channel.RemotePubkey != payReq.Destination {
+			continue
+		}
+		if best == nil || channel.LocalBalance > best.LocalBalance {
+			best = channel
+		}
+	}
+
+	switch {
+	case best == nil:
+		return 0, &paymentError{
+			hash:   payReq.PaymentHash,
+			reason: larpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+			msg:    fmt.Sprintf("no active channel with %s", payReq.Destination),
+		}
+
+	case best.LocalBalance < minBalance:
+		return 0, &paymentError{
+			hash:   payReq.PaymentHash,
+			reason: larpc.PaymentFailureReason_FAILURE_REASON_INSUFFICIENT_BALANCE,
+			msg: fmt.Sprintf("no channel with %s has %d sats, the most is %d",
+				payReq.Destination, minBalance, best.LocalBalance),
+		}
+	}
+
+	return best.ChanId, nil
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+)
+
+// how long to wait before subscribing to invoices again if the
+// subscription fails
+const invoiceWatcherRetryDelay = 10 * time.Second
+
+// errPaymentInFlight is returned when lnd is still trying to pay an invoice
+var errPaymentInFlight = errors.New("payment is still in flight")
+
+// payContractInvoice pays an invoice belonging to a contract, and records
+// the payment in the database. The payment is recorded before it is sent,
+// so a payment interrupted by a crash is reconciled with lnd instead of
+// being paid again. Paying an invoice that is already paid returns the
+// existing payment. Failed payments are attempted again, as many times as
+// the policy allows.
+func (a AssetClient) payContractInvoice(ctx context.Context, uuid string,
+	paymentRequest string, paymentType larpc.PaymentType,
+	policy paymentPolicy) (*larpc.Payment, error) {
+
+	if err := a.lnd.ready(); err != nil {
+		return nil, err
+	}
+
+	payReq, err := a.lncli.DecodePayReq(ctx, &lnrpc.PayReqString{
+		PayReq: paymentRequest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not decode payment request: %w", err)
+	}
+
+	// we never pay anyone but the server
+	if err := a.identity.verify(payReq.Destination); err != nil {
+		return nil, err
+	}
+
+	existing, err := a.db.GetPayment(payReq.PaymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if existing.Settled {
+			return existing, nil
+		}
+
+		// we have tried to pay this before, and have to make sure lnd
+		// did not complete the payment. If lnd is still trying to, we
+		// wait for it to finish.
+		settled, err := a.reconcileOutboundPayment(ctx, *existing)
+		var paymentErr *paymentError
+		switch {
+		case errors.Is(err, errPaymentInFlight):
+			err := a.trackPayment(ctx, existing)
+			if err == nil {
+				return existing, nil
+			}
+			if !errors.As(err, &paymentErr) {
+				return nil, err
+			}
+
+		case err != nil || settled != nil:
+			return settled, err
+		}
+	}
+
+	payment := larpc.Payment{
+		ContractUuid:   uuid,
+		AmountSat:      payReq.NumSatoshis,
+		PaymentRequest: paymentRequest,
+		Outbound:       true,
+		PaymentHash:    payReq.PaymentHash,
+		Type:           paymentType,
+		CreatedAt:      time.Now().Unix(),
+	}
+	if existing != nil {
+		payment.CreatedAt = existing.CreatedAt
+	}
+
+	if err := savePayment(a.db, a.paymentNotifier, payment); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := a.PayInvoice(ctx, &payment, payReq, policy)
+		if err == nil {
+			return &payment, nil
+		}
+
+		var paymentErr *paymentError
+		if attempt >= policy.maxAttempts || !errors.As(err, &paymentErr) ||
+			!paymentErr.retryable() {
+			return nil, err
+		}
+
+		log.WithError(err).WithFields(logrus.Fields{
+			"hash":    payReq.PaymentHash,
+			"attempt": attempt,
+		}).Warn("payment failed, trying again")
+
+		select {
+		case <-time.After(paymentRetryDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// paymentFailed returns true if err, returned by payContractInvoice, means
+// the invoice was not paid and paying it again will not help: lnd gave up
+// after as many attempts as the policy allows, the payment could not
+// succeed, or the invoice is not from the server.
+func paymentFailed(err error) bool {
+	var paymentErr *paymentError
+	var wrongNodeErr *wrongNodeError
+
+	return errors.As(err, &paymentErr) || errors.As(err, &wrongNodeErr)
+}
+
+// reconcileOutboundPayment checks what lnd knows about an unsettled outbound
+// payment. If lnd completed the payment, it is recorded as settled and
+// returned. If lnd never made the payment, or it failed, nil is returned and
+// the invoice can safely be paid again.
+func (a AssetClient) reconcileOutboundPayment(ctx context.Context,
+	payment larpc.Payment) (*larpc.Payment, error) {
+
+	lndPayment, err := a.findLndPayment(ctx, payment.PaymentHash)
+	if err != nil {
+		return nil, err
+	}
+	if lndPayment == nil {
+		return nil, nil
+	}
+
+	switch lndPayment.Status {
+	case lnrpc.Payment_SUCCEEDED:
+		return a.recordLndPayment(payment, lndPayment)
+
+	case lnrpc.Payment_IN_FLIGHT:
+		return nil, fmt.Errorf("%s: %w", payment.PaymentHash, errPaymentInFlight)
+	}
+
+	return nil, nil
+}
+
+// reconcileOutboundPayments catches up on the outcome of outbound payments
+// that were interrupted, ie because we crashed while paying. Payments lnd is
+// still making are tracked in the background until they complete.
+func (a AssetClient) reconcileOutboundPayments(ctx context.Context) error {
+	payments, err := listPayments(a.db, "", true)
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		if !payment.Outbound || payment.Settled {
+			continue
+		}
+
+		_, err := a.reconcileOutboundPayment(ctx, *payment)
+		if errors.Is(err, errPaymentInFlight) {
+			go a.trackInFlightPayment(ctx, *payment)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordLndPayment records a payment lnd has completed, but we did not get
+// to record ourselves, ie because we crashed while paying
+func (a AssetClient) recordLndPayment(payment larpc.Payment,
+	lndPayment *lnrpc.Payment) (*larpc.Payment, error) {
+
+	payment.Preimage = lndPayment.PaymentPreimage
+	payment.FeeSat = lndPayment.FeeSat
+	payment.State = larpc.PaymentState_PAYMENT_SUCCEEDED
+	payment.FailureReason = larpc.PaymentFailureReason_FAILURE_REASON_NONE
+	payment.Settled = true
+	payment.SettledAt = lndPayment.CreationDate
+
+	log.WithField("hash", payment.PaymentHash).
+		Info("recording payment completed by lnd")
+
+	if err := savePayment(a.db, a.paymentNotifier, payment); err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// addContractInvoice creates an invoice belonging to a contract, and
+// records it as an unsettled inbound payment
+func (a AssetClient) addContractInvoice(ctx context.Context, uuid string,
+	amountSat int64, paymentType larpc.PaymentType, memo string) (*larpc.Payment, error) {
+
+	if err := a.lnd.ready(); err != nil {
+		return nil, err
+	}
+
+	invoice, err := a.addInvoice(ctx, amountSat, memo)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := larpc.Payment{
+		ContractUuid:   uuid,
+		AmountSat:      amountSat,
+		PaymentRequest: invoice.PaymentRequest,
+		PaymentHash:    hex.EncodeToString(invoice.RHash),
+		Type:           paymentType,
+		CreatedAt:      time.Now().Unix(),
+	}
+
+	if err := savePayment(a.db, a.paymentNotifier, payment); err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// runInvoiceWatcher keeps watching invoices until ctx is canceled
+func (a AssetClient) runInvoiceWatcher(ctx context.Context) {
+	for {
+		err := a.watchInvoices(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.WithError(err).Errorf("stopped watching invoices, retrying in %s",
+			invoiceWatcherRetryDelay)
+
+		select {
+		case <-time.After(invoiceWatcherRetryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watchInvoices marks inbound payments as settled when lnd tells us our
+// invoices are paid. Invoices settled while we were offline are caught up
+// on before subscribing.
+func (a AssetClient) watchInvoices(ctx context.Context) error {
+	if err := a.reconcileInvoices(ctx); err != nil {
+		return err
+	}
+
+	stream, err := a.lncli.SubscribeInvoices(ctx, &lnrpc.InvoiceSubscription{})
+	if err != nil {
+		return fmt.Errorf("could not subscribe to invoices: %w", err)
+	}
+
+	for {
+		invoice, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("invoice subscription failed: %w", err)
+		}
+
+		if invoice.State != lnrpc.Invoice_SETTLED {
+			continue
+		}
+
+		err = settleInboundPayment(a.db, a.paymentNotifier, invoice)
+		if err != nil {
+			log.WithError(err).Error("could not settle payment")
+		}
+	}
+}
+
+// reconcileInvoices checks the state of all unsettled inbound payments
+func (a AssetClient) reconcileInvoices(ctx context.Context) error {
+	payments, err := listPayments(a.db, "", true)
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		if payment.Outbound || payment.Settled {
+			continue
+		}
+
+		invoice, err := a.lncli.LookupInvoice(ctx, &lnrpc.PaymentHash{
+			RHashStr: payment.PaymentHash,
+		})
+		if err != nil {
+			return fmt.Errorf("could not look up invoice: %w", err)
+		}
+
+		if invoice.State != lnrpc.Invoice_SETTLED {
+			continue
+		}
+
+		if err := settleInboundPayment(a.db, a.paymentNotifier, invoice); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// settleInboundPayment marks the payment of a settled invoice as settled.
+// Invoices that do not belong to any of our contracts are ignored.
+func settleInboundPayment(db store.Store, paymentNotifier *notifier,
+	invoice *lnrpc.Invoice) error {
+
+	hash := hex.EncodeToString(invoice.RHash)
+
+	var payment *larpc.Payment
+	var settled bool
+	err := db.Update(func(tx store.Tx) error {
+		var err error
+		payment, err = tx.GetPayment(hash)
+		if err != nil || payment == nil || payment.Settled {
+			return err
+		}
+
+		payment.Settled = true
+		payment.SettledAt = invoice.SettleDate
+		payment.Preimage = hex.EncodeToString(invoice.RPreimage)
+		settled = true
+
+		return tx.PutPayment(payment)
+	})
+	if err != nil {
+		return fmt.Errorf("could not settle payment: %w", err)
+	}
+
+	if !settled {
+		return nil
+	}
+
+	log.WithFields(logrus.Fields{
+		"uuid":      payment.ContractUuid,
+		"amountSat": payment.AmountSat,
+	}).Info("received payment")
+
+	notifyPayment(paymentNotifier, *payment)
+
+	return nil
+}
+
+func savePayment(db store.Store, paymentNotifier *notifier, payment larpc.Payment) error {
+	if err := db.PutPayment(&payment); err != nil {
+		return fmt.Errorf("could not save payment: %w", err)
+	}
+
+	notifyPayment(paymentNotifier, payment)
+
+	return nil
+}
+
+// notifyPayment passes the payment on to the subscribers, in case someone
+// is subscribed
+func notifyPayment(paymentNotifier *notifier, payment larpc.Payment) {
+	paymentNotifier.Notify(&payment)
+}
+
+// listPayments returns all payments of the contract with the given uuid,
+// or of all contracts if uuid is empty, sorted by creation time
+func listPayments(db store.Store, uuid string, includeUnsettled bool) ([]*larpc.Payment, error) {
+	all, err := db.ListPayments(uuid)
+	if err != nil {
+		return nil, fmt.Errorf("could not list payments: %w", err)
+	}
+
+	var payments []*larpc.Payment
+	for _, payment := range all {
+		if payment.Settled || includeUnsettled {
+			payments = append(payments, payment)
+		}
+	}
+
+	return payments, nil
+}
+
+func (a AssetClient) ListPayments(ctx context.Context, req *larpc.ClientListPaymentsRequest) (*larpc.ClientListPaymentsResponse, error) {
+	log.Infoln("received list payments request")
+
+	if req == nil {
+		return nil, fmt.Errorf("request can not be nil")
+	}
+
+	payments, err := listPayments(a.db, req.Uuid, req.IncludeUnsettled)
+	if err != nil {
+		return nil, err
+	}
+
+	return &larpc.ClientListPaymentsResponse{
+		Payments: payments,
+	}, nil
+}
+
+func (a AssetClient) SubscribePayments(req *larpc.ClientSubscribePaymentsRequest,
+	updateStream larpc.AssetClient_SubscribePaymentsServer) error {
+	log.Infoln("received subscribe payments request")
+
+	sub := a.paymentNotifier.Subscribe()
+	defer sub.Cancel()
+
+	return forwardUpdates(updateStream.Context(), sub, func(update interface{}) error {
+		return updateStream.Send(update.(*larpc.Payment))
+	})
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+const (
+	pushMinBackoff = 1 * time.Second
+	pushMaxBackoff = 1 * time.Minute
+)
+
+// runPushChannel keeps a push channel to the asset server open until ctx is
+// canceled, reconnecting with an exponential backoff if it is lost. Over the
+// push channel the server asks us for invoices and payments, so it never has
+// to connect to us.
+func (a AssetClient) runPushChannel(ctx context.Context) {
+	backoff := pushMinBackoff
+
+	for {
+		started := time.Now()
+		err := a.servePushChannel(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// if the channel was open for a while, we consider the
+		// connection to have been healthy and reset the backoff
+		if time.Since(started) > pushMaxBackoff {
+			backoff = pushMinBackoff
+		}
+
+		log.WithError(err).Warnf("push channel to server lost, reconnecting in %s",
+			backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		backoff *= 2
+		if backoff > pushMaxBackoff {
+			backoff = pushMaxBackoff
+		}
+	}
+}
+
+// servePushChannel opens a push channel, and handles requests from the
+// server until the channel is closed
+func (a AssetClient) servePushChannel(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the server knows us by the pubkey of our node, so we need to have
+	// reached lnd before registering
+	if err := a.lnd.waitConnected(ctx); err != nil {
+		return err
+	}
+
+	// wait for the server to be reachable instead of failing right away,
+	// grpc reconnects to it with its own backoff
+	stream, err := a.server.server.PushChannel(ctx, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("could not open push channel: %w", err)
+	}
+
+	// the registration is signed by our node, so no one else can take
+	// over our push channel. It is signed once the server is reached, so
+	// the signature is fresh.
+	registration := &larpc.PushRegistration{
+		NodePubkey: a.lnd.nodePubkey(),
+	}
+	registration.Signature, err = a.newSignature()
+	if err != nil {
+		return err
+	}
+	err = a.sign(ctx, registration.Signature, registration.SigningPayload())
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(&larpc.PushClientMessage{
+		Message: &larpc.PushClientMessage_Registration{
+			Registration: registration,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not register push channel: %w", err)
+	}
+
+	log.Info("opened push channel to server")
+
+	// requests are handled concurrently, as paying can take a while, but
+	// only one message can be sent on the stream at a time
+	var sendMu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res := a.handlePush(ctx, msg)
+			res.RequestId = msg.RequestId
+
+			sendMu.Lock()
+			defer sendMu.Unlock()
+
+			if err := stream.Send(res); err != nil {
+				log.WithError(err).WithField("request", msg.RequestId).
+					Error("could not respond on push channel")
+			}
+		}()
+	}
+}
+
+// handlePush handles a single request from the server, in the same way as
+// if the server had called our rpc interface directly
+func (a AssetClient) handlePush(ctx context.Context, msg *larpc.PushServerMessage) *larpc.PushClientMessage {
+	switch req := msg.Request.(type) {
+	case *larpc.PushServerMessage_InvoiceRequest:
+		res, err := a.RequestPaymentRequest(ctx, &larpc.ClientRequestPaymentRequestRequest{
+			Uuid:      req.InvoiceRequest.Uuid,
+			AmountSat: req.InvoiceRequest.AmountSat,
+		})
+		if err != nil {
+			return pushError(err)
+		}
+
+		return &larpc.PushClientMessage{
+			Message: &larpc.PushClientMessage_InvoiceResponse{
+				InvoiceResponse: &larpc.PushInvoiceResponse{PayReq: res.PayReq},
+			},
+		}
+
+	case *larpc.PushServerMessage_PaymentRequest:
+		_, err := a.RequestPayment(ctx, &larpc.ClientRequestPaymentRequest{
+			Uuid:   req.PaymentRequest.Uuid,
+			PayReq: req.PaymentRequest.PayReq,
+		})
+		if err != nil {
+			return pushError(err)
+		}
+
+		return &larpc.PushClientMessage{
+			Message: &larpc.PushClientMessage_PaymentResponse{
+				PaymentResponse: &larpc.PushPaymentResponse{},
+			},
+		}
+	}
+
+	return pushError(status.Errorf(codes.Unimplemented,
+		"unknown push request %T", msg.Request))
+}
+
+func pushError(err error) *larpc.PushClientMessage {
+	s := status.Convert(err)
+
+	pushErr := &larpc.PushError{
+		Code:    uint32(s.Code()),
+		Message: s.Message(),
+	}
+	for _, detail := range s.Details() {
+		if paymentErr, ok := detail.(*larpc.PaymentError); ok {
+			pushErr.PaymentError = paymentErr
+		}
+	}
+
+	return &larpc.PushClientMessage{
+		Message: &larpc.PushClientMessage_Error{
+			Error: pushErr,
+		},
+	}
+}
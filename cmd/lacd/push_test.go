@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+func TestPushChannel(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx, cancel := context.WithTimeout(h.ctx, 5*time.Second)
+	defer cancel()
+
+	go h.asset.runPushChannel(ctx)
+	if err := h.server.WaitForPushChannel(ctx, h.clientNode.Pubkey); err != nil {
+		t.Fatalf("push channel was not registered: %v", err)
+	}
+}
+
+func TestPushRegistration(t *testing.T) {
+	// signedBy returns a registration for the client node, signed by node
+	signedBy := func(h *testHarness, node lnrpc.LightningClient) *larpc.PushRegistration {
+		registration := &larpc.PushRegistration{
+			NodePubkey: h.clientNode.Pubkey,
+		}
+
+		var err error
+		registration.Signature, err = h.asset.newSignature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := node.SignMessage(h.ctx, &lnrpc.SignMessageRequest{
+			Msg: registration.SigningPayload(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		registration.Signature.Signature = res.Signature
+
+		return registration
+	}
+
+	tests := []struct {
+		name         string
+		registration func(h *testHarness) *larpc.PushRegistration
+		wantCode     codes.Code
+	}{
+		{
+			name: "signed by the client node",
+			registration: func(h *testHarness) *larpc.PushRegistration {
+				return signedBy(h, h.clientNode)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "unsigned",
+			registration: func(h *testHarness) *larpc.PushRegistration {
+				return &larpc.PushRegistration{NodePubkey: h.clientNode.Pubkey}
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "signed by another node",
+			registration: func(h *testHarness) *larpc.PushRegistration {
+				return signedBy(h, h.serverNode)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			err := h.register(test.registration(h))
+			requireCode(t, err, test.wantCode)
+		})
+	}
+}
+
+// register opens a push channel with the given registration, and waits
+// until the server either accepts or rejects it
+func (h *testHarness) register(registration *larpc.PushRegistration) error {
+	ctx, cancel := context.WithTimeout(h.ctx, 5*time.Second)
+	defer cancel()
+
+	stream, err := h.asset.server.server.PushChannel(ctx)
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&larpc.PushClientMessage{
+		Message: &larpc.PushClientMessage_Registration{
+			Registration: registration,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	accepted := make(chan error, 1)
+	go func() {
+		accepted <- h.server.WaitForPushChannel(ctx, registration.NodePubkey)
+	}()
+	rejected := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		rejected <- err
+	}()
+
+	select {
+	case err := <-accepted:
+		return err
+	case err := <-rejected:
+		return err
+	}
+}
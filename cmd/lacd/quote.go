@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+// deviationScale is the number of decimals deviations are calculated with
+const deviationScale = 4
+
+// quotePolicy decides which quotes from the server we accept
+type quotePolicy struct {
+	// how many percent the price of the server, and the invoice amounts
+	// based on it, may differ from our own
+	maxPriceDeviation money.Decimal
+
+	// the highest margin, in percent of the contract amount, we are
+	// willing to pay
+	maxMarginPercent money.Decimal
+}
+
+// quote is what the server offered us when creating a contract, and what we
+// expected based on our own price
+type quote struct {
+	ourPrice      money.Decimal
+	serverPrice   money.Decimal
+	percentMargin money.Decimal
+
+	marginSat         int64
+	expectedMarginSat int64
+	initSat           int64
+	expectedInitSat   int64
+}
+
+// validate checks the quote against the policy. The quote is accepted if no
+// reasons to reject it are found.
+func (p quotePolicy) validate(q quote) *larpc.QuoteValidation {
+	validation := &larpc.QuoteValidation{
+		PriceDeviationPercent:    math.Inf(1),
+		MaxPriceDeviationPercent: p.maxPriceDeviation.Float64(),
+		MaxMarginPercent:         p.maxMarginPercent.Float64(),
+	}
+
+	reject := func(format string, args ...interface{}) {
+		validation.Reasons = append(validation.Reasons, fmt.Sprintf(format, args...))
+	}
+
+	if q.ourPrice.Sign() <= 0 || q.serverPrice.Sign() <= 0 {
+		reject("invalid price, server used %s and we have %s",
+			q.serverPrice, q.ourPrice)
+	} else if deviation, ok := percentDeviation(q.serverPrice, q.ourPrice); !ok {
+		reject("could not compare server price %s to our price %s",
+			q.serverPrice, q.ourPrice)
+	} else {
+		validation.PriceDeviationPercent = deviation.Float64()
+
+		if deviation.Cmp(p.maxPriceDeviation) > 0 {
+			reject("server price %s differs %s%% from our price %s, max is %s%%",
+				q.serverPrice, deviation, q.ourPrice, p.maxPriceDeviation)
+		}
+	}
+
+	if q.percentMargin.Cmp(p.maxMarginPercent) > 0 {
+		reject("margin of %s%% is above the max of %s%%",
+			q.percentMargin, p.maxMarginPercent)
+	}
+
+	if !p.withinDeviation(q.marginSat, q.expectedMarginSat) {
+		reject("margin invoice is for %d sats, expected %d sats",
+			q.marginSat, q.expectedMarginSat)
+	}
+
+	if !p.withinDeviation(q.initSat, q.expectedInitSat) {
+		reject("init invoice is for %d sats, expected %d sats",
+			q.initSat, q.expectedInitSat)
+	}
+
+	validation.Accepted = len(validation.Reasons) == 0
+
+	return validation
+}
+
+// withinDeviation returns true if an amount of sats differs at most the max
+// price deviation from what we expected
+func (p quotePolicy) withinDeviation(sat, expectedSat int64) bool {
+	deviation, ok := percentDeviation(money.NewDecimal(sat, 0),
+		money.NewDecimal(expectedSat, 0))
+
+	return ok && deviation.Cmp(p.maxPriceDeviation) <= 0
+}
+
+// percentDeviation returns how many percent actual differs from expected,
+// rounded up. It returns false if the deviation is infinite, ie if only
+// expected is zero, or does not fit in a decimal.
+func percentDeviation(actual, expected money.Decimal) (money.Decimal, bool) {
+	if actual.Cmp(expected) == 0 {
+		return money.Decimal{}, true
+	}
+	if expected.IsZero() {
+		return money.Decimal{}, false
+	}
+
+	diff, err := actual.Sub(expected)
+	if err != nil {
+		return money.Decimal{}, false
+	}
+	diff, err = diff.Mul(money.OneHundredPercent)
+	if err != nil {
+		return money.Decimal{}, false
+	}
+	deviation, err := diff.Quo(expected, deviationScale, money.RoundUp)
+	if err != nil {
+		return money.Decimal{}, false
+	}
+
+	// the deviation is a distance, so it is never negative
+	if deviation.Sign() < 0 {
+		deviation, err = money.Decimal{}.Sub(deviation)
+	}
+
+	return deviation, err == nil
+}
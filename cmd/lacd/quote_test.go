@@ -0,0 +1,164 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+func TestQuotePolicy(t *testing.T) {
+	policy := quotePolicy{
+		maxPriceDeviation: money.MustParseDecimal("2"),
+		maxMarginPercent:  money.MustParseDecimal("10"),
+	}
+
+	// a quote for 10 USD at 10000 USD/BTC with a 10% margin
+	valid := func() quote {
+		return quote{
+			ourPrice:          money.MustParseDecimal("10000"),
+			serverPrice:       money.MustParseDecimal("10000"),
+			percentMargin:     money.MustParseDecimal("10"),
+			marginSat:         10000,
+			expectedMarginSat: 10000,
+			initSat:           100000,
+			expectedInitSat:   100000,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		change       func(q *quote)
+		wantAccepted bool
+	}{
+		{
+			name:         "valid",
+			change:       func(q *quote) {},
+			wantAccepted: true,
+		},
+		{
+			name: "price at the max deviation",
+			change: func(q *quote) {
+				q.serverPrice = money.MustParseDecimal("10200")
+			},
+			wantAccepted: true,
+		},
+		{
+			name: "price just above the max deviation",
+			change: func(q *quote) {
+				q.serverPrice = money.MustParseDecimal("10200.01")
+			},
+		},
+		{
+			name: "price below ours",
+			change: func(q *quote) {
+				q.serverPrice = money.MustParseDecimal("9799.99")
+			},
+		},
+		{
+			name: "no price of our own",
+			change: func(q *quote) {
+				q.ourPrice = money.Decimal{}
+			},
+		},
+		{
+			name: "margin at the max",
+			change: func(q *quote) {
+				q.percentMargin = money.MustParseDecimal("10.00")
+			},
+			wantAccepted: true,
+		},
+		{
+			name: "margin above the max",
+			change: func(q *quote) {
+				q.percentMargin = money.MustParseDecimal("10.001")
+			},
+		},
+		{
+			name: "margin invoice too large",
+			change: func(q *quote) {
+				q.marginSat = 10201
+			},
+		},
+		{
+			name: "init invoice expected but missing",
+			change: func(q *quote) {
+				q.initSat = 0
+			},
+		},
+		{
+			name: "init invoice not expected",
+			change: func(q *quote) {
+				q.expectedInitSat = 0
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := valid()
+			test.change(&q)
+
+			validation := policy.validate(q)
+			if validation.Accepted != test.wantAccepted {
+				t.Fatalf("got accepted %t, want %t: %v", validation.Accepted,
+					test.wantAccepted, validation.Reasons)
+			}
+		})
+	}
+}
+
+func TestPercentDeviation(t *testing.T) {
+	tests := []struct {
+		actual   string
+		expected string
+		want     string
+		wantOk   bool
+	}{
+		{actual: "10000", expected: "10000", want: "0", wantOk: true},
+		{actual: "10200", expected: "10000", want: "2.0000", wantOk: true},
+		{actual: "9800", expected: "10000", want: "2.0000", wantOk: true},
+		{actual: "1", expected: "3", want: "66.6667", wantOk: true},
+		{actual: "0", expected: "0", want: "0", wantOk: true},
+		{actual: "1", expected: "0", wantOk: false},
+	}
+
+	for _, test := range tests {
+		got, ok := percentDeviation(money.MustParseDecimal(test.actual),
+			money.MustParseDecimal(test.expected))
+		if ok != test.wantOk {
+			t.Errorf("%s from %s: got ok %t, want %t", test.actual,
+				test.expected, ok, test.wantOk)
+			continue
+		}
+		if ok && got.String() != test.want {
+			t.Errorf("%s from %s: got %s%%, want %s%%", test.actual,
+				test.expected, got, test.want)
+		}
+	}
+}
+
+func TestPaymentTolerance(t *testing.T) {
+	tests := []struct {
+		owed    int64
+		percent string
+		want    int64
+	}{
+		{owed: 20000, percent: "1", want: 200},
+		{owed: 20001, percent: "1", want: 201},
+		{owed: 50, percent: "1", want: 1},
+		{owed: 20000, percent: "0.1", want: 20},
+		{owed: 20000, percent: "0", want: 0},
+	}
+
+	for _, test := range tests {
+		got, err := paymentTolerance(test.owed, money.MustParseDecimal(test.percent))
+		if err != nil {
+			t.Errorf("%s%% of %d sats: %v", test.percent, test.owed, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s%% of %d sats: got %d, want %d", test.percent,
+				test.owed, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+)
+
+// errRebalancePending is returned when a contract already has a rebalance
+// in progress
+var errRebalancePending = errors.New("contract has a pending rebalance")
+
+// rebalancer periodically settles the difference between the current value
+// of every open contract and the value it was last settled at
+type rebalancer struct {
+	client   AssetClient
+	interval time.Duration
+
+	// differences smaller than this are not worth a payment, and are
+	// settled in a later rebalance
+	minAmountSat int64
+}
+
+// Start runs the rebalancer until ctx is canceled
+func (r *rebalancer) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	log.Infof("rebalancing contracts every %s", r.interval)
+
+	for {
+		select {
+		case <-ticker.C:
+			r.rebalanceAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *rebalancer) rebalanceAll(ctx context.Context) {
+	// every rebalance needs the server and lnd, so there is no point in
+	// trying while either is down
+	for _, conn := range []*connectionState{r.client.server.connectionState,
+		r.client.lnd.connectionState} {
+		if err := conn.ready(); err != nil {
+			log.WithError(err).Warn("skipping rebalance")
+			return
+		}
+	}
+
+	// a rebalance interrupted by a crash is only resumed once we know
+	// what was paid
+	if err := r.client.reconciled.ready(); err != nil {
+		log.WithError(err).Warn("skipping rebalance")
+		return
+	}
+
+	contracts, err := r.client.db.ListContracts()
+	if err != nil {
+		log.WithError(err).Error("could not list contracts")
+		return
+	}
+
+	for _, contract := range contracts {
+		if contract.Status != larpc.ContractStatus_OPEN {
+			continue
+		}
+
+		if err := r.rebalance(ctx, *contract); err != nil {
+			log.WithError(err).WithField("uuid", contract.Uuid).
+				Error("could not rebalance contract")
+		}
+	}
+}
+
+// rebalance finishes any pending rebalance of the contract, or starts a new
+// one if the value of the contract has changed enough
+func (r *rebalancer) rebalance(ctx context.Context, contract larpc.ClientContract) error {
+	latest, err := r.client.db.LatestRebalance(contract.Uuid)
+	if err != nil {
+		return err
+	}
+	if latest != nil && latest.State == larpc.RebalanceState_REBALANCE_PENDING {
+		return r.resume(ctx, contract, *latest)
+	}
+
+	price, err := r.client.oracle.Price(contract.Asset)
+	if err != nil {
+		return fmt.Errorf("could not get price: %w", err)
+	}
+
+	amount, err := contractAmount(&contract)
+	if err != nil {
+		return fmt.Errorf("invalid amount of contract %s: %w", contract.Uuid, err)
+	}
+	fairValue, err := convertPercentOfAssetToSats(amount, price.Value,
+		money.OneHundredPercent)
+	if err != nil {
+		return err
+	}
+	diff := fairValue - contract.AmountSat
+
+	if abs(diff) < r.minAmountSat {
+		return nil
+	}
+
+	rebalance := larpc.Rebalance{
+		ContractUuid: contract.Uuid,
+		AssetPrice:   price.Value.Float64(),
+		AmountSat:    diff,
+		State:        larpc.RebalanceState_REBALANCE_PENDING,
+		CreatedAt:    time.Now().Unix(),
+
+		AssetPriceDecimal: price.Value.String(),
+	}
+
+	log.WithFields(logrus.Fields{
+		"uuid":      contract.Uuid,
+		"amountSat": diff,
+		"price":     price.Value.String(),
+	}).Info("rebalancing contract")
+
+	// persist the rebalance before talking to anyone, so we know what we
+	// were doing if we crash
+	if err := addRebalance(r.client.db, &rebalance); err != nil {
+		return err
+	}
+
+	if diff > 0 {
+		return r.receive(ctx, contract, rebalance)
+	}
+
+	return r.pay(ctx, contract, rebalance)
+}
+
+// receive asks the server to pay us the amount of the rebalance
+func (r *rebalancer) receive(ctx context.Context, contract larpc.ClientContract,
+	rebalance larpc.Rebalance) error {
+
+	if rebalance.PayReq == "" {
+		invoice, err := r.client.addContractInvoice(ctx, contract.Uuid,
+			rebalance.AmountSat, larpc.PaymentType_REBALANCE,
+			fmt.Sprintf("rebalance %d of contract %s", rebalance.Id, contract.Uuid))
+		if err != nil {
+			return err
+		}
+
+		rebalance.PayReq = invoice.PaymentRequest
+		if err := r.client.db.PutRebalance(&rebalance); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.rebalanceWithServer(ctx, &larpc.ServerRebalanceContractRequest{
+		Uuid:       contract.Uuid,
+		AssetPrice: rebalance.AssetPrice,
+		AmountSat:  rebalance.AmountSat,
+		PayReq:     rebalance.PayReq,
+
+		AssetPriceDecimal: rebalance.AssetPriceDecimal,
+	})
+	if err != nil {
+		return err
+	}
+
+	state, err := r.client.invoiceState(ctx, rebalance.PayReq)
+	if err != nil {
+		return err
+	}
+	if state != lnrpc.Invoice_SETTLED {
+		// the server has not paid yet, we check again next time
+		return nil
+	}
+
+	return completeRebalance(r.client.db, r.client.contractNotifier, rebalance)
+}
+
+// rebalanceWithServer signs req with our node, sends it to the server, and
+// verifies that the response is signed by the node of the server. The
+// invoice in the response is only trusted if it is.
+func (r *rebalancer) rebalanceWithServer(ctx context.Context,
+	req *larpc.ServerRebalanceContractRequest) (*larpc.ServerRebalanceContractResponse, error) {
+
+	sig, err := r.client.newSignature()
+	if err != nil {
+		return nil, err
+	}
+	req.Signature = sig
+	if err := r.client.sign(ctx, sig, req.SigningPayload()); err != nil {
+		return nil, err
+	}
+
+	res, err := r.client.server.server.RebalanceContract(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("server could not rebalance contract: %w", err)
+	}
+
+	err = r.client.verifyResponse(ctx, sig, res.Signature,
+		res.SigningPayload(req.Uuid))
+	if err != nil {
+		return nil, &untrustedResponseError{err: err}
+	}
+
+	return res, nil
+}
+
+// untrustedResponseError is returned when the server answered a rebalance,
+// but the response is not signed by the node of the server
+type untrustedResponseError struct {
+	err error
+}
+
+func (e *untrustedResponseError) Error() string {
+	return "untrusted rebalance response: " + e.err.Error()
+}
+
+func (e *untrustedResponseError) Unwrap() error {
+	return e.err
+}
+
+// pay asks the server for an invoice for the amount of the rebalance, and
+// pays it
+func (r *rebalancer) pay(ctx context.Context, contract larpc.ClientContract,
+	rebalance larpc.Rebalance) error {
+
+	if rebalance.PayReq == "" {
+		res, err := r.rebalanceWithServer(ctx, &larpc.ServerRebalanceContractRequest{
+			Uuid:       contract.Uuid,
+			AssetPrice: rebalance.AssetPrice,
+			AmountSat:  rebalance.AmountSat,
+
+			AssetPriceDecimal: rebalance.AssetPriceDecimal,
+		})
+		var untrusted *untrustedResponseError
+		if errors.As(err, &untrusted) {
+			// the invoice could be forged, so it is never paid
+			rebalance.State = larpc.RebalanceState_REBALANCE_FAILED
+			if err := r.client.db.PutRebalance(&rebalance); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("rebalance %d failed: %w", rebalance.Id, err)
+		}
+		if err != nil {
+			return err
+		}
+
+		invoice, err := r.client.lncli.DecodePayReq(ctx, &lnrpc.PayReqString{
+			PayReq: res.PayReq,
+		})
+		if err != nil {
+			return err
+		}
+
+		if invoice.NumSatoshis != -rebalance.AmountSat {
+			rebalance.State = larpc.RebalanceState_REBALANCE_FAILED
+			if err := r.client.db.PutRebalance(&rebalance); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("server requested %d sats, expected %d",
+				invoice.NumSatoshis, -rebalance.AmountSat)
+		}
+
+		rebalance.PayReq = res.PayReq
+		if err := r.client.db.PutRebalance(&rebalance); err != nil {
+			return err
+		}
+	}
+
+	// paying is safe to retry, an invoice we already paid is not paid again
+	_, err := r.client.payContractInvoice(ctx, contract.Uuid,
+		rebalance.PayReq, larpc.PaymentType_REBALANCE, r.client.paymentPolicy)
+	if paymentFailed(err) {
+		// give up on this rebalance, so the next one starts over with a
+		// fresh price and invoice
+		rebalance.State = larpc.RebalanceState_REBALANCE_FAILED
+		if err := r.client.db.PutRebalance(&rebalance); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("rebalance %d failed: %w", rebalance.Id, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return completeRebalance(r.client.db, r.client.contractNotifier, rebalance)
+}
+
+// resume continues a rebalance that was interrupted
+func (r *rebalancer) resume(ctx context.Context, contract larpc.ClientContract,
+	rebalance larpc.Rebalance) error {
+
+	log.WithFields(logrus.Fields{
+		"uuid": contract.Uuid,
+		"id":   rebalance.Id,
+	}).Info("resuming rebalance")
+
+	if rebalance.AmountSat > 0 {
+		// we have not created an invoice yet, so the server can not have
+		// paid us. Give up on this rebalance and start over with a fresh
+		// price.
+		if rebalance.PayReq == "" {
+			rebalance.State = larpc.RebalanceState_REBALANCE_FAILED
+			return r.client.db.PutRebalance(&rebalance)
+		}
+
+		state, err := r.client.invoiceState(ctx, rebalance.PayReq)
+		if err != nil {
+			return err
+		}
+
+		switch state {
+		case lnrpc.Invoice_SETTLED:
+			return completeRebalance(r.client.db, r.client.contractNotifier, rebalance)
+
+		// the invoice expired before the server paid it
+		case lnrpc.Invoice_CANCELED:
+			rebalance.State = larpc.RebalanceState_REBALANCE_FAILED
+			return r.client.db.PutRebalance(&rebalance)
+		}
+
+		return r.receive(ctx, contract, rebalance)
+	}
+
+	// we never got an invoice from the server, start over
+	if rebalance.PayReq == "" {
+		rebalance.State = larpc.RebalanceState_REBALANCE_FAILED
+		return r.client.db.PutRebalance(&rebalance)
+	}
+
+	return r.pay(ctx, contract, rebalance)
+}
+
+// completeRebalance marks the rebalance as completed and adds its amount to
+// the contract, in a single transaction. The contract is read within the
+// transaction, so changes made to it while we were paying are kept.
+func completeRebalance(db store.Store, contractNotifier *notifier,
+	rebalance larpc.Rebalance) error {
+
+	rebalance.State = larpc.RebalanceState_REBALANCE_COMPLETED
+	rebalance.CompletedAt = time.Now().Unix()
+
+	var contract *larpc.ClientContract
+	err := db.Update(func(tx store.Tx) error {
+		var err error
+		contract, err = tx.GetContract(rebalance.ContractUuid)
+		if err != nil {
+			return err
+		}
+
+		if contract.Status != larpc.ContractStatus_OPEN {
+			return fmt.Errorf("contract %s is %s, not open",
+				contract.Uuid, contract.Status)
+		}
+
+		contract.AmountSat += rebalance.AmountSat
+		contract.NumRebalances++
+
+		if err := tx.PutRebalance(&rebalance); err != nil {
+			return err
+		}
+
+		return tx.PutContract(contract)
+	})
+	if err != nil {
+		return fmt.Errorf("could not complete rebalance: %w", err)
+	}
+
+	log.WithFields(logrus.Fields{
+		"uuid":      contract.Uuid,
+		"amountSat": contract.AmountSat,
+	}).Info("rebalanced contract")
+
+	notifyContract(contractNotifier, larpc.ClientContractUpdate_REBALANCED, *contract)
+
+	return nil
+}
+
+// addRebalance stores rebalance as the next rebalance of its contract, and
+// sets its id. It fails with errRebalancePending if the contract already
+// has a rebalance in progress, so two rebalances never get the same id.
+func addRebalance(db store.Store, rebalance *larpc.Rebalance) error {
+	return db.Update(func(tx store.Tx) error {
+		latest, err := tx.LatestRebalance(rebalance.ContractUuid)
+		if err != nil {
+			return err
+		}
+		if latest != nil && latest.State == larpc.RebalanceState_REBALANCE_PENDING {
+			return errRebalancePending
+		}
+
+		rebalance.Id = nextRebalanceID(latest)
+		return tx.PutRebalance(rebalance)
+	})
+}
+
+// nextRebalanceID returns the id following the latest rebalance
+func nextRebalanceID(latest *larpc.Rebalance) int64 {
+	if latest == nil {
+		return 1
+	}
+
+	return latest.Id + 1
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
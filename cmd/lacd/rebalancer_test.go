@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+)
+
+func TestAddRebalance(t *testing.T) {
+	db := store.NewMemoryStore()
+
+	first := larpc.Rebalance{ContractUuid: "a",
+		State: larpc.RebalanceState_REBALANCE_PENDING}
+	if err := addRebalance(db, &first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Id != 1 {
+		t.Fatalf("first rebalance got id %d, want 1", first.Id)
+	}
+
+	second := larpc.Rebalance{ContractUuid: "a",
+		State: larpc.RebalanceState_REBALANCE_PENDING}
+	if err := addRebalance(db, &second); !errors.Is(err, errRebalancePending) {
+		t.Fatalf("got error %v with a pending rebalance, want %v", err,
+			errRebalancePending)
+	}
+
+	first.State = larpc.RebalanceState_REBALANCE_FAILED
+	if err := db.PutRebalance(&first); err != nil {
+		t.Fatal(err)
+	}
+	if err := addRebalance(db, &second); err != nil {
+		t.Fatal(err)
+	}
+	if second.Id != 2 {
+		t.Fatalf("second rebalance got id %d, want 2", second.Id)
+	}
+}
+
+func TestCompleteRebalance(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        larpc.ContractStatus
+		wantErr       bool
+		wantAmountSat int64
+	}{
+		{
+			name:          "open contract",
+			status:        larpc.ContractStatus_OPEN,
+			wantAmountSat: 1500,
+		},
+		{
+			name:          "contract closed while paying",
+			status:        larpc.ContractStatus_CLOSED,
+			wantErr:       true,
+			wantAmountSat: 1200,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := store.NewMemoryStore()
+
+			// the contract was updated after the rebalance started
+			contract := &larpc.ClientContract{
+				Uuid:      "a",
+				Status:    test.status,
+				AmountSat: 1200,
+			}
+			if err := db.PutContract(contract); err != nil {
+				t.Fatal(err)
+			}
+
+			rebalance := larpc.Rebalance{ContractUuid: "a", Id: 1,
+				AmountSat: 300, State: larpc.RebalanceState_REBALANCE_PENDING}
+			if err := db.PutRebalance(&rebalance); err != nil {
+				t.Fatal(err)
+			}
+
+			err := completeRebalance(db, newNotifier(1), rebalance)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+
+			stored, err := db.GetContract("a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status != test.status {
+				t.Errorf("contract is %s, want %s", stored.Status, test.status)
+			}
+			if stored.AmountSat != test.wantAmountSat {
+				t.Errorf("contract has %d sats, want %d", stored.AmountSat,
+					test.wantAmountSat)
+			}
+
+			latest, err := db.LatestRebalance("a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			completed := latest.State == larpc.RebalanceState_REBALANCE_COMPLETED
+			if completed == test.wantErr {
+				t.Errorf("rebalance is %s", latest.State)
+			}
+		})
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	tests := []struct {
+		name          string
+		price         string
+		wantAmountSat int64
+	}{
+		{
+			name:          "we owe the server",
+			price:         "12500",
+			wantAmountSat: 80000,
+		},
+		{
+			name:          "the server owes us",
+			price:         "8000",
+			wantAmountSat: 125000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			contract := h.openContract()
+			h.setPrice("USD", money.MustParseDecimal(test.price))
+
+			r := &rebalancer{client: *h.asset, minAmountSat: 1}
+			if err := r.rebalance(h.ctx, *contract); err != nil {
+				t.Fatal(err)
+			}
+
+			latest, err := h.asset.db.LatestRebalance(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if latest.State != larpc.RebalanceState_REBALANCE_COMPLETED {
+				t.Fatalf("rebalance is %s, want %s", latest.State,
+					larpc.RebalanceState_REBALANCE_COMPLETED)
+			}
+
+			stored, err := h.asset.db.GetContract(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.AmountSat != test.wantAmountSat {
+				t.Fatalf("contract has %d sats, want %d", stored.AmountSat,
+					test.wantAmountSat)
+			}
+		})
+	}
+}
+
+func TestRebalanceFailedPayment(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(h *testHarness)
+	}{
+		{
+			name: "lnd gives up",
+			prepare: func(h *testHarness) {
+				h.clientNode.FailNextPayment("no route to server")
+			},
+		},
+		{
+			name: "response from another node",
+			prepare: func(h *testHarness) {
+				h.asset.identity.configured = h.clientNode.Pubkey
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHarness(t)
+			defer h.stop()
+
+			contract := h.openContract()
+
+			// the price went up, so we owe the server
+			h.setPrice("USD", money.MustParseDecimal("12500"))
+
+			test.prepare(h)
+			r := &rebalancer{client: *h.asset, minAmountSat: 1}
+
+			err := r.rebalance(h.ctx, *contract)
+			if err == nil {
+				t.Fatal("rebalance succeeded")
+			}
+
+			latest, err := h.asset.db.LatestRebalance(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if latest.State != larpc.RebalanceState_REBALANCE_FAILED {
+				t.Fatalf("rebalance is %s, want %s", latest.State,
+					larpc.RebalanceState_REBALANCE_FAILED)
+			}
+
+			// the next tick starts over with a new rebalance
+			h.asset.identity.configured = ""
+			if err := r.rebalance(h.ctx, *contract); err != nil {
+				t.Fatal(err)
+			}
+
+			latest, err = h.asset.db.LatestRebalance(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if latest.Id != 2 || latest.State != larpc.RebalanceState_REBALANCE_COMPLETED {
+				t.Fatalf("got rebalance %d %s, want rebalance 2 %s", latest.Id,
+					latest.State, larpc.RebalanceState_REBALANCE_COMPLETED)
+			}
+
+			stored, err := h.asset.db.GetContract(contract.Uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.AmountSat != 80000 {
+				t.Fatalf("contract has %d sats, want 80000", stored.AmountSat)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// newRESTRouter returns the router of the REST server. grpc-web requests are
+// passed straight to the gRPC server, while plain JSON requests go through
+// the grpc-gateway, which calls the gRPC server at grpcAddress like any
+// other client. This way every request is authenticated the same way.
+func newRESTRouter(ctx context.Context, grpcServer *grpc.Server,
+	grpcAddress, certPath string) (*mux.Router, error) {
+
+	creds, err := credentials.NewClientTLSFromFile(certPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("could not load TLS certificate: %w", err)
+	}
+
+	// macaroons are passed on to the gRPC server from the
+	// Grpc-Metadata-Macaroon header
+	gateway := runtime.NewServeMux()
+	err = larpc.RegisterAssetClientHandlerFromEndpoint(ctx, gateway, grpcAddress,
+		[]grpc.DialOption{grpc.WithTransportCredentials(creds)})
+	if err != nil {
+		return nil, fmt.Errorf("could not register REST handlers: %w", err)
+	}
+
+	wrappedGrpc := grpcweb.WrapServer(grpcServer)
+
+	router := mux.NewRouter()
+	router.Use(headerMiddleware)
+
+	router.Path("/swagger.json").HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(larpc.ClientSwagger))
+		})
+
+	router.PathPrefix("/").HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if wrappedGrpc.IsGrpcWebRequest(r) ||
+				wrappedGrpc.IsAcceptableGrpcCorsRequest(r) {
+
+				wrappedGrpc.ServeHTTP(w, r)
+				return
+			}
+
+			gateway.ServeHTTP(w, r)
+		})
+
+	return router, nil
+}
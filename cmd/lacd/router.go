@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// paymentError is returned when lnd gave up paying an invoice, or when we
+// did not try as the payment could not succeed. rpcs return it as a status
+// with a larpc.PaymentError attached, so callers can tell why.
+type paymentError struct {
+	hash   string
+	reason larpc.PaymentFailureReason
+	msg    string
+}
+
+func (e *paymentError) Error() string {
+	return fmt.Sprintf("could not pay %s: %s", e.hash, e.msg)
+}
+
+// GRPCStatus is used by grpc to turn the error into a status
+func (e *paymentError) GRPCStatus() *status.Status {
+	code := codes.FailedPrecondition
+	switch e.reason {
+	case larpc.PaymentFailureReason_FAILURE_REASON_TIMEOUT:
+		code = codes.DeadlineExceeded
+	case larpc.PaymentFailureReason_FAILURE_REASON_ERROR:
+		code = codes.Unknown
+	}
+
+	s, err := status.New(code, e.Error()).WithDetails(&larpc.PaymentError{
+		PaymentHash: e.hash,
+		Reason:      e.reason,
+		Message:     e.msg,
+	})
+	if err != nil {
+		return status.New(code, e.Error())
+	}
+
+	return s
+}
+
+// retryable returns true if paying again could succeed
+func (e *paymentError) retryable() bool {
+	switch e.reason {
+	case larpc.PaymentFailureReason_FAILURE_REASON_TIMEOUT,
+		larpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+		larpc.PaymentFailureReason_FAILURE_REASON_ERROR:
+		return true
+	}
+
+	return false
+}
+
+// failureReasons maps the final states of a payment in lnd to why it failed
+var failureReasons = map[routerrpc.PaymentState]larpc.PaymentFailureReason{
+	routerrpc.PaymentState_FAILED_TIMEOUT:                   larpc.PaymentFailureReason_FAILURE_REASON_TIMEOUT,
+	routerrpc.PaymentState_FAILED_NO_ROUTE:                  larpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+	routerrpc.PaymentState_FAILED_ERROR:                     larpc.PaymentFailureReason_FAILURE_REASON_ERROR,
+	routerrpc.PaymentState_FAILED_INCORRECT_PAYMENT_DETAILS: larpc.PaymentFailureReason_FAILURE_REASON_INCORRECT_PAYMENT_DETAILS,
+}
+
+// paymentStream is the stream of updates lnd sends about a payment, both
+// when sending and tracking it
+type paymentStream interface {
+	Recv() (*routerrpc.PaymentStatus, error)
+}
+
+// followPayment records the progress of payment as lnd reports it, until
+// lnd has either completed the payment or given up. If the stream breaks
+// before that, the payment may still be in flight, and has to be tracked
+// again.
+func (a AssetClient) followPayment(stream paymentStream, payment *larpc.Payment) error {
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("lost track of payment %s: %w", payment.PaymentHash, err)
+		}
+
+		switch update.State {
+		case routerrpc.PaymentState_IN_FLIGHT:
+			if payment.State == larpc.PaymentState_PAYMENT_IN_FLIGHT {
+				continue
+			}
+			payment.State = larpc.PaymentState_PAYMENT_IN_FLIGHT
+
+			if err := savePayment(a.db, a.paymentNotifier, *payment); err != nil {
+				return err
+			}
+
+		case routerrpc.PaymentState_SUCCEEDED:
+			payment.State = larpc.PaymentState_PAYMENT_SUCCEEDED
+			payment.FailureReason = larpc.PaymentFailureReason_FAILURE_REASON_NONE
+			payment.Preimage = hex.EncodeToString(update.Preimage)
+			payment.Settled = true
+			payment.SettledAt = time.Now().Unix()
+			if update.Route != nil {
+				payment.FeeSat = update.Route.TotalFees
+			}
+
+			return savePayment(a.db, a.paymentNotifier, *payment)
+
+		default:
+			reason, ok := failureReasons[update.State]
+			if !ok {
+				reason = larpc.PaymentFailureReason_FAILURE_REASON_ERROR
+			}
+
+			return a.failPayment(payment, &paymentError{
+				hash:   payment.PaymentHash,
+				reason: reason,
+				msg:    fmt.Sprintf("lnd failed the payment: %s", update.State),
+			})
+		}
+	}
+}
+
+// failPayment records that payment failed, and returns why
+func (a AssetClient) failPayment(payment *larpc.Payment, paymentErr *paymentError) error {
+	payment.State = larpc.PaymentState_PAYMENT_FAILED
+	payment.FailureReason = paymentErr.reason
+
+	if err := savePayment(a.db, a.paymentNotifier, *payment); err != nil {
+		return err
+	}
+
+	return paymentErr
+}
+
+// trackPayment follows a payment lnd is already making, ie one we started
+// before we were restarted, until it completes or fails
+func (a AssetClient) trackPayment(ctx context.Context, payment *larpc.Payment) error {
+	hash, err := hex.DecodeString(payment.PaymentHash)
+	if err != nil {
+		return fmt.Errorf("invalid payment hash %q: %w", payment.PaymentHash, err)
+	}
+
+	stream, err := a.router.TrackPayment(ctx, &routerrpc.TrackPaymentRequest{
+		PaymentHash: hash,
+	})
+	if err != nil {
+		return fmt.Errorf("could not track payment %s: %w", payment.PaymentHash, err)
+	}
+
+	return a.followPayment(stream, payment)
+}
+
+// trackInFlightPayment tracks a payment in the background, logging how it
+// ends
+func (a AssetClient) trackInFlightPayment(ctx context.Context, payment larpc.Payment) {
+	log.WithField("hash", payment.PaymentHash).Info("tracking payment in flight")
+
+	err := a.trackPayment(ctx, &payment)
+	var paymentErr *paymentError
+	switch {
+	case errors.As(err, &paymentErr):
+		log.WithError(err).WithField("hash", payment.PaymentHash).
+			Warn("payment in flight failed")
+	case err != nil:
+		log.WithError(err).WithField("hash", payment.PaymentHash).
+			Error("could not track payment in flight")
+	default:
+		log.WithField("hash", payment.PaymentHash).
+			Info("payment in flight succeeded")
+	}
+}
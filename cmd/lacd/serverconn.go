@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+const (
+	// how often we check that the server is serving
+	serverHealthCheckInterval = 30 * time.Second
+	serverHealthCheckTimeout  = 10 * time.Second
+
+	// grpc servers refuse keepalive pings more frequent than every five
+	// minutes by default, the health checks detect a dead server sooner
+	serverKeepaliveTime    = 5 * time.Minute
+	serverKeepaliveTimeout = 20 * time.Second
+)
+
+// how long we wait between attempts to connect to the server
+var serverBackoff = backoff.Config{
+	BaseDelay:  1 * time.Second,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   1 * time.Minute,
+}
+
+// grpcServerConnection is the connection to the asset server. The connection
+// is never given up on, grpc keeps reconnecting with an exponential backoff
+// while the server is unreachable. Its state is tracked, so operations that
+// need the server can be rejected while it is down.
+type grpcServerConnection struct {
+	*connectionState
+
+	server larpc.AssetServerClient
+	conn   *grpc.ClientConn
+	health grpc_health_v1.HealthClient
+}
+
+// newServerConnection opens a connection to the asset server. It does not
+// wait for the server to be reachable, lacd runs in a degraded mode until
+// it is.
+func newServerConnection(address string, insecure bool,
+	tlsPath string) (*grpcServerConnection, func(), error) {
+
+	opts := []grpc.DialOption{
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           serverBackoff,
+			MinConnectTimeout: serverHealthCheckTimeout,
+		}),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    serverKeepaliveTime,
+			Timeout: serverKeepaliveTimeout,
+		}),
+	}
+
+	// There are three options to connect to an asset server, either insecure,
+	// using a self-signed certificate or with a certificate signed by a
+	// public CA.
+	switch {
+	case insecure:
+		opts = append(opts, grpc.WithInsecure())
+
+	case tlsPath != "":
+		// Load the specified TLS certificate and build
+		// transport credentials
+		creds, err := credentials.NewClientTLSFromFile(tlsPath, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+
+	default:
+		creds := credentials.NewTLS(&tls.Config{})
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	}
+
+	serverConn, err := grpc.Dial(address, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to connect to RPC server: %v",
+			err)
+	}
+
+	cleanUp := func() {
+		serverConn.Close()
+	}
+
+	conn := &grpcServerConnection{
+		connectionState: newConnectionState("asset server", address),
+		server:          larpc.NewAssetServerClient(serverConn),
+		conn:            serverConn,
+		health:          grpc_health_v1.NewHealthClient(serverConn),
+	}
+
+	return conn, cleanUp, nil
+}
+
+// monitor keeps the state of the connection up to date until ctx is
+// canceled, by watching the grpc connection and periodically checking the
+// health of the server
+func (s *grpcServerConnection) monitor(ctx context.Context) {
+	go s.watchConnectivity(ctx)
+
+	ticker := time.NewTicker(serverHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkHealth(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watchConnectivity updates the state of the connection every time the
+// state of the grpc connection changes
+func (s *grpcServerConnection) watchConnectivity(ctx context.Context) {
+	state := s.conn.GetState()
+
+	for {
+		switch state {
+		// the server is reachable, but we only consider ourselves
+		// connected once it tells us it is serving. An idle connection
+		// is only reconnected by a request, which the check also is.
+		case connectivity.Ready, connectivity.Idle:
+			s.checkHealth(ctx)
+
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			s.setState(larpc.ConnectionState_DISCONNECTED,
+				fmt.Errorf("connection is %s", state))
+		}
+
+		if !s.conn.WaitForStateChange(ctx, state) {
+			return
+		}
+		state = s.conn.GetState()
+	}
+}
+
+// checkHealth asks the server whether it is serving, using the standard grpc
+// health checking protocol
+func (s *grpcServerConnection) checkHealth(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, serverHealthCheckTimeout)
+	defer cancel()
+
+	res, err := s.health.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	switch {
+	case ctx.Err() != nil && ctx.Err() != context.DeadlineExceeded:
+		// we are shutting down
+
+	// a server without health checks answering at all is good enough
+	case status.Code(err) == codes.Unimplemented:
+		s.setState(larpc.ConnectionState_CONNECTED, nil)
+
+	case err != nil:
+		s.setState(larpc.ConnectionState_DISCONNECTED, err)
+
+	case res.Status != grpc_health_v1.HealthCheckResponse_SERVING:
+		s.setState(larpc.ConnectionState_DISCONNECTED,
+			fmt.Errorf("server is %s", res.Status))
+
+	default:
+		s.setState(larpc.ConnectionState_CONNECTED, nil)
+	}
+}
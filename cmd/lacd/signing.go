@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// newSignature creates the signature of a request to the server, without the
+// signature itself. It is filled in by sign once the request is complete.
+func (a AssetClient) newSignature() (*larpc.MessageSignature, error) {
+	pubkey := a.lnd.nodePubkey()
+	if pubkey == "" {
+		return nil, status.Error(codes.Unavailable,
+			"can not sign requests before lnd is reached")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("could not create nonce: %w", err)
+	}
+
+	return &larpc.MessageSignature{
+		NodePubkey: pubkey,
+		Timestamp:  time.Now().Unix(),
+		Nonce:      hex.EncodeToString(nonce),
+	}, nil
+}
+
+// sign signs payload with our lnd node, and stores the signature in sig
+func (a AssetClient) sign(ctx context.Context, sig *larpc.MessageSignature, payload []byte) error {
+	res, err := a.lncli.SignMessage(ctx, &lnrpc.SignMessageRequest{
+		Msg: payload,
+	})
+	if err != nil {
+		return fmt.Errorf("could not sign request: %w", err)
+	}
+
+	sig.Signature = res.Signature
+	return nil
+}
+
+// verifyResponse checks that a response to the request signed with reqSig is
+// signed by the node of the server, and made for our request. Responses
+// without a signature are rejected.
+func (a AssetClient) verifyResponse(ctx context.Context, reqSig, resSig *larpc.MessageSignature, payload []byte) error {
+	if resSig.GetSignature() == "" {
+		return status.Error(codes.Unauthenticated,
+			"server response is not signed")
+	}
+
+	// the nonce of the request is echoed, so an old response can not be
+	// replayed as the answer to a new request
+	if resSig.Nonce != reqSig.Nonce {
+		return status.Error(codes.Unauthenticated,
+			"server response is signed for another request")
+	}
+
+	age := time.Since(time.Unix(resSig.Timestamp, 0))
+	if age > larpc.MaxSignatureAge || age < -larpc.MaxSignatureAge {
+		return status.Errorf(codes.Unauthenticated,
+			"server response is signed %v from now, at most %v is allowed",
+			age.Round(time.Second), larpc.MaxSignatureAge)
+	}
+
+	res, err := a.lncli.VerifyMessage(ctx, &lnrpc.VerifyMessageRequest{
+		Msg:       payload,
+		Signature: resSig.Signature,
+	})
+	if err != nil {
+		return fmt.Errorf("could not verify server response: %w", err)
+	}
+	if !res.Valid || res.Pubkey != resSig.NodePubkey {
+		return status.Errorf(codes.Unauthenticated,
+			"server response has an invalid signature by %s", resSig.NodePubkey)
+	}
+
+	return a.identity.verify(resSig.NodePubkey)
+}
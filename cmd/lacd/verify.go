@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+// verifyPaymentRequest checks that an invoice the server wants us to pay is
+// for a contract we have open, pays the node that created the contract and
+// is for the amount we owe according to our own price, within the tolerance
+// of the client. It returns the decoded invoice if everything checks out,
+// and a gRPC status error if not.
+func (a AssetClient) verifyPaymentRequest(ctx context.Context,
+	contract *larpc.ClientContract, paymentRequest string) (*lnrpc.PayReq, money.Decimal, error) {
+
+	if contract.Status != larpc.ContractStatus_OPEN {
+		return nil, money.Decimal{}, status.Errorf(codes.FailedPrecondition,
+			"contract %s is not open", contract.Uuid)
+	}
+
+	invoice, err := a.lncli.DecodePayReq(ctx, &lnrpc.PayReqString{
+		PayReq: paymentRequest,
+	})
+	if err != nil {
+		return nil, money.Decimal{}, status.Errorf(codes.InvalidArgument,
+			"could not decode payment request: %v", err)
+	}
+
+	if contract.ServerPubkey == "" || invoice.Destination != contract.ServerPubkey {
+		return nil, money.Decimal{}, status.Errorf(codes.PermissionDenied,
+			"payment request pays %s, expected server node %s",
+			invoice.Destination, contract.ServerPubkey)
+	}
+
+	price, err := a.oracle.Price(contract.Asset)
+	if err != nil {
+		return nil, money.Decimal{}, status.Errorf(codes.Unavailable,
+			"could not get price: %v", err)
+	}
+
+	// we owe the server the amount the contract has lost in value since it
+	// was last settled
+	amount, err := contractAmount(contract)
+	if err != nil {
+		return nil, money.Decimal{}, status.Errorf(codes.Internal,
+			"invalid amount of contract %s: %v", contract.Uuid, err)
+	}
+	fairValue, err := convertPercentOfAssetToSats(amount, price.Value,
+		money.OneHundredPercent)
+	if err != nil {
+		return nil, money.Decimal{}, status.Error(codes.Internal, err.Error())
+	}
+	owed := contract.AmountSat - fairValue
+
+	if owed <= 0 {
+		return nil, money.Decimal{}, status.Errorf(codes.InvalidArgument,
+			"we do not owe the server anything, contract is worth %d sats "+
+				"and was settled at %d sats", fairValue, contract.AmountSat)
+	}
+
+	tolerance, err := paymentTolerance(owed, a.paymentTolerance)
+	if err != nil {
+		return nil, money.Decimal{}, status.Error(codes.Internal, err.Error())
+	}
+	if invoice.NumSatoshis <= 0 || abs(invoice.NumSatoshis-owed) > tolerance {
+		return nil, money.Decimal{}, status.Errorf(codes.InvalidArgument,
+			"payment request is for %d sats, expected %d sats (+/- %d)",
+			invoice.NumSatoshis, owed, tolerance)
+	}
+
+	return invoice, price.Value, nil
+}
+
+// paymentTolerance returns how many sats a payment of owed sats requested by
+// the server may differ from it, when it may differ percent percent. It is
+// rounded up, so small payments may differ a sat.
+func paymentTolerance(owed int64, percent money.Decimal) (int64, error) {
+	tolerance, err := money.NewDecimal(owed, 0).Mul(percent)
+	if err != nil {
+		return 0, err
+	}
+	tolerance, err = tolerance.Quo(money.OneHundredPercent, 0, money.RoundUp)
+	if err != nil {
+		return 0, err
+	}
+
+	return tolerance.Units(), nil
+}
@@ -0,0 +1,66 @@
+package lactest
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// the size of the in-memory connection buffer
+const bufSize = 1024 * 1024
+
+// Serve starts a grpc server listening on an in-memory connection, and
+// returns a client connection to it. register is called to register services
+// on the server before it starts. The returned function stops the server and
+// closes the connection.
+func Serve(register func(*grpc.Server), opts ...grpc.ServerOption) (*grpc.ClientConn, func(), error) {
+	listener := bufconn.Listen(bufSize)
+
+	server := grpc.NewServer(opts...)
+	register(server)
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.WithError(err).Error("in-memory grpc server stopped")
+		}
+	}()
+
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.Dial()
+	}
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(dialer), grpc.WithInsecure())
+	if err != nil {
+		server.Stop()
+		return nil, nil, fmt.Errorf("could not connect to in-memory server: %w", err)
+	}
+
+	stop := func() {
+		conn.Close()
+		server.Stop()
+	}
+
+	return conn, stop, nil
+}
+
+// StartAssetServer serves s in memory, together with the standard grpc
+// health service, and returns a client connected to it
+func StartAssetServer(s larpc.AssetServerServer) (larpc.AssetServerClient, func(), error) {
+	conn, stop, err := Serve(func(server *grpc.Server) {
+		larpc.RegisterAssetServerServer(server, s)
+		grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return larpc.NewAssetServerClient(conn), stop, nil
+}
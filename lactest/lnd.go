@@ -0,0 +1,660 @@
+// Package lactest contains fakes of the asset server, lnd and the price
+// feeds, for running lacd in-process without any external dependencies.
+package lactest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logrus.New()
+
+// defaultInvoiceExpiry is the expiry of invoices created without one, in
+// seconds
+const defaultInvoiceExpiry = 3600
+
+// Network connects fake lnd nodes, so invoices created by one node can be
+// paid by any other node in the network
+type Network struct {
+	mu       sync.Mutex
+	nodes    []*Node
+	invoices map[string]*invoice
+}
+
+// NewNetwork creates an empty network
+func NewNetwork() *Network {
+	return &Network{
+		invoices: make(map[string]*invoice),
+	}
+}
+
+// NewNode adds a node with the given channel balance to the network
+func (n *Network) NewNode(alias string, balanceSat int64) *Node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	pubkey := sha256.Sum256([]byte(alias))
+
+	node := &Node{
+		network:     n,
+		Pubkey:      "02" + hex.EncodeToString(pubkey[:]),
+		Alias:       alias,
+		balance:     balanceSat,
+		synced:      true,
+		blockHeight: 100,
+	}
+	n.nodes = append(n.nodes, node)
+
+	return node
+}
+
+type invoice struct {
+	dest           *Node
+	paymentRequest string
+	memo           string
+	preimage       []byte
+	hash           []byte
+	valueSat       int64
+	createdAt      int64
+	expiry         int64
+	state          lnrpc.Invoice_InvoiceState
+	settledAt      int64
+	addIndex       uint64
+	settleIndex    uint64
+}
+
+func (i *invoice) toRPC() *lnrpc.Invoice {
+	return &lnrpc.Invoice{
+		Memo:           i.memo,
+		RPreimage:      i.preimage,
+		RHash:          i.hash,
+		Value:          i.valueSat,
+		Settled:        i.state == lnrpc.Invoice_SETTLED,
+		CreationDate:   i.createdAt,
+		SettleDate:     i.settledAt,
+		PaymentRequest: i.paymentRequest,
+		Expiry:         i.expiry,
+		AmtPaidSat:     i.paidSat(),
+		AmtPaidMsat:    i.paidSat() * 1000,
+		State:          i.state,
+	}
+}
+
+func (i *invoice) paidSat() int64 {
+	if i.state != lnrpc.Invoice_SETTLED {
+		return 0
+	}
+	return i.valueSat
+}
+
+func (i *invoice) expired() bool {
+	return time.Now().Unix() >= i.createdAt+i.expiry
+}
+
+// Node is a fake lnd node. It implements the parts of lnrpc.LightningClient
+// lacd uses, calling any other method panics.
+type Node struct {
+	lnrpc.LightningClient
+
+	network *Network
+
+	Pubkey string
+	Alias  string
+
+	mu          sync.Mutex
+	balance     int64
+	feeSat      int64
+	synced      bool
+	blockHeight uint32
+	payments    []*lnrpc.Payment
+	subscribers []chan *lnrpc.Invoice
+	nextIndex   uint64
+
+	// failures scripted for the next payments, see FailNextPayment,
+	// LoseNextPaymentResponse and HoldNextPayment
+	paymentFailures []paymentFailure
+
+	// completes the payments held in flight
+	held []func()
+}
+
+type paymentFailure struct {
+	// reason is returned as the payment error, and the payment fails
+	reason string
+	// err is returned after the payment succeeded, as if the connection
+	// to lnd was lost before the response arrived
+	err error
+	// hold keeps the payment in flight until ReleasePayments is called
+	hold bool
+}
+
+var _ lnrpc.LightningClient = (*Node)(nil)
+
+// Balance returns the channel balance of the node
+func (n *Node) Balance() int64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.balance
+}
+
+// SetFee sets the routing fee paid for every payment the node makes
+func (n *Node) SetFee(feeSat int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.feeSat = feeSat
+}
+
+// SetSynced sets whether the node reports to be synced to the chain
+func (n *Node) SetSynced(synced bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.synced = synced
+}
+
+// FailNextPayment makes the next payment of the node fail with reason
+func (n *Node) FailNextPayment(reason string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.paymentFailures = append(n.paymentFailures, paymentFailure{reason: reason})
+}
+
+// LoseNextPaymentResponse makes the next payment of the node succeed, but
+// return an error to the caller, as if lnd went away before responding
+func (n *Node) LoseNextPaymentResponse() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.paymentFailures = append(n.paymentFailures, paymentFailure{
+		err: status.Error(codes.Unavailable, "connection to lnd lost"),
+	})
+}
+
+// HoldNextPayment keeps the next payment of the node in flight until
+// ReleasePayments is called
+func (n *Node) HoldNextPayment() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.paymentFailures = append(n.paymentFailures, paymentFailure{hold: true})
+}
+
+// ReleasePayments completes the payments held in flight
+func (n *Node) ReleasePayments() {
+	n.mu.Lock()
+	held := n.held
+	n.held = nil
+	n.mu.Unlock()
+
+	for _, complete := range held {
+		complete()
+	}
+}
+
+// Payments returns all payments the node has attempted
+func (n *Node) Payments() []*lnrpc.Payment {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]*lnrpc.Payment(nil), n.payments...)
+}
+
+func (n *Node) GetInfo(ctx context.Context, in *lnrpc.GetInfoRequest,
+	opts ...grpc.CallOption) (*lnrpc.GetInfoResponse, error) {
+
+	n.network.mu.Lock()
+	defer n.network.mu.Unlock()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return &lnrpc.GetInfoResponse{
+		IdentityPubkey:    n.Pubkey,
+		Alias:             n.Alias,
+		NumActiveChannels: uint32(len(n.network.nodes) - 1),
+		BlockHeight:       n.blockHeight,
+		SyncedToChain:     n.synced,
+		Version:           "0.8.2-beta lactest",
+	}, nil
+}
+
+func (n *Node) AddInvoice(ctx context.Context, in *lnrpc.Invoice,
+	opts ...grpc.CallOption) (*lnrpc.AddInvoiceResponse, error) {
+
+	if in.Value < 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount can not be negative")
+	}
+
+	preimage := make([]byte, 32)
+	if _, err := rand.Read(preimage); err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(preimage)
+
+	expiry := in.Expiry
+	if expiry == 0 {
+		expiry = defaultInvoiceExpiry
+	}
+
+	n.mu.Lock()
+	n.nextIndex++
+	addIndex := n.nextIndex
+	n.mu.Unlock()
+
+	inv := &invoice{
+		dest: n,
+		paymentRequest: fmt.Sprintf("lnfake%d%s%s", in.Value,
+			n.Pubkey[:8], hex.EncodeToString(hash[:8])),
+		memo:      in.Memo,
+		preimage:  preimage,
+		hash:      hash[:],
+		valueSat:  in.Value,
+		createdAt: time.Now().Unix(),
+		expiry:    expiry,
+		state:     lnrpc.Invoice_OPEN,
+		addIndex:  addIndex,
+	}
+
+	n.network.mu.Lock()
+	n.network.invoices[inv.paymentRequest] = inv
+	n.network.mu.Unlock()
+
+	return &lnrpc.AddInvoiceResponse{
+		RHash:          inv.hash,
+		PaymentRequest: inv.paymentRequest,
+		AddIndex:       addIndex,
+	}, nil
+}
+
+// ExpireInvoice makes an invoice of the node expire, as if its expiry had
+// passed
+func (n *Node) ExpireInvoice(paymentRequest string) error {
+	n.network.mu.Lock()
+	defer n.network.mu.Unlock()
+
+	inv, ok := n.network.invoices[paymentRequest]
+	if !ok || inv.dest != n {
+		return errors.New("invoice not found")
+	}
+
+	inv.createdAt = time.Now().Unix() - inv.expiry
+	if inv.state == lnrpc.Invoice_OPEN {
+		inv.state = lnrpc.Invoice_CANCELED
+	}
+
+	return nil
+}
+
+func (n *Node) DecodePayReq(ctx context.Context, in *lnrpc.PayReqString,
+	opts ...grpc.CallOption) (*lnrpc.PayReq, error) {
+
+	n.network.mu.Lock()
+	defer n.network.mu.Unlock()
+
+	inv, ok := n.network.invoices[in.PayReq]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"invalid payment request %q", in.PayReq)
+	}
+
+	return &lnrpc.PayReq{
+		Destination: inv.dest.Pubkey,
+		PaymentHash: hex.EncodeToString(inv.hash),
+		NumSatoshis: inv.valueSat,
+		Timestamp:   inv.createdAt,
+		Expiry:      inv.expiry,
+		Description: inv.memo,
+		CltvExpiry:  40,
+	}, nil
+}
+
+func (n *Node) SendPaymentSync(ctx context.Context, in *lnrpc.SendRequest,
+	opts ...grpc.CallOption) (*lnrpc.SendResponse, error) {
+
+	feeLimit := int64(-1)
+	if in.FeeLimit != nil {
+		if limit, ok := in.FeeLimit.Limit.(*lnrpc.FeeLimit_Fixed); ok {
+			feeLimit = limit.Fixed
+		}
+	}
+
+	result, err := n.pay(in.PaymentRequest, feeLimit)
+	if err != nil {
+		return nil, err
+	}
+	if result.held != nil {
+		<-result.held
+	}
+
+	if result.failure != "" {
+		return &lnrpc.SendResponse{
+			PaymentError: result.failure,
+			PaymentHash:  result.inv.hash,
+		}, nil
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	return &lnrpc.SendResponse{
+		PaymentPreimage: result.inv.preimage,
+		PaymentHash:     result.inv.hash,
+		PaymentRoute:    result.route(),
+	}, nil
+}
+
+// payResult is the outcome of a payment made by a node
+type payResult struct {
+	inv *invoice
+	fee int64
+
+	// why the payment failed, empty if it succeeded
+	failure      string
+	failureState routerrpc.PaymentState
+
+	// returned after the payment succeeded, see LoseNextPaymentResponse
+	err error
+
+	// closed when a held payment completes, see HoldNextPayment
+	held chan struct{}
+}
+
+func (r *payResult) route() *lnrpc.Route {
+	return &lnrpc.Route{
+		TotalFees:     r.fee,
+		TotalFeesMsat: r.fee * 1000,
+		TotalAmt:      r.inv.valueSat + r.fee,
+		TotalAmtMsat:  (r.inv.valueSat + r.fee) * 1000,
+	}
+}
+
+// pay pays an invoice of another node in the network, with a fee of at most
+// feeLimit, or any fee if feeLimit is negative
+func (n *Node) pay(paymentRequest string, feeLimit int64) (*payResult, error) {
+	n.network.mu.Lock()
+	defer n.network.mu.Unlock()
+
+	inv, ok := n.network.invoices[paymentRequest]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"invalid payment request %q", paymentRequest)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	payment := &lnrpc.Payment{
+		PaymentHash:    hex.EncodeToString(inv.hash),
+		Value:          inv.valueSat,
+		ValueSat:       inv.valueSat,
+		ValueMsat:      inv.valueSat * 1000,
+		CreationDate:   time.Now().Unix(),
+		PaymentRequest: inv.paymentRequest,
+	}
+	result := &payResult{inv: inv}
+
+	// fail does not record the attempt if lnd would refuse the payment
+	// before trying it
+	fail := func(reason string, state routerrpc.PaymentState,
+		record bool) (*payResult, error) {
+
+		if record {
+			payment.Status = lnrpc.Payment_FAILED
+			n.payments = append(n.payments, payment)
+		}
+		result.failure = reason
+		result.failureState = state
+		return result, nil
+	}
+
+	// lnd never pays the same invoice twice
+	for _, p := range n.payments {
+		if p.PaymentHash != payment.PaymentHash {
+			continue
+		}
+		switch p.Status {
+		case lnrpc.Payment_SUCCEEDED:
+			return nil, status.Error(codes.Unknown, "invoice is already paid")
+		case lnrpc.Payment_IN_FLIGHT:
+			return nil, status.Error(codes.Unknown, "payment is in transition")
+		}
+	}
+
+	var failure paymentFailure
+	if len(n.paymentFailures) > 0 {
+		failure = n.paymentFailures[0]
+		n.paymentFailures = n.paymentFailures[1:]
+	}
+
+	switch {
+	case failure.reason != "":
+		return fail(failure.reason, routerrpc.PaymentState_FAILED_NO_ROUTE, true)
+	case inv.dest == n:
+		return fail("can not pay own invoice",
+			routerrpc.PaymentState_FAILED_ERROR, false)
+	case inv.state != lnrpc.Invoice_OPEN || inv.expired():
+		return fail("invoice expired or canceled",
+			routerrpc.PaymentState_FAILED_INCORRECT_PAYMENT_DETAILS, true)
+	case n.balance < inv.valueSat+n.feeSat:
+		return fail("insufficient local balance",
+			routerrpc.PaymentState_FAILED_NO_ROUTE, true)
+	case feeLimit >= 0 && n.feeSat > feeLimit:
+		return fail("no route within fee limit",
+			routerrpc.PaymentState_FAILED_NO_ROUTE, true)
+	}
+
+	result.fee = n.feeSat
+	result.err = failure.err
+	n.payments = append(n.payments, payment)
+
+	if !failure.hold {
+		n.complete(payment, result)
+		return result, nil
+	}
+
+	// the payment stays in flight until the test releases it
+	payment.Status = lnrpc.Payment_IN_FLIGHT
+	result.held = make(chan struct{})
+	n.held = append(n.held, func() {
+		n.network.mu.Lock()
+		n.mu.Lock()
+		n.complete(payment, result)
+		n.mu.Unlock()
+		n.network.mu.Unlock()
+
+		close(result.held)
+	})
+
+	return result, nil
+}
+
+// complete settles a payment. The network lock and the lock of the node
+// must be held.
+func (n *Node) complete(payment *lnrpc.Payment, result *payResult) {
+	n.balance -= result.inv.valueSat + result.fee
+	result.inv.dest.settle(result.inv)
+
+	payment.Status = lnrpc.Payment_SUCCEEDED
+	payment.PaymentPreimage = hex.EncodeToString(result.inv.preimage)
+	payment.Fee = result.fee
+	payment.FeeSat = result.fee
+	payment.FeeMsat = result.fee * 1000
+}
+
+// settle marks an invoice of the node as paid, and notifies subscribers.
+// The network lock must be held.
+func (n *Node) settle(inv *invoice) {
+	if n != inv.dest {
+		panic("settling invoice of another node")
+	}
+
+	// the paying node holds its own lock, the receiving node is never the
+	// same node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.nextIndex++
+	inv.state = lnrpc.Invoice_SETTLED
+	inv.settledAt = time.Now().Unix()
+	inv.settleIndex = n.nextIndex
+	n.balance += inv.valueSat
+
+	for _, sub := range n.subscribers {
+		select {
+		case sub <- inv.toRPC():
+		default:
+		}
+	}
+}
+
+func (n *Node) LookupInvoice(ctx context.Context, in *lnrpc.PaymentHash,
+	opts ...grpc.CallOption) (*lnrpc.Invoice, error) {
+
+	hash := in.RHashStr
+	if hash == "" {
+		hash = hex.EncodeToString(in.RHash)
+	}
+
+	n.network.mu.Lock()
+	defer n.network.mu.Unlock()
+
+	for _, inv := range n.network.invoices {
+		if inv.dest == n && hex.EncodeToString(inv.hash) == hash {
+			return inv.toRPC(), nil
+		}
+	}
+
+	return nil, status.Error(codes.NotFound, "unable to locate invoice")
+}
+
+func (n *Node) SubscribeInvoices(ctx context.Context, in *lnrpc.InvoiceSubscription,
+	opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeInvoicesClient, error) {
+
+	updates := make(chan *lnrpc.Invoice, 100)
+
+	n.mu.Lock()
+	n.subscribers = append(n.subscribers, updates)
+	n.mu.Unlock()
+
+	return &invoiceStream{ctx: ctx, updates: updates}, nil
+}
+
+// invoiceStream is the client side of an invoice subscription
+type invoiceStream struct {
+	grpc.ClientStream
+
+	ctx     context.Context
+	updates chan *lnrpc.Invoice
+}
+
+func (s *invoiceStream) Recv() (*lnrpc.Invoice, error) {
+	select {
+	case invoice := <-s.updates:
+		return invoice, nil
+	case <-s.ctx.Done():
+		return nil, status.FromContextError(s.ctx.Err()).Err()
+	}
+}
+
+func (s *invoiceStream) Context() context.Context {
+	return s.ctx
+}
+
+func (n *Node) ListPayments(ctx context.Context, in *lnrpc.ListPaymentsRequest,
+	opts ...grpc.CallOption) (*lnrpc.ListPaymentsResponse, error) {
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	res := &lnrpc.ListPaymentsResponse{}
+	for _, payment := range n.payments {
+		if payment.Status == lnrpc.Payment_SUCCEEDED || in.IncludeIncomplete {
+			res.Payments = append(res.Payments, payment)
+		}
+	}
+
+	return res, nil
+}
+
+func (n *Node) ChannelBalance(ctx context.Context, in *lnrpc.ChannelBalanceRequest,
+	opts ...grpc.CallOption) (*lnrpc.ChannelBalanceResponse, error) {
+
+	return &lnrpc.ChannelBalanceResponse{Balance: n.Balance()}, nil
+}
+
+func (n *Node) WalletBalance(ctx context.Context, in *lnrpc.WalletBalanceRequest,
+	opts ...grpc.CallOption) (*lnrpc.WalletBalanceResponse, error) {
+
+	return &lnrpc.WalletBalanceResponse{}, nil
+}
+
+// ListChannels returns an active channel to every other node in the
+// network, holding the balance of the node
+func (n *Node) ListChannels(ctx context.Context, in *lnrpc.ListChannelsRequest,
+	opts ...grpc.CallOption) (*lnrpc.ListChannelsResponse, error) {
+
+	n.network.mu.Lock()
+	defer n.network.mu.Unlock()
+
+	res := &lnrpc.ListChannelsResponse{}
+	for i, peer := range n.network.nodes {
+		if peer == n {
+			continue
+		}
+
+		res.Channels = append(res.Channels, &lnrpc.Channel{
+			Active:       true,
+			RemotePubkey: peer.Pubkey,
+			ChanId:       uint64(i + 1),
+			LocalBalance: n.Balance(),
+		})
+	}
+
+	return res, nil
+}
+
+// SignMessage signs msg with a fake signature, that VerifyMessage of any
+// node in the network accepts
+func (n *Node) SignMessage(ctx context.Context, in *lnrpc.SignMessageRequest,
+	opts ...grpc.CallOption) (*lnrpc.SignMessageResponse, error) {
+
+	return &lnrpc.SignMessageResponse{
+		Signature: n.Pubkey + ":" + signature(n.Pubkey, in.Msg),
+	}, nil
+}
+
+func (n *Node) VerifyMessage(ctx context.Context, in *lnrpc.VerifyMessageRequest,
+	opts ...grpc.CallOption) (*lnrpc.VerifyMessageResponse, error) {
+
+	parts := strings.SplitN(in.Signature, ":", 2)
+	if len(parts) != 2 {
+		return &lnrpc.VerifyMessageResponse{}, nil
+	}
+
+	return &lnrpc.VerifyMessageResponse{
+		Valid:  parts[1] == signature(parts[0], in.Msg),
+		Pubkey: parts[0],
+	}, nil
+}
+
+func signature(pubkey string, msg []byte) string {
+	sig := sha256.Sum256(append([]byte(pubkey), msg...))
+	return hex.EncodeToString(sig[:])
+}
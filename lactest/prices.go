@@ -0,0 +1,109 @@
+package lactest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+	"github.com/ArcaneCryptoAS/lassets-client/oracle"
+)
+
+// PriceSource is a price source whose prices are set by hand
+type PriceSource struct {
+	mu          sync.Mutex
+	prices      map[string]oracle.Price
+	subscribers map[chan oracle.Price]struct{}
+	failure     error
+}
+
+var _ oracle.PriceSource = (*PriceSource)(nil)
+
+// NewPriceSource creates a price source with no prices
+func NewPriceSource() *PriceSource {
+	return &PriceSource{
+		prices:      make(map[string]oracle.Price),
+		subscribers: make(map[chan oracle.Price]struct{}),
+	}
+}
+
+func (p *PriceSource) Name() string {
+	return "lactest"
+}
+
+// SetPrice sets the price of one bitcoin in asset, and sends it to everyone
+// running the source
+func (p *PriceSource) SetPrice(asset string, value money.Decimal) {
+	price := oracle.Price{
+		Asset:     asset,
+		Value:     value,
+		Timestamp: time.Now(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prices[asset] = price
+	for subscriber := range p.subscribers {
+		subscriber <- price
+	}
+}
+
+// Disconnect makes every running source return err, as if the connection
+// to the source was lost
+func (p *PriceSource) Disconnect(err error) {
+	if err == nil {
+		err = errors.New("disconnected")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failure = err
+	for subscriber := range p.subscribers {
+		close(subscriber)
+		delete(p.subscribers, subscriber)
+	}
+}
+
+// Run sends the current prices, and then every price set until ctx is
+// canceled or the source is disconnected
+func (p *PriceSource) Run(ctx context.Context, updates chan<- oracle.Price) error {
+	// large enough that SetPrice never blocks on a slow reader
+	subscriber := make(chan oracle.Price, 100)
+
+	p.mu.Lock()
+	p.failure = nil
+	for _, price := range p.prices {
+		subscriber <- price
+	}
+	p.subscribers[subscriber] = struct{}{}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.subscribers, subscriber)
+		p.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case price, ok := <-subscriber:
+			if !ok {
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				return p.failure
+			}
+
+			select {
+			case updates <- price:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
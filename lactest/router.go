@@ -0,0 +1,155 @@
+package lactest
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// how often a tracked payment is checked for completion
+const trackInterval = 10 * time.Millisecond
+
+// Router is the router sub-server of a fake lnd node. It implements the
+// parts of routerrpc.RouterClient lacd uses, calling any other method
+// panics.
+type Router struct {
+	routerrpc.RouterClient
+
+	node *Node
+}
+
+var _ routerrpc.RouterClient = (*Router)(nil)
+
+// Router returns the router of the node
+func (n *Node) Router() *Router {
+	return &Router{node: n}
+}
+
+func (r *Router) SendPayment(ctx context.Context, in *routerrpc.SendPaymentRequest,
+	opts ...grpc.CallOption) (routerrpc.Router_SendPaymentClient, error) {
+
+	if in.TimeoutSeconds <= 0 {
+		return nil, status.Error(codes.InvalidArgument,
+			"timeout_seconds must be specified")
+	}
+
+	result, err := r.node.pay(in.PaymentRequest, in.FeeLimitSat)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan *routerrpc.PaymentStatus, 2)
+	errs := make(chan error, 1)
+
+	if result.failure != "" {
+		updates <- &routerrpc.PaymentStatus{State: result.failureState}
+		return &paymentStream{ctx: ctx, updates: updates, errs: errs}, nil
+	}
+
+	updates <- &routerrpc.PaymentStatus{State: routerrpc.PaymentState_IN_FLIGHT}
+	go func() {
+		if result.held != nil {
+			<-result.held
+		}
+
+		if result.err != nil {
+			errs <- result.err
+			return
+		}
+		updates <- &routerrpc.PaymentStatus{
+			State:    routerrpc.PaymentState_SUCCEEDED,
+			Preimage: result.inv.preimage,
+			Route:    result.route(),
+		}
+	}()
+
+	return &paymentStream{ctx: ctx, updates: updates, errs: errs}, nil
+}
+
+func (r *Router) TrackPayment(ctx context.Context, in *routerrpc.TrackPaymentRequest,
+	opts ...grpc.CallOption) (routerrpc.Router_TrackPaymentClient, error) {
+
+	hash := hex.EncodeToString(in.PaymentHash)
+
+	// the latest attempt is the one lnd tracks
+	var payment *lnrpc.Payment
+	for _, p := range r.node.Payments() {
+		if p.PaymentHash == hash {
+			payment = p
+		}
+	}
+	if payment == nil {
+		return nil, status.Error(codes.Unknown, "payment isn't initiated")
+	}
+
+	updates := make(chan *routerrpc.PaymentStatus, 2)
+	go func() {
+		inFlight := false
+
+		for {
+			r.node.mu.Lock()
+			state, preimage, fee := payment.Status, payment.PaymentPreimage,
+				payment.FeeSat
+			r.node.mu.Unlock()
+
+			switch state {
+			case lnrpc.Payment_SUCCEEDED:
+				decoded, _ := hex.DecodeString(preimage)
+				updates <- &routerrpc.PaymentStatus{
+					State:    routerrpc.PaymentState_SUCCEEDED,
+					Preimage: decoded,
+					Route:    &lnrpc.Route{TotalFees: fee},
+				}
+				return
+
+			case lnrpc.Payment_FAILED:
+				updates <- &routerrpc.PaymentStatus{
+					State: routerrpc.PaymentState_FAILED_NO_ROUTE,
+				}
+				return
+
+			case lnrpc.Payment_IN_FLIGHT:
+				if !inFlight {
+					inFlight = true
+					updates <- &routerrpc.PaymentStatus{
+						State: routerrpc.PaymentState_IN_FLIGHT,
+					}
+				}
+			}
+
+			select {
+			case <-time.After(trackInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &paymentStream{ctx: ctx, updates: updates}, nil
+}
+
+// paymentStream is the client side of a payment being sent or tracked
+type paymentStream struct {
+	grpc.ClientStream
+
+	ctx     context.Context
+	updates chan *routerrpc.PaymentStatus
+	errs    chan error
+}
+
+func (s *paymentStream) Recv() (*routerrpc.PaymentStatus, error) {
+	select {
+	case update := <-s.updates:
+		return update, nil
+	case err := <-s.errs:
+		return nil, err
+	case <-s.ctx.Done():
+		return nil, status.FromContextError(s.ctx.Err()).Err()
+	}
+}
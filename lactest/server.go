@@ -0,0 +1,628 @@
+package lactest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+// Quote is what the fake asset server offers for new contracts
+type Quote struct {
+	AssetPrice    money.Decimal
+	PercentMargin money.Decimal
+}
+
+// AssetServer is a fake asset server, creating and paying invoices with its
+// own fake lnd node. Its quotes and failures can be scripted.
+type AssetServer struct {
+	lnd *Node
+
+	mu        sync.Mutex
+	quote     Quote
+	assets    []string
+	contracts map[string]*larpc.ServerContract
+	closed    map[string]bool
+	failures  map[string][]func() error
+
+	// the node of the client of every contract, and the push channel of
+	// every connected client node
+	contractNodes map[string]string
+	pushChannels  map[string]*pushChannel
+	pushChanged   chan struct{}
+
+	// the nonces of all signed requests, which may not be reused
+	nonces map[string]bool
+}
+
+var _ larpc.AssetServerServer = (*AssetServer)(nil)
+
+// NewAssetServer creates a fake asset server that supports USD at the
+// given quote
+func NewAssetServer(lnd *Node, quote Quote) *AssetServer {
+	return &AssetServer{
+		lnd:       lnd,
+		quote:     quote,
+		assets:    []string{"USD"},
+		contracts: make(map[string]*larpc.ServerContract),
+		closed:    make(map[string]bool),
+		failures:  make(map[string][]func() error),
+
+		contractNodes: make(map[string]string),
+		pushChannels:  make(map[string]*pushChannel),
+		pushChanged:   make(chan struct{}),
+
+		nonces: make(map[string]bool),
+	}
+}
+
+// SetQuote changes the quote of new contracts
+func (s *AssetServer) SetQuote(quote Quote) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quote = quote
+}
+
+// SetAssets changes the supported assets
+func (s *AssetServer) SetAssets(assets ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.assets = assets
+}
+
+// FailNext makes the next call to method, ie "NewContract", return err
+func (s *AssetServer) FailNext(method string, err error) {
+	s.OnNext(method, func() error {
+		return err
+	})
+}
+
+// OnNext runs fn when method is called next, before the call does anything
+// else. The call fails if fn returns an error.
+func (s *AssetServer) OnNext(method string, fn func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[method] = append(s.failures[method], fn)
+}
+
+// Contract returns the contract with the given uuid, and whether it is closed
+func (s *AssetServer) Contract(uuid string) (*larpc.ServerContract, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.contracts[uuid], s.closed[uuid]
+}
+
+// nextFailure returns the scripted failure of the next call to method, if
+// any. The lock must be held.
+func (s *AssetServer) nextFailure(method string) error {
+	failures := s.failures[method]
+	if len(failures) == 0 {
+		return nil
+	}
+
+	s.failures[method] = failures[1:]
+	return failures[0]()
+}
+
+func (s *AssetServer) supports(asset string) bool {
+	for _, supported := range s.assets {
+		if supported == asset {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *AssetServer) NewContract(ctx context.Context,
+	req *larpc.ServerNewContractRequest) (*larpc.ServerNewContractResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.nextFailure("NewContract"); err != nil {
+		return nil, err
+	}
+
+	if !s.supports(req.Asset) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"asset %s is not supported", req.Asset)
+	}
+	amount, err := money.ParseDecimalOr(req.AmountDecimal, req.Amount)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if amount.Sign() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be positive")
+	}
+
+	if req.Signature.GetNodePubkey() != req.NodePubkey {
+		return nil, status.Error(codes.Unauthenticated,
+			"request is not signed by the node of the client")
+	}
+	err = s.verifyRequest(ctx, req.Signature, req.SigningPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &larpc.ServerNewContractResponse{
+		Uuid:                 uuid.New().String(),
+		AssetPrice:           s.quote.AssetPrice.Float64(),
+		AssetPriceDecimal:    s.quote.AssetPrice.String(),
+		PercentMargin:        s.quote.PercentMargin.Float64(),
+		PercentMarginDecimal: s.quote.PercentMargin.String(),
+	}
+
+	marginSat, err := satsForAsset(amount, s.quote.AssetPrice, s.quote.PercentMargin)
+	if err != nil {
+		return nil, err
+	}
+	amountSat, err := satsForAsset(amount, s.quote.AssetPrice, money.OneHundredPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	margin, err := s.lnd.AddInvoice(ctx, &lnrpc.Invoice{
+		Value: marginSat,
+		Memo:  "margin of contract " + res.Uuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.MarginPayReq = margin.PaymentRequest
+
+	if req.ContractType == larpc.ContractType_FUNDED {
+		init, err := s.lnd.AddInvoice(ctx, &lnrpc.Invoice{
+			Value: amountSat,
+			Memo:  "init of contract " + res.Uuid,
+		})
+		if err != nil {
+			return nil, err
+		}
+		res.InitiatingPayReq = init.PaymentRequest
+	}
+
+	s.contracts[res.Uuid] = &larpc.ServerContract{
+		Uuid:             res.Uuid,
+		Asset:            req.Asset,
+		Amount:           amount.Float64(),
+		AmountDecimal:    amount.String(),
+		AmountSats:       amountSat,
+		ClientHost:       req.Host,
+		MarginPayReq:     res.MarginPayReq,
+		InitiatingPayReq: res.InitiatingPayReq,
+		ContractType:     req.ContractType,
+	}
+	s.contractNodes[res.Uuid] = req.NodePubkey
+
+	res.Signature = responseSignature(s.lnd, req.Signature)
+	if err := s.sign(ctx, res.Signature, res.SigningPayload()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *AssetServer) CloseContract(ctx context.Context,
+	req *larpc.ServerCloseContractRequest) (*larpc.ServerCloseContractResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.nextFailure("CloseContract"); err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.contracts[req.Uuid]; !ok || s.closed[req.Uuid] {
+		return nil, status.Errorf(codes.NotFound, "contract %s not found", req.Uuid)
+	}
+
+	// only the node the contract is bound to may close it
+	if req.Signature.GetNodePubkey() != s.contractNodes[req.Uuid] {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"contract %s is not bound to node %s", req.Uuid,
+			req.Signature.GetNodePubkey())
+	}
+	err := s.verifyRequest(ctx, req.Signature, req.SigningPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	s.closed[req.Uuid] = true
+
+	res := &larpc.ServerCloseContractResponse{
+		Signature: responseSignature(s.lnd, req.Signature),
+	}
+	err = s.sign(ctx, res.Signature, res.SigningPayload(req.Uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// verifyRequest checks that sig is a fresh, valid signature of payload. The
+// lock must be held.
+func (s *AssetServer) verifyRequest(ctx context.Context,
+	sig *larpc.MessageSignature, payload []byte) error {
+
+	if sig.GetSignature() == "" {
+		return status.Error(codes.Unauthenticated, "request is not signed")
+	}
+
+	age := time.Since(time.Unix(sig.Timestamp, 0))
+	if age > larpc.MaxSignatureAge || age < -larpc.MaxSignatureAge {
+		return status.Error(codes.Unauthenticated, "request signature is expired")
+	}
+	if s.nonces[sig.Nonce] {
+		return status.Errorf(codes.Unauthenticated,
+			"nonce %s is already used", sig.Nonce)
+	}
+
+	res, err := s.lnd.VerifyMessage(ctx, &lnrpc.VerifyMessageRequest{
+		Msg:       payload,
+		Signature: sig.Signature,
+	})
+	if err != nil {
+		return err
+	}
+	if !res.Valid || res.Pubkey != sig.NodePubkey {
+		return status.Error(codes.Unauthenticated, "request signature is invalid")
+	}
+
+	s.nonces[sig.Nonce] = true
+	return nil
+}
+
+// responseSignature creates the signature of node for the response to the
+// request signed with reqSig, without the signature itself
+func responseSignature(node *Node, reqSig *larpc.MessageSignature) *larpc.MessageSignature {
+	return &larpc.MessageSignature{
+		NodePubkey: node.Pubkey,
+		Timestamp:  time.Now().Unix(),
+		Nonce:      reqSig.GetNonce(),
+	}
+}
+
+// sign signs payload with the node of the server, and stores the signature
+// in sig
+func (s *AssetServer) sign(ctx context.Context, sig *larpc.MessageSignature,
+	payload []byte) error {
+
+	res, err := s.lnd.SignMessage(ctx, &lnrpc.SignMessageRequest{
+		Msg: payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	sig.Signature = res.Signature
+	return nil
+}
+
+func (s *AssetServer) RebalanceContract(ctx context.Context,
+	req *larpc.ServerRebalanceContractRequest) (*larpc.ServerRebalanceContractResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.nextFailure("RebalanceContract"); err != nil {
+		return nil, err
+	}
+
+	contract, ok := s.contracts[req.Uuid]
+	if !ok || s.closed[req.Uuid] {
+		return nil, status.Errorf(codes.NotFound, "contract %s not found", req.Uuid)
+	}
+
+	// only the node the contract is bound to may rebalance it
+	if req.Signature.GetNodePubkey() != s.contractNodes[req.Uuid] {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"contract %s is not bound to node %s", req.Uuid,
+			req.Signature.GetNodePubkey())
+	}
+	err := s.verifyRequest(ctx, req.Signature, req.SigningPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &larpc.ServerRebalanceContractResponse{
+		Signature: responseSignature(s.lnd, req.Signature),
+	}
+
+	// the client owes us, and needs an invoice to pay
+	if req.AmountSat < 0 {
+		invoice, err := s.lnd.AddInvoice(ctx, &lnrpc.Invoice{
+			Value: -req.AmountSat,
+			Memo:  "rebalance of contract " + req.Uuid,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		contract.AmountSats += req.AmountSat
+
+		res.PayReq = invoice.PaymentRequest
+		err = s.sign(ctx, res.Signature, res.SigningPayload(req.Uuid))
+		if err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}
+
+	payReq, err := s.lnd.DecodePayReq(ctx, &lnrpc.PayReqString{PayReq: req.PayReq})
+	if err != nil {
+		return nil, err
+	}
+	if payReq.NumSatoshis != req.AmountSat {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"invoice is for %d sats, expected %d", payReq.NumSatoshis, req.AmountSat)
+	}
+
+	payment, err := s.lnd.SendPaymentSync(ctx, &lnrpc.SendRequest{PaymentRequest: req.PayReq})
+	if err != nil {
+		return nil, err
+	}
+	if payment.PaymentError != "" {
+		return nil, status.Errorf(codes.Internal, "could not pay: %s",
+			payment.PaymentError)
+	}
+
+	contract.AmountSats += req.AmountSat
+
+	err = s.sign(ctx, res.Signature, res.SigningPayload(req.Uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *AssetServer) ListAssets(ctx context.Context,
+	req *larpc.ServerListAssetsRequest) (*larpc.ServerListAssetsResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.nextFailure("ListAssets"); err != nil {
+		return nil, err
+	}
+
+	return &larpc.ServerListAssetsResponse{
+		SupportedAssets: append([]string(nil), s.assets...),
+	}, nil
+}
+
+// pushChannel is the push channel of a single client
+type pushChannel struct {
+	stream larpc.AssetServer_PushChannelServer
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *larpc.PushClientMessage
+}
+
+// PushChannel registers the push channel of a client, and routes the
+// responses of the client to the requests they answer
+func (s *AssetServer) PushChannel(stream larpc.AssetServer_PushChannelServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	registration := msg.GetRegistration()
+	if registration == nil || registration.NodePubkey == "" {
+		return status.Error(codes.InvalidArgument,
+			"the first message must be a registration")
+	}
+
+	// only the node itself may register its push channel
+	if registration.Signature.GetNodePubkey() != registration.NodePubkey {
+		return status.Error(codes.Unauthenticated,
+			"registration is not signed by the node of the client")
+	}
+
+	channel := &pushChannel{
+		stream:  stream,
+		pending: make(map[uint64]chan *larpc.PushClientMessage),
+	}
+
+	s.mu.Lock()
+	err = s.verifyRequest(stream.Context(), registration.Signature,
+		registration.SigningPayload())
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.pushChannels[registration.NodePubkey] = channel
+	s.notifyPushChanged()
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		if s.pushChannels[registration.NodePubkey] == channel {
+			delete(s.pushChannels, registration.NodePubkey)
+			s.notifyPushChanged()
+		}
+		s.mu.Unlock()
+
+		// fail every request still waiting for a response
+		channel.mu.Lock()
+		for id, pending := range channel.pending {
+			close(pending)
+			delete(channel.pending, id)
+		}
+		channel.mu.Unlock()
+	}()
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		channel.mu.Lock()
+		pending, ok := channel.pending[msg.RequestId]
+		delete(channel.pending, msg.RequestId)
+		channel.mu.Unlock()
+
+		if !ok {
+			log.WithField("request", msg.RequestId).
+				Warn("response to unknown push request")
+			continue
+		}
+		pending <- msg
+	}
+}
+
+// notifyPushChanged wakes up everyone waiting for a push channel. The lock
+// must be held.
+func (s *AssetServer) notifyPushChanged() {
+	close(s.pushChanged)
+	s.pushChanged = make(chan struct{})
+}
+
+// WaitForPushChannel blocks until the client node with the given pubkey
+// has registered a push channel
+func (s *AssetServer) WaitForPushChannel(ctx context.Context, nodePubkey string) error {
+	for {
+		s.mu.Lock()
+		_, ok := s.pushChannels[nodePubkey]
+		changed := s.pushChanged
+		s.mu.Unlock()
+
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RequestInvoice asks the client of a contract for an invoice over its push
+// channel
+func (s *AssetServer) RequestInvoice(ctx context.Context, uuid string,
+	amountSat int64) (string, error) {
+
+	res, err := s.push(ctx, uuid, &larpc.PushServerMessage{
+		Request: &larpc.PushServerMessage_InvoiceRequest{
+			InvoiceRequest: &larpc.PushInvoiceRequest{
+				Uuid:      uuid,
+				AmountSat: amountSat,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	invoice := res.GetInvoiceResponse()
+	if invoice == nil {
+		return "", fmt.Errorf("unexpected response %T", res.Message)
+	}
+
+	return invoice.PayReq, nil
+}
+
+// RequestPayment asks the client of a contract to pay an invoice over its
+// push channel
+func (s *AssetServer) RequestPayment(ctx context.Context, uuid, payReq string) error {
+	res, err := s.push(ctx, uuid, &larpc.PushServerMessage{
+		Request: &larpc.PushServerMessage_PaymentRequest{
+			PaymentRequest: &larpc.PushPaymentRequest{
+				Uuid:   uuid,
+				PayReq: payReq,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.GetPaymentResponse() == nil {
+		return fmt.Errorf("unexpected response %T", res.Message)
+	}
+
+	return nil
+}
+
+// push sends a request to the client of a contract, and waits for its
+// response. An error response from the client is returned as an error.
+func (s *AssetServer) push(ctx context.Context, uuid string,
+	req *larpc.PushServerMessage) (*larpc.PushClientMessage, error) {
+
+	s.mu.Lock()
+	nodePubkey, ok := s.contractNodes[uuid]
+	channel := s.pushChannels[nodePubkey]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "contract %s not found", uuid)
+	}
+	if channel == nil {
+		return nil, status.Errorf(codes.Unavailable,
+			"client of contract %s has no push channel", uuid)
+	}
+
+	pending := make(chan *larpc.PushClientMessage, 1)
+
+	channel.mu.Lock()
+	channel.nextID++
+	req.RequestId = channel.nextID
+	channel.pending[req.RequestId] = pending
+	err := channel.stream.Send(req)
+	channel.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case res, ok := <-pending:
+		if !ok {
+			return nil, status.Error(codes.Unavailable, "push channel closed")
+		}
+		if pushErr := res.GetError(); pushErr != nil {
+			return nil, status.Error(codes.Code(pushErr.Code), pushErr.Message)
+		}
+		return res, nil
+
+	case <-ctx.Done():
+		channel.mu.Lock()
+		delete(channel.pending, req.RequestId)
+		channel.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// satsForAsset converts percent of an amount of asset to sats, rounded half
+// up like the client does
+func satsForAsset(amount, price, percent money.Decimal) (int64, error) {
+	if price.Sign() <= 0 {
+		panic(fmt.Sprintf("invalid price %s", price))
+	}
+
+	value, err := money.AssetValue(amount, price, percent, money.RoundHalfUp)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return value.ToSatoshis(money.RoundHalfUp), nil
+}
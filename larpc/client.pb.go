@@ -25,19 +25,229 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type ContractStatus int32
+
+const (
+	// the contract is created with the server, but the invoices are not paid
+	ContractStatus_CREATED ContractStatus = 0
+	// we are paying the invoices of the contract
+	ContractStatus_OPENING ContractStatus = 1
+	// the invoices are paid, and the contract is rebalanced
+	ContractStatus_OPEN ContractStatus = 2
+	// we are closing the contract with the server
+	ContractStatus_CLOSING ContractStatus = 3
+	ContractStatus_CLOSED  ContractStatus = 4
+	// the contract could not be opened. It can still be closed if the
+	// margin was paid, to get the margin back from the server.
+	ContractStatus_FAILED ContractStatus = 5
+	// the invoices of the contract expired before they were paid
+	ContractStatus_EXPIRED ContractStatus = 6
+)
+
+var ContractStatus_name = map[int32]string{
+	0: "CREATED",
+	1: "OPENING",
+	2: "OPEN",
+	3: "CLOSING",
+	4: "CLOSED",
+	5: "FAILED",
+	6: "EXPIRED",
+}
+
+var ContractStatus_value = map[string]int32{
+	"CREATED": 0,
+	"OPENING": 1,
+	"OPEN":    2,
+	"CLOSING": 3,
+	"CLOSED":  4,
+	"FAILED":  5,
+	"EXPIRED": 6,
+}
+
+func (x ContractStatus) String() string {
+	return proto.EnumName(ContractStatus_name, int32(x))
+}
+
+func (ContractStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{0}
+}
+
+type RebalanceState int32
+
+const (
+	RebalanceState_REBALANCE_PENDING   RebalanceState = 0
+	RebalanceState_REBALANCE_COMPLETED RebalanceState = 1
+	RebalanceState_REBALANCE_FAILED    RebalanceState = 2
+)
+
+var RebalanceState_name = map[int32]string{
+	0: "REBALANCE_PENDING",
+	1: "REBALANCE_COMPLETED",
+	2: "REBALANCE_FAILED",
+}
+
+var RebalanceState_value = map[string]int32{
+	"REBALANCE_PENDING":   0,
+	"REBALANCE_COMPLETED": 1,
+	"REBALANCE_FAILED":    2,
+}
+
+func (x RebalanceState) String() string {
+	return proto.EnumName(RebalanceState_name, int32(x))
+}
+
+func (RebalanceState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{1}
+}
+
+type ConnectionState int32
+
+const (
+	// we are trying to connect, and have not succeeded yet
+	ConnectionState_CONNECTING ConnectionState = 0
+	ConnectionState_CONNECTED  ConnectionState = 1
+	// the connection was lost or could not be made, we keep retrying
+	ConnectionState_DISCONNECTED ConnectionState = 2
+	// we are connected, but the other side can not be used yet, ie lnd is
+	// locked or not synced to chain. last_error says why.
+	ConnectionState_NOT_READY ConnectionState = 3
+)
+
+var ConnectionState_name = map[int32]string{
+	0: "CONNECTING",
+	1: "CONNECTED",
+	2: "DISCONNECTED",
+	3: "NOT_READY",
+}
+
+var ConnectionState_value = map[string]int32{
+	"CONNECTING":   0,
+	"CONNECTED":    1,
+	"DISCONNECTED": 2,
+	"NOT_READY":    3,
+}
+
+func (x ConnectionState) String() string {
+	return proto.EnumName(ConnectionState_name, int32(x))
+}
+
+func (ConnectionState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{2}
+}
+
+type IdentitySource int32
+
+const (
+	// pinned from the first invoice the server sent us
+	IdentitySource_TRUST_ON_FIRST_USE IdentitySource = 0
+	// given in the configuration of the daemon
+	IdentitySource_CONFIGURED IdentitySource = 1
+)
+
+var IdentitySource_name = map[int32]string{
+	0: "TRUST_ON_FIRST_USE",
+	1: "CONFIGURED",
+}
+
+var IdentitySource_value = map[string]int32{
+	"TRUST_ON_FIRST_USE": 0,
+	"CONFIGURED":         1,
+}
+
+func (x IdentitySource) String() string {
+	return proto.EnumName(IdentitySource_name, int32(x))
+}
+
+func (IdentitySource) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{3}
+}
+
+type ClientContractUpdate_UpdateType int32
+
+const (
+	ClientContractUpdate_SNAPSHOT   ClientContractUpdate_UpdateType = 0
+	ClientContractUpdate_CREATED    ClientContractUpdate_UpdateType = 1
+	ClientContractUpdate_OPENED     ClientContractUpdate_UpdateType = 2
+	ClientContractUpdate_REBALANCED ClientContractUpdate_UpdateType = 3
+	ClientContractUpdate_CLOSED     ClientContractUpdate_UpdateType = 4
+	ClientContractUpdate_OPENING    ClientContractUpdate_UpdateType = 5
+	ClientContractUpdate_CLOSING    ClientContractUpdate_UpdateType = 6
+	ClientContractUpdate_FAILED     ClientContractUpdate_UpdateType = 7
+	ClientContractUpdate_EXPIRED    ClientContractUpdate_UpdateType = 8
+	// the contract expired long ago, and was removed from the database
+	ClientContractUpdate_REMOVED ClientContractUpdate_UpdateType = 9
+)
+
+var ClientContractUpdate_UpdateType_name = map[int32]string{
+	0: "SNAPSHOT",
+	1: "CREATED",
+	2: "OPENED",
+	3: "REBALANCED",
+	4: "CLOSED",
+	5: "OPENING",
+	6: "CLOSING",
+	7: "FAILED",
+	8: "EXPIRED",
+	9: "REMOVED",
+}
+
+var ClientContractUpdate_UpdateType_value = map[string]int32{
+	"SNAPSHOT":   0,
+	"CREATED":    1,
+	"OPENED":     2,
+	"REBALANCED": 3,
+	"CLOSED":     4,
+	"OPENING":    5,
+	"CLOSING":    6,
+	"FAILED":     7,
+	"EXPIRED":    8,
+	"REMOVED":    9,
+}
+
+func (x ClientContractUpdate_UpdateType) String() string {
+	return proto.EnumName(ClientContractUpdate_UpdateType_name, int32(x))
+}
+
+func (ClientContractUpdate_UpdateType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{18, 0}
+}
+
 type ClientContract struct {
-	Uuid                 string       `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Asset                string       `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
-	Amount               float64      `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
-	AmountSatMargin      int64        `protobuf:"varint,5,opt,name=amount_sat_margin,json=amountSatMargin,proto3" json:"amount_sat_margin,omitempty"`
-	AmountSatInit        int64        `protobuf:"varint,6,opt,name=amount_sat_init,json=amountSatInit,proto3" json:"amount_sat_init,omitempty"`
-	MarginInvoice        string       `protobuf:"bytes,7,opt,name=margin_invoice,json=marginInvoice,proto3" json:"margin_invoice,omitempty"`
-	InitInvoice          string       `protobuf:"bytes,8,opt,name=init_invoice,json=initInvoice,proto3" json:"init_invoice,omitempty"`
-	ContractType         ContractType `protobuf:"varint,9,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
-	InvoicesPaid         bool         `protobuf:"varint,10,opt,name=invoices_paid,json=invoicesPaid,proto3" json:"invoices_paid,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Uuid            string       `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Asset           string       `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount          float64      `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	AmountSatMargin int64        `protobuf:"varint,5,opt,name=amount_sat_margin,json=amountSatMargin,proto3" json:"amount_sat_margin,omitempty"`
+	AmountSatInit   int64        `protobuf:"varint,6,opt,name=amount_sat_init,json=amountSatInit,proto3" json:"amount_sat_init,omitempty"`
+	MarginInvoice   string       `protobuf:"bytes,7,opt,name=margin_invoice,json=marginInvoice,proto3" json:"margin_invoice,omitempty"`
+	InitInvoice     string       `protobuf:"bytes,8,opt,name=init_invoice,json=initInvoice,proto3" json:"init_invoice,omitempty"`
+	ContractType    ContractType `protobuf:"varint,9,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
+	// the value of the contract in sats, as of the last rebalance
+	AmountSat     int64 `protobuf:"varint,11,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	NumRebalances int64 `protobuf:"varint,12,opt,name=num_rebalances,json=numRebalances,proto3" json:"num_rebalances,omitempty"`
+	// the pubkey of the node that created the contract invoices
+	ServerPubkey string         `protobuf:"bytes,13,opt,name=server_pubkey,json=serverPubkey,proto3" json:"server_pubkey,omitempty"`
+	Status       ContractStatus `protobuf:"varint,14,opt,name=status,proto3,enum=larpc.ContractStatus" json:"status,omitempty"`
+	// every status the contract has been in, oldest first
+	StatusHistory []*ContractStatusChange `protobuf:"bytes,15,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	// the invoices of the contract are marked paid as soon as the payment
+	// succeeds, so an interrupted open never pays an invoice twice
+	MarginPaymentHash string `protobuf:"bytes,16,opt,name=margin_payment_hash,json=marginPaymentHash,proto3" json:"margin_payment_hash,omitempty"`
+	MarginPaid        bool   `protobuf:"varint,17,opt,name=margin_paid,json=marginPaid,proto3" json:"margin_paid,omitempty"`
+	InitPaymentHash   string `protobuf:"bytes,18,opt,name=init_payment_hash,json=initPaymentHash,proto3" json:"init_payment_hash,omitempty"`
+	InitPaid          bool   `protobuf:"varint,19,opt,name=init_paid,json=initPaid,proto3" json:"init_paid,omitempty"`
+	// the result of validating the quote of the server when the contract
+	// was created
+	QuoteValidation *QuoteValidation `protobuf:"bytes,20,opt,name=quote_validation,json=quoteValidation,proto3" json:"quote_validation,omitempty"`
+	// unix timestamp of when the first invoice of the contract expires. A
+	// contract that is not opened by then expires.
+	ExpiresAt int64 `protobuf:"varint,21,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// the exact amount as a decimal string, with the decimals of the asset.
+	// amount is its nearest float. Contracts created before amounts were
+	// exact do not have it.
+	AmountDecimal        string   `protobuf:"bytes,22,opt,name=amount_decimal,json=amountDecimal,proto3" json:"amount_decimal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ClientContract) Reset()         { *m = ClientContract{} }
@@ -121,27 +331,268 @@ func (m *ClientContract) GetContractType() ContractType {
 	return ContractType_FUNDED
 }
 
-func (m *ClientContract) GetInvoicesPaid() bool {
+func (m *ClientContract) GetAmountSat() int64 {
+	if m != nil {
+		return m.AmountSat
+	}
+	return 0
+}
+
+func (m *ClientContract) GetNumRebalances() int64 {
+	if m != nil {
+		return m.NumRebalances
+	}
+	return 0
+}
+
+func (m *ClientContract) GetServerPubkey() string {
+	if m != nil {
+		return m.ServerPubkey
+	}
+	return ""
+}
+
+func (m *ClientContract) GetStatus() ContractStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ContractStatus_CREATED
+}
+
+func (m *ClientContract) GetStatusHistory() []*ContractStatusChange {
+	if m != nil {
+		return m.StatusHistory
+	}
+	return nil
+}
+
+func (m *ClientContract) GetMarginPaymentHash() string {
+	if m != nil {
+		return m.MarginPaymentHash
+	}
+	return ""
+}
+
+func (m *ClientContract) GetMarginPaid() bool {
+	if m != nil {
+		return m.MarginPaid
+	}
+	return false
+}
+
+func (m *ClientContract) GetInitPaymentHash() string {
+	if m != nil {
+		return m.InitPaymentHash
+	}
+	return ""
+}
+
+func (m *ClientContract) GetInitPaid() bool {
 	if m != nil {
-		return m.InvoicesPaid
+		return m.InitPaid
 	}
 	return false
 }
 
+func (m *ClientContract) GetQuoteValidation() *QuoteValidation {
+	if m != nil {
+		return m.QuoteValidation
+	}
+	return nil
+}
+
+func (m *ClientContract) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *ClientContract) GetAmountDecimal() string {
+	if m != nil {
+		return m.AmountDecimal
+	}
+	return ""
+}
+
+type ContractStatusChange struct {
+	Status               ContractStatus `protobuf:"varint,1,opt,name=status,proto3,enum=larpc.ContractStatus" json:"status,omitempty"`
+	Timestamp            int64          `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Reason               string         `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ContractStatusChange) Reset()         { *m = ContractStatusChange{} }
+func (m *ContractStatusChange) String() string { return proto.CompactTextString(m) }
+func (*ContractStatusChange) ProtoMessage()    {}
+func (*ContractStatusChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{1}
+}
+
+func (m *ContractStatusChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractStatusChange.Unmarshal(m, b)
+}
+func (m *ContractStatusChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractStatusChange.Marshal(b, m, deterministic)
+}
+func (m *ContractStatusChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractStatusChange.Merge(m, src)
+}
+func (m *ContractStatusChange) XXX_Size() int {
+	return xxx_messageInfo_ContractStatusChange.Size(m)
+}
+func (m *ContractStatusChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractStatusChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractStatusChange proto.InternalMessageInfo
+
+func (m *ContractStatusChange) GetStatus() ContractStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ContractStatus_CREATED
+}
+
+func (m *ContractStatusChange) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *ContractStatusChange) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// Rebalance is a single rebalancing step of a contract
+type Rebalance struct {
+	ContractUuid string  `protobuf:"bytes,1,opt,name=contract_uuid,json=contractUuid,proto3" json:"contract_uuid,omitempty"`
+	Id           int64   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	AssetPrice   float64 `protobuf:"fixed64,3,opt,name=asset_price,json=assetPrice,proto3" json:"asset_price,omitempty"`
+	// positive if the server owes us, negative if we owe the server
+	AmountSat   int64          `protobuf:"varint,4,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	PayReq      string         `protobuf:"bytes,5,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
+	State       RebalanceState `protobuf:"varint,6,opt,name=state,proto3,enum=larpc.RebalanceState" json:"state,omitempty"`
+	CreatedAt   int64          `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt int64          `protobuf:"varint,8,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// the exact price as a decimal string, asset_price is its nearest float
+	AssetPriceDecimal    string   `protobuf:"bytes,9,opt,name=asset_price_decimal,json=assetPriceDecimal,proto3" json:"asset_price_decimal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Rebalance) Reset()         { *m = Rebalance{} }
+func (m *Rebalance) String() string { return proto.CompactTextString(m) }
+func (*Rebalance) ProtoMessage()    {}
+func (*Rebalance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{2}
+}
+
+func (m *Rebalance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rebalance.Unmarshal(m, b)
+}
+func (m *Rebalance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rebalance.Marshal(b, m, deterministic)
+}
+func (m *Rebalance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rebalance.Merge(m, src)
+}
+func (m *Rebalance) XXX_Size() int {
+	return xxx_messageInfo_Rebalance.Size(m)
+}
+func (m *Rebalance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rebalance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rebalance proto.InternalMessageInfo
+
+func (m *Rebalance) GetContractUuid() string {
+	if m != nil {
+		return m.ContractUuid
+	}
+	return ""
+}
+
+func (m *Rebalance) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Rebalance) GetAssetPrice() float64 {
+	if m != nil {
+		return m.AssetPrice
+	}
+	return 0
+}
+
+func (m *Rebalance) GetAmountSat() int64 {
+	if m != nil {
+		return m.AmountSat
+	}
+	return 0
+}
+
+func (m *Rebalance) GetPayReq() string {
+	if m != nil {
+		return m.PayReq
+	}
+	return ""
+}
+
+func (m *Rebalance) GetState() RebalanceState {
+	if m != nil {
+		return m.State
+	}
+	return RebalanceState_REBALANCE_PENDING
+}
+
+func (m *Rebalance) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Rebalance) GetCompletedAt() int64 {
+	if m != nil {
+		return m.CompletedAt
+	}
+	return 0
+}
+
+func (m *Rebalance) GetAssetPriceDecimal() string {
+	if m != nil {
+		return m.AssetPriceDecimal
+	}
+	return ""
+}
+
 type ClientCreateContractRequest struct {
-	Asset                string       `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
-	Amount               float64      `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
-	ContractType         ContractType `protobuf:"varint,3,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Asset        string       `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount       float64      `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	ContractType ContractType `protobuf:"varint,3,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
+	// the exact amount as a decimal string, ie "0.10", with at most the
+	// decimals of the asset. If set, it takes precedence over amount.
+	AmountDecimal        string   `protobuf:"bytes,4,opt,name=amount_decimal,json=amountDecimal,proto3" json:"amount_decimal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ClientCreateContractRequest) Reset()         { *m = ClientCreateContractRequest{} }
 func (m *ClientCreateContractRequest) String() string { return proto.CompactTextString(m) }
 func (*ClientCreateContractRequest) ProtoMessage()    {}
 func (*ClientCreateContractRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{1}
+	return fileDescriptor_014de31d7ac8c57c, []int{3}
 }
 
 func (m *ClientCreateContractRequest) XXX_Unmarshal(b []byte) error {
@@ -183,23 +634,40 @@ func (m *ClientCreateContractRequest) GetContractType() ContractType {
 	return ContractType_FUNDED
 }
 
+func (m *ClientCreateContractRequest) GetAmountDecimal() string {
+	if m != nil {
+		return m.AmountDecimal
+	}
+	return ""
+}
+
 type ClientCreateContractResponse struct {
-	Contract             *ClientContract `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
-	ExpectedMarginAmount int64           `protobuf:"varint,2,opt,name=expected_margin_amount,json=expectedMarginAmount,proto3" json:"expected_margin_amount,omitempty"`
-	ExpectedInitAmount   int64           `protobuf:"varint,3,opt,name=expected_init_amount,json=expectedInitAmount,proto3" json:"expected_init_amount,omitempty"`
-	OurPrice             float64         `protobuf:"fixed64,4,opt,name=our_price,json=ourPrice,proto3" json:"our_price,omitempty"`
-	ServerPrice          float64         `protobuf:"fixed64,5,opt,name=server_price,json=serverPrice,proto3" json:"server_price,omitempty"`
-	PercentMargin        float64         `protobuf:"fixed64,6,opt,name=percent_margin,json=percentMargin,proto3" json:"percent_margin,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Contract             *ClientContract  `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
+	ExpectedMarginAmount int64            `protobuf:"varint,2,opt,name=expected_margin_amount,json=expectedMarginAmount,proto3" json:"expected_margin_amount,omitempty"`
+	ExpectedInitAmount   int64            `protobuf:"varint,3,opt,name=expected_init_amount,json=expectedInitAmount,proto3" json:"expected_init_amount,omitempty"`
+	OurPrice             float64          `protobuf:"fixed64,4,opt,name=our_price,json=ourPrice,proto3" json:"our_price,omitempty"`
+	ServerPrice          float64          `protobuf:"fixed64,5,opt,name=server_price,json=serverPrice,proto3" json:"server_price,omitempty"`
+	PercentMargin        float64          `protobuf:"fixed64,6,opt,name=percent_margin,json=percentMargin,proto3" json:"percent_margin,omitempty"`
+	QuoteValidation      *QuoteValidation `protobuf:"bytes,7,opt,name=quote_validation,json=quoteValidation,proto3" json:"quote_validation,omitempty"`
+	// the exact prices and margin as decimal strings, the doubles are their
+	// nearest floats
+	OurPriceDecimal      string `protobuf:"bytes,8,opt,name=our_price_decimal,json=ourPriceDecimal,proto3" json:"our_price_decimal,omitempty"`
+	ServerPriceDecimal   string `protobuf:"bytes,9,opt,name=server_price_decimal,json=serverPriceDecimal,proto3" json:"server_price_decimal,omitempty"`
+	PercentMarginDecimal string `protobuf:"bytes,10,opt,name=percent_margin_decimal,json=percentMarginDecimal,proto3" json:"percent_margin_decimal,omitempty"`
+	// the expected amounts in millisatoshis, before they are rounded to the
+	// sats of the invoices
+	ExpectedMarginAmountMsat int64    `protobuf:"varint,11,opt,name=expected_margin_amount_msat,json=expectedMarginAmountMsat,proto3" json:"expected_margin_amount_msat,omitempty"`
+	ExpectedInitAmountMsat   int64    `protobuf:"varint,12,opt,name=expected_init_amount_msat,json=expectedInitAmountMsat,proto3" json:"expected_init_amount_msat,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{} `json:"-"`
+	XXX_unrecognized         []byte   `json:"-"`
+	XXX_sizecache            int32    `json:"-"`
 }
 
 func (m *ClientCreateContractResponse) Reset()         { *m = ClientCreateContractResponse{} }
 func (m *ClientCreateContractResponse) String() string { return proto.CompactTextString(m) }
 func (*ClientCreateContractResponse) ProtoMessage()    {}
 func (*ClientCreateContractResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{2}
+	return fileDescriptor_014de31d7ac8c57c, []int{4}
 }
 
 func (m *ClientCreateContractResponse) XXX_Unmarshal(b []byte) error {
@@ -262,31 +730,151 @@ func (m *ClientCreateContractResponse) GetPercentMargin() float64 {
 	return 0
 }
 
-type ClientOpenContractRequest struct {
-	Uuid                 string   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ClientCreateContractResponse) GetQuoteValidation() *QuoteValidation {
+	if m != nil {
+		return m.QuoteValidation
+	}
+	return nil
 }
 
-func (m *ClientOpenContractRequest) Reset()         { *m = ClientOpenContractRequest{} }
-func (m *ClientOpenContractRequest) String() string { return proto.CompactTextString(m) }
-func (*ClientOpenContractRequest) ProtoMessage()    {}
-func (*ClientOpenContractRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{3}
+func (m *ClientCreateContractResponse) GetOurPriceDecimal() string {
+	if m != nil {
+		return m.OurPriceDecimal
+	}
+	return ""
 }
 
-func (m *ClientOpenContractRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ClientOpenContractRequest.Unmarshal(m, b)
-}
-func (m *ClientOpenContractRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ClientOpenContractRequest.Marshal(b, m, deterministic)
+func (m *ClientCreateContractResponse) GetServerPriceDecimal() string {
+	if m != nil {
+		return m.ServerPriceDecimal
+	}
+	return ""
 }
-func (m *ClientOpenContractRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ClientOpenContractRequest.Merge(m, src)
+
+func (m *ClientCreateContractResponse) GetPercentMarginDecimal() string {
+	if m != nil {
+		return m.PercentMarginDecimal
+	}
+	return ""
 }
-func (m *ClientOpenContractRequest) XXX_Size() int {
-	return xxx_messageInfo_ClientOpenContractRequest.Size(m)
+
+func (m *ClientCreateContractResponse) GetExpectedMarginAmountMsat() int64 {
+	if m != nil {
+		return m.ExpectedMarginAmountMsat
+	}
+	return 0
+}
+
+func (m *ClientCreateContractResponse) GetExpectedInitAmountMsat() int64 {
+	if m != nil {
+		return m.ExpectedInitAmountMsat
+	}
+	return 0
+}
+
+// QuoteValidation is the result of checking a quote from the server against
+// our own price, and the quote policies of the client
+type QuoteValidation struct {
+	Accepted bool `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	// why the quote was rejected, empty if it was accepted
+	Reasons []string `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	// how many percent the price of the server differs from ours
+	PriceDeviationPercent float64 `protobuf:"fixed64,3,opt,name=price_deviation_percent,json=priceDeviationPercent,proto3" json:"price_deviation_percent,omitempty"`
+	// the policies the quote was validated against
+	MaxPriceDeviationPercent float64  `protobuf:"fixed64,4,opt,name=max_price_deviation_percent,json=maxPriceDeviationPercent,proto3" json:"max_price_deviation_percent,omitempty"`
+	MaxMarginPercent         float64  `protobuf:"fixed64,5,opt,name=max_margin_percent,json=maxMarginPercent,proto3" json:"max_margin_percent,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{} `json:"-"`
+	XXX_unrecognized         []byte   `json:"-"`
+	XXX_sizecache            int32    `json:"-"`
+}
+
+func (m *QuoteValidation) Reset()         { *m = QuoteValidation{} }
+func (m *QuoteValidation) String() string { return proto.CompactTextString(m) }
+func (*QuoteValidation) ProtoMessage()    {}
+func (*QuoteValidation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{5}
+}
+
+func (m *QuoteValidation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuoteValidation.Unmarshal(m, b)
+}
+func (m *QuoteValidation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuoteValidation.Marshal(b, m, deterministic)
+}
+func (m *QuoteValidation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuoteValidation.Merge(m, src)
+}
+func (m *QuoteValidation) XXX_Size() int {
+	return xxx_messageInfo_QuoteValidation.Size(m)
+}
+func (m *QuoteValidation) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuoteValidation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuoteValidation proto.InternalMessageInfo
+
+func (m *QuoteValidation) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *QuoteValidation) GetReasons() []string {
+	if m != nil {
+		return m.Reasons
+	}
+	return nil
+}
+
+func (m *QuoteValidation) GetPriceDeviationPercent() float64 {
+	if m != nil {
+		return m.PriceDeviationPercent
+	}
+	return 0
+}
+
+func (m *QuoteValidation) GetMaxPriceDeviationPercent() float64 {
+	if m != nil {
+		return m.MaxPriceDeviationPercent
+	}
+	return 0
+}
+
+func (m *QuoteValidation) GetMaxMarginPercent() float64 {
+	if m != nil {
+		return m.MaxMarginPercent
+	}
+	return 0
+}
+
+type ClientOpenContractRequest struct {
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// overrides how the daemon pays the invoices of the contract
+	PaymentOptions       *PaymentOptions `protobuf:"bytes,2,opt,name=payment_options,json=paymentOptions,proto3" json:"payment_options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ClientOpenContractRequest) Reset()         { *m = ClientOpenContractRequest{} }
+func (m *ClientOpenContractRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientOpenContractRequest) ProtoMessage()    {}
+func (*ClientOpenContractRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{6}
+}
+
+func (m *ClientOpenContractRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientOpenContractRequest.Unmarshal(m, b)
+}
+func (m *ClientOpenContractRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientOpenContractRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientOpenContractRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientOpenContractRequest.Merge(m, src)
+}
+func (m *ClientOpenContractRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientOpenContractRequest.Size(m)
 }
 func (m *ClientOpenContractRequest) XXX_DiscardUnknown() {
 	xxx_messageInfo_ClientOpenContractRequest.DiscardUnknown(m)
@@ -301,6 +889,100 @@ func (m *ClientOpenContractRequest) GetUuid() string {
 	return ""
 }
 
+func (m *ClientOpenContractRequest) GetPaymentOptions() *PaymentOptions {
+	if m != nil {
+		return m.PaymentOptions
+	}
+	return nil
+}
+
+// PaymentOptions controls how invoices are paid. Fields left unset use the
+// payment policy of the daemon.
+type PaymentOptions struct {
+	// the highest routing fee to pay, in sats and in percent of the amount.
+	// The lower of the two applies.
+	MaxFeeSat     int64   `protobuf:"varint,1,opt,name=max_fee_sat,json=maxFeeSat,proto3" json:"max_fee_sat,omitempty"`
+	MaxFeePercent float64 `protobuf:"fixed64,2,opt,name=max_fee_percent,json=maxFeePercent,proto3" json:"max_fee_percent,omitempty"`
+	// how long a single attempt to pay may take
+	TimeoutSeconds int64 `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	// the channel payments must leave through
+	OutgoingChanId uint64 `protobuf:"varint,4,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
+	// pay through one of our channels with the server node
+	PinToServer bool `protobuf:"varint,5,opt,name=pin_to_server,json=pinToServer,proto3" json:"pin_to_server,omitempty"`
+	// how many times to try paying an invoice before giving up
+	MaxAttempts          int32    `protobuf:"varint,6,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PaymentOptions) Reset()         { *m = PaymentOptions{} }
+func (m *PaymentOptions) String() string { return proto.CompactTextString(m) }
+func (*PaymentOptions) ProtoMessage()    {}
+func (*PaymentOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{7}
+}
+
+func (m *PaymentOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PaymentOptions.Unmarshal(m, b)
+}
+func (m *PaymentOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PaymentOptions.Marshal(b, m, deterministic)
+}
+func (m *PaymentOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PaymentOptions.Merge(m, src)
+}
+func (m *PaymentOptions) XXX_Size() int {
+	return xxx_messageInfo_PaymentOptions.Size(m)
+}
+func (m *PaymentOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_PaymentOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PaymentOptions proto.InternalMessageInfo
+
+func (m *PaymentOptions) GetMaxFeeSat() int64 {
+	if m != nil {
+		return m.MaxFeeSat
+	}
+	return 0
+}
+
+func (m *PaymentOptions) GetMaxFeePercent() float64 {
+	if m != nil {
+		return m.MaxFeePercent
+	}
+	return 0
+}
+
+func (m *PaymentOptions) GetTimeoutSeconds() int64 {
+	if m != nil {
+		return m.TimeoutSeconds
+	}
+	return 0
+}
+
+func (m *PaymentOptions) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+func (m *PaymentOptions) GetPinToServer() bool {
+	if m != nil {
+		return m.PinToServer
+	}
+	return false
+}
+
+func (m *PaymentOptions) GetMaxAttempts() int32 {
+	if m != nil {
+		return m.MaxAttempts
+	}
+	return 0
+}
+
 type ClientOpenContractResponse struct {
 	Contract             *ClientContract `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
@@ -312,7 +994,7 @@ func (m *ClientOpenContractResponse) Reset()         { *m = ClientOpenContractRe
 func (m *ClientOpenContractResponse) String() string { return proto.CompactTextString(m) }
 func (*ClientOpenContractResponse) ProtoMessage()    {}
 func (*ClientOpenContractResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{4}
+	return fileDescriptor_014de31d7ac8c57c, []int{8}
 }
 
 func (m *ClientOpenContractResponse) XXX_Unmarshal(b []byte) error {
@@ -351,7 +1033,7 @@ func (m *ClientCloseContractRequest) Reset()         { *m = ClientCloseContractR
 func (m *ClientCloseContractRequest) String() string { return proto.CompactTextString(m) }
 func (*ClientCloseContractRequest) ProtoMessage()    {}
 func (*ClientCloseContractRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{5}
+	return fileDescriptor_014de31d7ac8c57c, []int{9}
 }
 
 func (m *ClientCloseContractRequest) XXX_Unmarshal(b []byte) error {
@@ -389,7 +1071,7 @@ func (m *ClientCloseContractResponse) Reset()         { *m = ClientCloseContract
 func (m *ClientCloseContractResponse) String() string { return proto.CompactTextString(m) }
 func (*ClientCloseContractResponse) ProtoMessage()    {}
 func (*ClientCloseContractResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{6}
+	return fileDescriptor_014de31d7ac8c57c, []int{10}
 }
 
 func (m *ClientCloseContractResponse) XXX_Unmarshal(b []byte) error {
@@ -411,6 +1093,8 @@ func (m *ClientCloseContractResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_ClientCloseContractResponse proto.InternalMessageInfo
 
 type ClientListContractsRequest struct {
+	// also list closed, failed and expired contracts
+	IncludeInactive      bool     `protobuf:"varint,1,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -420,7 +1104,7 @@ func (m *ClientListContractsRequest) Reset()         { *m = ClientListContractsR
 func (m *ClientListContractsRequest) String() string { return proto.CompactTextString(m) }
 func (*ClientListContractsRequest) ProtoMessage()    {}
 func (*ClientListContractsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{7}
+	return fileDescriptor_014de31d7ac8c57c, []int{11}
 }
 
 func (m *ClientListContractsRequest) XXX_Unmarshal(b []byte) error {
@@ -441,6 +1125,13 @@ func (m *ClientListContractsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ClientListContractsRequest proto.InternalMessageInfo
 
+func (m *ClientListContractsRequest) GetIncludeInactive() bool {
+	if m != nil {
+		return m.IncludeInactive
+	}
+	return false
+}
+
 type ClientListContractsResponse struct {
 	Contracts            []*ClientContract `protobuf:"bytes,1,rep,name=contracts,proto3" json:"contracts,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
@@ -452,7 +1143,7 @@ func (m *ClientListContractsResponse) Reset()         { *m = ClientListContracts
 func (m *ClientListContractsResponse) String() string { return proto.CompactTextString(m) }
 func (*ClientListContractsResponse) ProtoMessage()    {}
 func (*ClientListContractsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{8}
+	return fileDescriptor_014de31d7ac8c57c, []int{12}
 }
 
 func (m *ClientListContractsResponse) XXX_Unmarshal(b []byte) error {
@@ -481,7 +1172,9 @@ func (m *ClientListContractsResponse) GetContracts() []*ClientContract {
 }
 
 type ClientRequestPaymentRequestRequest struct {
-	AmountSat            int64    `protobuf:"varint,1,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	AmountSat int64 `protobuf:"varint,1,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	// the contract the payment settles
+	Uuid                 string   `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -491,7 +1184,7 @@ func (m *ClientRequestPaymentRequestRequest) Reset()         { *m = ClientReques
 func (m *ClientRequestPaymentRequestRequest) String() string { return proto.CompactTextString(m) }
 func (*ClientRequestPaymentRequestRequest) ProtoMessage()    {}
 func (*ClientRequestPaymentRequestRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{9}
+	return fileDescriptor_014de31d7ac8c57c, []int{13}
 }
 
 func (m *ClientRequestPaymentRequestRequest) XXX_Unmarshal(b []byte) error {
@@ -519,6 +1212,13 @@ func (m *ClientRequestPaymentRequestRequest) GetAmountSat() int64 {
 	return 0
 }
 
+func (m *ClientRequestPaymentRequestRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
 type ClientRequestPaymentRequestResponse struct {
 	PayReq               string   `protobuf:"bytes,1,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -530,7 +1230,7 @@ func (m *ClientRequestPaymentRequestResponse) Reset()         { *m = ClientReque
 func (m *ClientRequestPaymentRequestResponse) String() string { return proto.CompactTextString(m) }
 func (*ClientRequestPaymentRequestResponse) ProtoMessage()    {}
 func (*ClientRequestPaymentRequestResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{10}
+	return fileDescriptor_014de31d7ac8c57c, []int{14}
 }
 
 func (m *ClientRequestPaymentRequestResponse) XXX_Unmarshal(b []byte) error {
@@ -559,7 +1259,9 @@ func (m *ClientRequestPaymentRequestResponse) GetPayReq() string {
 }
 
 type ClientRequestPaymentRequest struct {
-	PayReq               string   `protobuf:"bytes,1,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
+	PayReq string `protobuf:"bytes,1,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
+	// the contract the payment settles
+	Uuid                 string   `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -569,101 +1271,1119 @@ func (m *ClientRequestPaymentRequest) Reset()         { *m = ClientRequestPaymen
 func (m *ClientRequestPaymentRequest) String() string { return proto.CompactTextString(m) }
 func (*ClientRequestPaymentRequest) ProtoMessage()    {}
 func (*ClientRequestPaymentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{11}
+	return fileDescriptor_014de31d7ac8c57c, []int{15}
+}
+
+func (m *ClientRequestPaymentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientRequestPaymentRequest.Unmarshal(m, b)
+}
+func (m *ClientRequestPaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientRequestPaymentRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientRequestPaymentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientRequestPaymentRequest.Merge(m, src)
+}
+func (m *ClientRequestPaymentRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientRequestPaymentRequest.Size(m)
+}
+func (m *ClientRequestPaymentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientRequestPaymentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientRequestPaymentRequest proto.InternalMessageInfo
+
+func (m *ClientRequestPaymentRequest) GetPayReq() string {
+	if m != nil {
+		return m.PayReq
+	}
+	return ""
+}
+
+func (m *ClientRequestPaymentRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+type ClientRequestPaymentResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientRequestPaymentResponse) Reset()         { *m = ClientRequestPaymentResponse{} }
+func (m *ClientRequestPaymentResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientRequestPaymentResponse) ProtoMessage()    {}
+func (*ClientRequestPaymentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{16}
+}
+
+func (m *ClientRequestPaymentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientRequestPaymentResponse.Unmarshal(m, b)
+}
+func (m *ClientRequestPaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientRequestPaymentResponse.Marshal(b, m, deterministic)
+}
+func (m *ClientRequestPaymentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientRequestPaymentResponse.Merge(m, src)
+}
+func (m *ClientRequestPaymentResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientRequestPaymentResponse.Size(m)
+}
+func (m *ClientRequestPaymentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientRequestPaymentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientRequestPaymentResponse proto.InternalMessageInfo
+
+type ClientSubscribeContractsRequest struct {
+	// if true, all existing contracts are sent as SNAPSHOT updates before
+	// any new updates
+	IncludeSnapshot      bool     `protobuf:"varint,1,opt,name=include_snapshot,json=includeSnapshot,proto3" json:"include_snapshot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientSubscribeContractsRequest) Reset()         { *m = ClientSubscribeContractsRequest{} }
+func (m *ClientSubscribeContractsRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientSubscribeContractsRequest) ProtoMessage()    {}
+func (*ClientSubscribeContractsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{17}
+}
+
+func (m *ClientSubscribeContractsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientSubscribeContractsRequest.Unmarshal(m, b)
+}
+func (m *ClientSubscribeContractsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientSubscribeContractsRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientSubscribeContractsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientSubscribeContractsRequest.Merge(m, src)
+}
+func (m *ClientSubscribeContractsRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientSubscribeContractsRequest.Size(m)
+}
+func (m *ClientSubscribeContractsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientSubscribeContractsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientSubscribeContractsRequest proto.InternalMessageInfo
+
+func (m *ClientSubscribeContractsRequest) GetIncludeSnapshot() bool {
+	if m != nil {
+		return m.IncludeSnapshot
+	}
+	return false
+}
+
+type ClientContractUpdate struct {
+	Type                 ClientContractUpdate_UpdateType `protobuf:"varint,1,opt,name=type,proto3,enum=larpc.ClientContractUpdate_UpdateType" json:"type,omitempty"`
+	Contract             *ClientContract                 `protobuf:"bytes,2,opt,name=contract,proto3" json:"contract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *ClientContractUpdate) Reset()         { *m = ClientContractUpdate{} }
+func (m *ClientContractUpdate) String() string { return proto.CompactTextString(m) }
+func (*ClientContractUpdate) ProtoMessage()    {}
+func (*ClientContractUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{18}
+}
+
+func (m *ClientContractUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientContractUpdate.Unmarshal(m, b)
+}
+func (m *ClientContractUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientContractUpdate.Marshal(b, m, deterministic)
+}
+func (m *ClientContractUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientContractUpdate.Merge(m, src)
+}
+func (m *ClientContractUpdate) XXX_Size() int {
+	return xxx_messageInfo_ClientContractUpdate.Size(m)
+}
+func (m *ClientContractUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientContractUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientContractUpdate proto.InternalMessageInfo
+
+func (m *ClientContractUpdate) GetType() ClientContractUpdate_UpdateType {
+	if m != nil {
+		return m.Type
+	}
+	return ClientContractUpdate_SNAPSHOT
+}
+
+func (m *ClientContractUpdate) GetContract() *ClientContract {
+	if m != nil {
+		return m.Contract
+	}
+	return nil
+}
+
+type ClientListPaymentsRequest struct {
+	// only list payments of this contract, lists all payments if empty
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// also list invoices we have created that are not paid yet
+	IncludeUnsettled     bool     `protobuf:"varint,2,opt,name=include_unsettled,json=includeUnsettled,proto3" json:"include_unsettled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientListPaymentsRequest) Reset()         { *m = ClientListPaymentsRequest{} }
+func (m *ClientListPaymentsRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientListPaymentsRequest) ProtoMessage()    {}
+func (*ClientListPaymentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{19}
+}
+
+func (m *ClientListPaymentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientListPaymentsRequest.Unmarshal(m, b)
+}
+func (m *ClientListPaymentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientListPaymentsRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientListPaymentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientListPaymentsRequest.Merge(m, src)
+}
+func (m *ClientListPaymentsRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientListPaymentsRequest.Size(m)
+}
+func (m *ClientListPaymentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientListPaymentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientListPaymentsRequest proto.InternalMessageInfo
+
+func (m *ClientListPaymentsRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *ClientListPaymentsRequest) GetIncludeUnsettled() bool {
+	if m != nil {
+		return m.IncludeUnsettled
+	}
+	return false
+}
+
+type ClientListPaymentsResponse struct {
+	Payments             []*Payment `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ClientListPaymentsResponse) Reset()         { *m = ClientListPaymentsResponse{} }
+func (m *ClientListPaymentsResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientListPaymentsResponse) ProtoMessage()    {}
+func (*ClientListPaymentsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{20}
+}
+
+func (m *ClientListPaymentsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientListPaymentsResponse.Unmarshal(m, b)
+}
+func (m *ClientListPaymentsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientListPaymentsResponse.Marshal(b, m, deterministic)
+}
+func (m *ClientListPaymentsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientListPaymentsResponse.Merge(m, src)
+}
+func (m *ClientListPaymentsResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientListPaymentsResponse.Size(m)
+}
+func (m *ClientListPaymentsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientListPaymentsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientListPaymentsResponse proto.InternalMessageInfo
+
+func (m *ClientListPaymentsResponse) GetPayments() []*Payment {
+	if m != nil {
+		return m.Payments
+	}
+	return nil
+}
+
+type ClientSubscribePaymentsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientSubscribePaymentsRequest) Reset()         { *m = ClientSubscribePaymentsRequest{} }
+func (m *ClientSubscribePaymentsRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientSubscribePaymentsRequest) ProtoMessage()    {}
+func (*ClientSubscribePaymentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{21}
+}
+
+func (m *ClientSubscribePaymentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientSubscribePaymentsRequest.Unmarshal(m, b)
+}
+func (m *ClientSubscribePaymentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientSubscribePaymentsRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientSubscribePaymentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientSubscribePaymentsRequest.Merge(m, src)
+}
+func (m *ClientSubscribePaymentsRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientSubscribePaymentsRequest.Size(m)
+}
+func (m *ClientSubscribePaymentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientSubscribePaymentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientSubscribePaymentsRequest proto.InternalMessageInfo
+
+type ClientGetConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientGetConfigRequest) Reset()         { *m = ClientGetConfigRequest{} }
+func (m *ClientGetConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientGetConfigRequest) ProtoMessage()    {}
+func (*ClientGetConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{22}
+}
+
+func (m *ClientGetConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetConfigRequest.Unmarshal(m, b)
+}
+func (m *ClientGetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientGetConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetConfigRequest.Merge(m, src)
+}
+func (m *ClientGetConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientGetConfigRequest.Size(m)
+}
+func (m *ClientGetConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientGetConfigRequest proto.InternalMessageInfo
+
+type ClientGetConfigResponse struct {
+	// the config file that was read, it does not have to exist
+	ConfigFile           string         `protobuf:"bytes,1,opt,name=config_file,json=configFile,proto3" json:"config_file,omitempty"`
+	Values               []*ConfigValue `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ClientGetConfigResponse) Reset()         { *m = ClientGetConfigResponse{} }
+func (m *ClientGetConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientGetConfigResponse) ProtoMessage()    {}
+func (*ClientGetConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{23}
+}
+
+func (m *ClientGetConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetConfigResponse.Unmarshal(m, b)
+}
+func (m *ClientGetConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetConfigResponse.Marshal(b, m, deterministic)
+}
+func (m *ClientGetConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetConfigResponse.Merge(m, src)
+}
+func (m *ClientGetConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientGetConfigResponse.Size(m)
+}
+func (m *ClientGetConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientGetConfigResponse proto.InternalMessageInfo
+
+func (m *ClientGetConfigResponse) GetConfigFile() string {
+	if m != nil {
+		return m.ConfigFile
+	}
+	return ""
+}
+
+func (m *ClientGetConfigResponse) GetValues() []*ConfigValue {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type ConfigValue struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// where the value came from, one of flag, env, file or default
+	Source               string   `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConfigValue) Reset()         { *m = ConfigValue{} }
+func (m *ConfigValue) String() string { return proto.CompactTextString(m) }
+func (*ConfigValue) ProtoMessage()    {}
+func (*ConfigValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{24}
+}
+
+func (m *ConfigValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigValue.Unmarshal(m, b)
+}
+func (m *ConfigValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigValue.Marshal(b, m, deterministic)
+}
+func (m *ConfigValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigValue.Merge(m, src)
+}
+func (m *ConfigValue) XXX_Size() int {
+	return xxx_messageInfo_ConfigValue.Size(m)
+}
+func (m *ConfigValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigValue proto.InternalMessageInfo
+
+func (m *ConfigValue) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ConfigValue) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *ConfigValue) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+type ClientGetStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientGetStatusRequest) Reset()         { *m = ClientGetStatusRequest{} }
+func (m *ClientGetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientGetStatusRequest) ProtoMessage()    {}
+func (*ClientGetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{25}
+}
+
+func (m *ClientGetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetStatusRequest.Unmarshal(m, b)
+}
+func (m *ClientGetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientGetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetStatusRequest.Merge(m, src)
+}
+func (m *ClientGetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientGetStatusRequest.Size(m)
+}
+func (m *ClientGetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientGetStatusRequest proto.InternalMessageInfo
+
+type ClientGetStatusResponse struct {
+	Server               *ConnectionStatus `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Lnd                  *ConnectionStatus `protobuf:"bytes,2,opt,name=lnd,proto3" json:"lnd,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ClientGetStatusResponse) Reset()         { *m = ClientGetStatusResponse{} }
+func (m *ClientGetStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientGetStatusResponse) ProtoMessage()    {}
+func (*ClientGetStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{26}
+}
+
+func (m *ClientGetStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetStatusResponse.Unmarshal(m, b)
+}
+func (m *ClientGetStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *ClientGetStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetStatusResponse.Merge(m, src)
+}
+func (m *ClientGetStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientGetStatusResponse.Size(m)
+}
+func (m *ClientGetStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientGetStatusResponse proto.InternalMessageInfo
+
+func (m *ClientGetStatusResponse) GetServer() *ConnectionStatus {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *ClientGetStatusResponse) GetLnd() *ConnectionStatus {
+	if m != nil {
+		return m.Lnd
+	}
+	return nil
+}
+
+type ConnectionStatus struct {
+	Address string          `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	State   ConnectionState `protobuf:"varint,2,opt,name=state,proto3,enum=larpc.ConnectionState" json:"state,omitempty"`
+	// unix timestamp of when the connection entered its current state
+	Since int64 `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	// unix timestamp of when we were last connected, 0 if never
+	LastConnected int64 `protobuf:"varint,4,opt,name=last_connected,json=lastConnected,proto3" json:"last_connected,omitempty"`
+	// why the connection was last lost or could not be made
+	LastError            string   `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectionStatus) Reset()         { *m = ConnectionStatus{} }
+func (m *ConnectionStatus) String() string { return proto.CompactTextString(m) }
+func (*ConnectionStatus) ProtoMessage()    {}
+func (*ConnectionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{27}
+}
+
+func (m *ConnectionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionStatus.Unmarshal(m, b)
+}
+func (m *ConnectionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionStatus.Marshal(b, m, deterministic)
+}
+func (m *ConnectionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionStatus.Merge(m, src)
+}
+func (m *ConnectionStatus) XXX_Size() int {
+	return xxx_messageInfo_ConnectionStatus.Size(m)
+}
+func (m *ConnectionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionStatus proto.InternalMessageInfo
+
+func (m *ConnectionStatus) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ConnectionStatus) GetState() ConnectionState {
+	if m != nil {
+		return m.State
+	}
+	return ConnectionState_CONNECTING
+}
+
+func (m *ConnectionStatus) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *ConnectionStatus) GetLastConnected() int64 {
+	if m != nil {
+		return m.LastConnected
+	}
+	return 0
+}
+
+func (m *ConnectionStatus) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+type ClientGetInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientGetInfoRequest) Reset()         { *m = ClientGetInfoRequest{} }
+func (m *ClientGetInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientGetInfoRequest) ProtoMessage()    {}
+func (*ClientGetInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{28}
+}
+
+func (m *ClientGetInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetInfoRequest.Unmarshal(m, b)
+}
+func (m *ClientGetInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientGetInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetInfoRequest.Merge(m, src)
+}
+func (m *ClientGetInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientGetInfoRequest.Size(m)
+}
+func (m *ClientGetInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientGetInfoRequest proto.InternalMessageInfo
+
+type ClientGetInfoResponse struct {
+	// the version of lacd
+	Version       string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Network       string `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	ServerAddress string `protobuf:"bytes,3,opt,name=server_address,json=serverAddress,proto3" json:"server_address,omitempty"`
+	// the lnd fields are empty until we have reached lnd
+	LndAlias         string `protobuf:"bytes,4,opt,name=lnd_alias,json=lndAlias,proto3" json:"lnd_alias,omitempty"`
+	LndPubkey        string `protobuf:"bytes,5,opt,name=lnd_pubkey,json=lndPubkey,proto3" json:"lnd_pubkey,omitempty"`
+	LndVersion       string `protobuf:"bytes,6,opt,name=lnd_version,json=lndVersion,proto3" json:"lnd_version,omitempty"`
+	NumOpenContracts int64  `protobuf:"varint,7,opt,name=num_open_contracts,json=numOpenContracts,proto3" json:"num_open_contracts,omitempty"`
+	// the margin we have paid for contracts that are not closed yet
+	TotalMarginSat int64 `protobuf:"varint,8,opt,name=total_margin_sat,json=totalMarginSat,proto3" json:"total_margin_sat,omitempty"`
+	// the value of all open contracts in sats, as of their last rebalance
+	TotalContractSat int64 `protobuf:"varint,9,opt,name=total_contract_sat,json=totalContractSat,proto3" json:"total_contract_sat,omitempty"`
+	// the latest price of every asset we have a price for
+	Prices               []*PriceInfo `protobuf:"bytes,10,rep,name=prices,proto3" json:"prices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ClientGetInfoResponse) Reset()         { *m = ClientGetInfoResponse{} }
+func (m *ClientGetInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientGetInfoResponse) ProtoMessage()    {}
+func (*ClientGetInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{29}
+}
+
+func (m *ClientGetInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetInfoResponse.Unmarshal(m, b)
+}
+func (m *ClientGetInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *ClientGetInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetInfoResponse.Merge(m, src)
+}
+func (m *ClientGetInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientGetInfoResponse.Size(m)
+}
+func (m *ClientGetInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientGetInfoResponse proto.InternalMessageInfo
+
+func (m *ClientGetInfoResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ClientGetInfoResponse) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *ClientGetInfoResponse) GetServerAddress() string {
+	if m != nil {
+		return m.ServerAddress
+	}
+	return ""
+}
+
+func (m *ClientGetInfoResponse) GetLndAlias() string {
+	if m != nil {
+		return m.LndAlias
+	}
+	return ""
+}
+
+func (m *ClientGetInfoResponse) GetLndPubkey() string {
+	if m != nil {
+		return m.LndPubkey
+	}
+	return ""
+}
+
+func (m *ClientGetInfoResponse) GetLndVersion() string {
+	if m != nil {
+		return m.LndVersion
+	}
+	return ""
+}
+
+func (m *ClientGetInfoResponse) GetNumOpenContracts() int64 {
+	if m != nil {
+		return m.NumOpenContracts
+	}
+	return 0
+}
+
+func (m *ClientGetInfoResponse) GetTotalMarginSat() int64 {
+	if m != nil {
+		return m.TotalMarginSat
+	}
+	return 0
+}
+
+func (m *ClientGetInfoResponse) GetTotalContractSat() int64 {
+	if m != nil {
+		return m.TotalContractSat
+	}
+	return 0
+}
+
+func (m *ClientGetInfoResponse) GetPrices() []*PriceInfo {
+	if m != nil {
+		return m.Prices
+	}
+	return nil
+}
+
+type PriceInfo struct {
+	Asset string `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	// the price of one bitcoin in asset
+	Value float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	// unix timestamp of when the price was observed
+	Timestamp  int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	AgeSeconds int64 `protobuf:"varint,4,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	// stale prices are too old to be used
+	Stale bool `protobuf:"varint,5,opt,name=stale,proto3" json:"stale,omitempty"`
+	// the exact price as a decimal string, value is its nearest float
+	ValueDecimal         string   `protobuf:"bytes,6,opt,name=value_decimal,json=valueDecimal,proto3" json:"value_decimal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PriceInfo) Reset()         { *m = PriceInfo{} }
+func (m *PriceInfo) String() string { return proto.CompactTextString(m) }
+func (*PriceInfo) ProtoMessage()    {}
+func (*PriceInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{30}
+}
+
+func (m *PriceInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PriceInfo.Unmarshal(m, b)
+}
+func (m *PriceInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PriceInfo.Marshal(b, m, deterministic)
+}
+func (m *PriceInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PriceInfo.Merge(m, src)
+}
+func (m *PriceInfo) XXX_Size() int {
+	return xxx_messageInfo_PriceInfo.Size(m)
+}
+func (m *PriceInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_PriceInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PriceInfo proto.InternalMessageInfo
+
+func (m *PriceInfo) GetAsset() string {
+	if m != nil {
+		return m.Asset
+	}
+	return ""
+}
+
+func (m *PriceInfo) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *PriceInfo) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *PriceInfo) GetAgeSeconds() int64 {
+	if m != nil {
+		return m.AgeSeconds
+	}
+	return 0
+}
+
+func (m *PriceInfo) GetStale() bool {
+	if m != nil {
+		return m.Stale
+	}
+	return false
+}
+
+func (m *PriceInfo) GetValueDecimal() string {
+	if m != nil {
+		return m.ValueDecimal
+	}
+	return ""
+}
+
+type ClientListAssetsRequest struct {
+	// if true, the supported assets are fetched from the server even if we
+	// have fetched them recently
+	Refresh              bool     `protobuf:"varint,1,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientListAssetsRequest) Reset()         { *m = ClientListAssetsRequest{} }
+func (m *ClientListAssetsRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientListAssetsRequest) ProtoMessage()    {}
+func (*ClientListAssetsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{31}
+}
+
+func (m *ClientListAssetsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientListAssetsRequest.Unmarshal(m, b)
+}
+func (m *ClientListAssetsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientListAssetsRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientListAssetsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientListAssetsRequest.Merge(m, src)
+}
+func (m *ClientListAssetsRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientListAssetsRequest.Size(m)
+}
+func (m *ClientListAssetsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientListAssetsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientListAssetsRequest proto.InternalMessageInfo
+
+func (m *ClientListAssetsRequest) GetRefresh() bool {
+	if m != nil {
+		return m.Refresh
+	}
+	return false
+}
+
+type ClientListAssetsResponse struct {
+	// assets supported by the server that we have a price for
+	Assets []string `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	// assets supported by the server that we have no usable price for, so
+	// contracts can not be created in them
+	UnpricedAssets []string `protobuf:"bytes,2,rep,name=unpriced_assets,json=unpricedAssets,proto3" json:"unpriced_assets,omitempty"`
+	// unix timestamp of when the supported assets were fetched from the
+	// server
+	FetchedAt            int64    `protobuf:"varint,3,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientListAssetsResponse) Reset()         { *m = ClientListAssetsResponse{} }
+func (m *ClientListAssetsResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientListAssetsResponse) ProtoMessage()    {}
+func (*ClientListAssetsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{32}
+}
+
+func (m *ClientListAssetsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientListAssetsResponse.Unmarshal(m, b)
+}
+func (m *ClientListAssetsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientListAssetsResponse.Marshal(b, m, deterministic)
+}
+func (m *ClientListAssetsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientListAssetsResponse.Merge(m, src)
+}
+func (m *ClientListAssetsResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientListAssetsResponse.Size(m)
+}
+func (m *ClientListAssetsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientListAssetsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientListAssetsResponse proto.InternalMessageInfo
+
+func (m *ClientListAssetsResponse) GetAssets() []string {
+	if m != nil {
+		return m.Assets
+	}
+	return nil
+}
+
+func (m *ClientListAssetsResponse) GetUnpricedAssets() []string {
+	if m != nil {
+		return m.UnpricedAssets
+	}
+	return nil
+}
+
+func (m *ClientListAssetsResponse) GetFetchedAt() int64 {
+	if m != nil {
+		return m.FetchedAt
+	}
+	return 0
+}
+
+// ServerIdentity is the lightning node of the asset server
+type ServerIdentity struct {
+	Pubkey string         `protobuf:"bytes,1,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	Source IdentitySource `protobuf:"varint,2,opt,name=source,proto3,enum=larpc.IdentitySource" json:"source,omitempty"`
+	// unix timestamp of when the identity was pinned, 0 if it is configured
+	PinnedAt int64 `protobuf:"varint,3,opt,name=pinned_at,json=pinnedAt,proto3" json:"pinned_at,omitempty"`
+	// the address of the server when the identity was pinned
+	ServerAddress        string   `protobuf:"bytes,4,opt,name=server_address,json=serverAddress,proto3" json:"server_address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServerIdentity) Reset()         { *m = ServerIdentity{} }
+func (m *ServerIdentity) String() string { return proto.CompactTextString(m) }
+func (*ServerIdentity) ProtoMessage()    {}
+func (*ServerIdentity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{33}
+}
+
+func (m *ServerIdentity) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerIdentity.Unmarshal(m, b)
+}
+func (m *ServerIdentity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerIdentity.Marshal(b, m, deterministic)
+}
+func (m *ServerIdentity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerIdentity.Merge(m, src)
+}
+func (m *ServerIdentity) XXX_Size() int {
+	return xxx_messageInfo_ServerIdentity.Size(m)
+}
+func (m *ServerIdentity) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerIdentity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServerIdentity proto.InternalMessageInfo
+
+func (m *ServerIdentity) GetPubkey() string {
+	if m != nil {
+		return m.Pubkey
+	}
+	return ""
+}
+
+func (m *ServerIdentity) GetSource() IdentitySource {
+	if m != nil {
+		return m.Source
+	}
+	return IdentitySource_TRUST_ON_FIRST_USE
+}
+
+func (m *ServerIdentity) GetPinnedAt() int64 {
+	if m != nil {
+		return m.PinnedAt
+	}
+	return 0
+}
+
+func (m *ServerIdentity) GetServerAddress() string {
+	if m != nil {
+		return m.ServerAddress
+	}
+	return ""
+}
+
+type ClientGetServerIdentityRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientGetServerIdentityRequest) Reset()         { *m = ClientGetServerIdentityRequest{} }
+func (m *ClientGetServerIdentityRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientGetServerIdentityRequest) ProtoMessage()    {}
+func (*ClientGetServerIdentityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{34}
+}
+
+func (m *ClientGetServerIdentityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetServerIdentityRequest.Unmarshal(m, b)
+}
+func (m *ClientGetServerIdentityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetServerIdentityRequest.Marshal(b, m, deterministic)
+}
+func (m *ClientGetServerIdentityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetServerIdentityRequest.Merge(m, src)
+}
+func (m *ClientGetServerIdentityRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientGetServerIdentityRequest.Size(m)
+}
+func (m *ClientGetServerIdentityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetServerIdentityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientGetServerIdentityRequest proto.InternalMessageInfo
+
+type ClientGetServerIdentityResponse struct {
+	// not set if no identity is pinned yet
+	Identity             *ServerIdentity `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ClientGetServerIdentityResponse) Reset()         { *m = ClientGetServerIdentityResponse{} }
+func (m *ClientGetServerIdentityResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientGetServerIdentityResponse) ProtoMessage()    {}
+func (*ClientGetServerIdentityResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{35}
 }
 
-func (m *ClientRequestPaymentRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ClientRequestPaymentRequest.Unmarshal(m, b)
+func (m *ClientGetServerIdentityResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientGetServerIdentityResponse.Unmarshal(m, b)
 }
-func (m *ClientRequestPaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ClientRequestPaymentRequest.Marshal(b, m, deterministic)
+func (m *ClientGetServerIdentityResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientGetServerIdentityResponse.Marshal(b, m, deterministic)
 }
-func (m *ClientRequestPaymentRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ClientRequestPaymentRequest.Merge(m, src)
+func (m *ClientGetServerIdentityResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientGetServerIdentityResponse.Merge(m, src)
 }
-func (m *ClientRequestPaymentRequest) XXX_Size() int {
-	return xxx_messageInfo_ClientRequestPaymentRequest.Size(m)
+func (m *ClientGetServerIdentityResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientGetServerIdentityResponse.Size(m)
 }
-func (m *ClientRequestPaymentRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ClientRequestPaymentRequest.DiscardUnknown(m)
+func (m *ClientGetServerIdentityResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientGetServerIdentityResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ClientRequestPaymentRequest proto.InternalMessageInfo
+var xxx_messageInfo_ClientGetServerIdentityResponse proto.InternalMessageInfo
 
-func (m *ClientRequestPaymentRequest) GetPayReq() string {
+func (m *ClientGetServerIdentityResponse) GetIdentity() *ServerIdentity {
 	if m != nil {
-		return m.PayReq
+		return m.Identity
 	}
-	return ""
+	return nil
 }
 
-type ClientRequestPaymentResponse struct {
+type ClientResetServerIdentityRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ClientRequestPaymentResponse) Reset()         { *m = ClientRequestPaymentResponse{} }
-func (m *ClientRequestPaymentResponse) String() string { return proto.CompactTextString(m) }
-func (*ClientRequestPaymentResponse) ProtoMessage()    {}
-func (*ClientRequestPaymentResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{12}
+func (m *ClientResetServerIdentityRequest) Reset()         { *m = ClientResetServerIdentityRequest{} }
+func (m *ClientResetServerIdentityRequest) String() string { return proto.CompactTextString(m) }
+func (*ClientResetServerIdentityRequest) ProtoMessage()    {}
+func (*ClientResetServerIdentityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{36}
 }
 
-func (m *ClientRequestPaymentResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ClientRequestPaymentResponse.Unmarshal(m, b)
+func (m *ClientResetServerIdentityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientResetServerIdentityRequest.Unmarshal(m, b)
 }
-func (m *ClientRequestPaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ClientRequestPaymentResponse.Marshal(b, m, deterministic)
+func (m *ClientResetServerIdentityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientResetServerIdentityRequest.Marshal(b, m, deterministic)
 }
-func (m *ClientRequestPaymentResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ClientRequestPaymentResponse.Merge(m, src)
+func (m *ClientResetServerIdentityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientResetServerIdentityRequest.Merge(m, src)
 }
-func (m *ClientRequestPaymentResponse) XXX_Size() int {
-	return xxx_messageInfo_ClientRequestPaymentResponse.Size(m)
+func (m *ClientResetServerIdentityRequest) XXX_Size() int {
+	return xxx_messageInfo_ClientResetServerIdentityRequest.Size(m)
 }
-func (m *ClientRequestPaymentResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ClientRequestPaymentResponse.DiscardUnknown(m)
+func (m *ClientResetServerIdentityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientResetServerIdentityRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ClientRequestPaymentResponse proto.InternalMessageInfo
+var xxx_messageInfo_ClientResetServerIdentityRequest proto.InternalMessageInfo
 
-type ClientSubscribeContractsRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type ClientResetServerIdentityResponse struct {
+	// the identity that was forgotten, not set if none was pinned
+	Previous             *ServerIdentity `protobuf:"bytes,1,opt,name=previous,proto3" json:"previous,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *ClientSubscribeContractsRequest) Reset()         { *m = ClientSubscribeContractsRequest{} }
-func (m *ClientSubscribeContractsRequest) String() string { return proto.CompactTextString(m) }
-func (*ClientSubscribeContractsRequest) ProtoMessage()    {}
-func (*ClientSubscribeContractsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_014de31d7ac8c57c, []int{13}
+func (m *ClientResetServerIdentityResponse) Reset()         { *m = ClientResetServerIdentityResponse{} }
+func (m *ClientResetServerIdentityResponse) String() string { return proto.CompactTextString(m) }
+func (*ClientResetServerIdentityResponse) ProtoMessage()    {}
+func (*ClientResetServerIdentityResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{37}
 }
 
-func (m *ClientSubscribeContractsRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ClientSubscribeContractsRequest.Unmarshal(m, b)
+func (m *ClientResetServerIdentityResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientResetServerIdentityResponse.Unmarshal(m, b)
 }
-func (m *ClientSubscribeContractsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ClientSubscribeContractsRequest.Marshal(b, m, deterministic)
+func (m *ClientResetServerIdentityResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientResetServerIdentityResponse.Marshal(b, m, deterministic)
 }
-func (m *ClientSubscribeContractsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ClientSubscribeContractsRequest.Merge(m, src)
+func (m *ClientResetServerIdentityResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientResetServerIdentityResponse.Merge(m, src)
 }
-func (m *ClientSubscribeContractsRequest) XXX_Size() int {
-	return xxx_messageInfo_ClientSubscribeContractsRequest.Size(m)
+func (m *ClientResetServerIdentityResponse) XXX_Size() int {
+	return xxx_messageInfo_ClientResetServerIdentityResponse.Size(m)
 }
-func (m *ClientSubscribeContractsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ClientSubscribeContractsRequest.DiscardUnknown(m)
+func (m *ClientResetServerIdentityResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientResetServerIdentityResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ClientSubscribeContractsRequest proto.InternalMessageInfo
+var xxx_messageInfo_ClientResetServerIdentityResponse proto.InternalMessageInfo
+
+func (m *ClientResetServerIdentityResponse) GetPrevious() *ServerIdentity {
+	if m != nil {
+		return m.Previous
+	}
+	return nil
+}
 
 func init() {
+	proto.RegisterEnum("larpc.ContractStatus", ContractStatus_name, ContractStatus_value)
+	proto.RegisterEnum("larpc.RebalanceState", RebalanceState_name, RebalanceState_value)
+	proto.RegisterEnum("larpc.ConnectionState", ConnectionState_name, ConnectionState_value)
+	proto.RegisterEnum("larpc.IdentitySource", IdentitySource_name, IdentitySource_value)
+	proto.RegisterEnum("larpc.ClientContractUpdate_UpdateType", ClientContractUpdate_UpdateType_name, ClientContractUpdate_UpdateType_value)
 	proto.RegisterType((*ClientContract)(nil), "larpc.ClientContract")
+	proto.RegisterType((*ContractStatusChange)(nil), "larpc.ContractStatusChange")
+	proto.RegisterType((*Rebalance)(nil), "larpc.Rebalance")
 	proto.RegisterType((*ClientCreateContractRequest)(nil), "larpc.ClientCreateContractRequest")
 	proto.RegisterType((*ClientCreateContractResponse)(nil), "larpc.ClientCreateContractResponse")
+	proto.RegisterType((*QuoteValidation)(nil), "larpc.QuoteValidation")
 	proto.RegisterType((*ClientOpenContractRequest)(nil), "larpc.ClientOpenContractRequest")
+	proto.RegisterType((*PaymentOptions)(nil), "larpc.PaymentOptions")
 	proto.RegisterType((*ClientOpenContractResponse)(nil), "larpc.ClientOpenContractResponse")
 	proto.RegisterType((*ClientCloseContractRequest)(nil), "larpc.ClientCloseContractRequest")
 	proto.RegisterType((*ClientCloseContractResponse)(nil), "larpc.ClientCloseContractResponse")
@@ -674,62 +2394,205 @@ func init() {
 	proto.RegisterType((*ClientRequestPaymentRequest)(nil), "larpc.ClientRequestPaymentRequest")
 	proto.RegisterType((*ClientRequestPaymentResponse)(nil), "larpc.ClientRequestPaymentResponse")
 	proto.RegisterType((*ClientSubscribeContractsRequest)(nil), "larpc.ClientSubscribeContractsRequest")
+	proto.RegisterType((*ClientContractUpdate)(nil), "larpc.ClientContractUpdate")
+	proto.RegisterType((*ClientListPaymentsRequest)(nil), "larpc.ClientListPaymentsRequest")
+	proto.RegisterType((*ClientListPaymentsResponse)(nil), "larpc.ClientListPaymentsResponse")
+	proto.RegisterType((*ClientSubscribePaymentsRequest)(nil), "larpc.ClientSubscribePaymentsRequest")
+	proto.RegisterType((*ClientGetConfigRequest)(nil), "larpc.ClientGetConfigRequest")
+	proto.RegisterType((*ClientGetConfigResponse)(nil), "larpc.ClientGetConfigResponse")
+	proto.RegisterType((*ConfigValue)(nil), "larpc.ConfigValue")
+	proto.RegisterType((*ClientGetStatusRequest)(nil), "larpc.ClientGetStatusRequest")
+	proto.RegisterType((*ClientGetStatusResponse)(nil), "larpc.ClientGetStatusResponse")
+	proto.RegisterType((*ConnectionStatus)(nil), "larpc.ConnectionStatus")
+	proto.RegisterType((*ClientGetInfoRequest)(nil), "larpc.ClientGetInfoRequest")
+	proto.RegisterType((*ClientGetInfoResponse)(nil), "larpc.ClientGetInfoResponse")
+	proto.RegisterType((*PriceInfo)(nil), "larpc.PriceInfo")
+	proto.RegisterType((*ClientListAssetsRequest)(nil), "larpc.ClientListAssetsRequest")
+	proto.RegisterType((*ClientListAssetsResponse)(nil), "larpc.ClientListAssetsResponse")
+	proto.RegisterType((*ServerIdentity)(nil), "larpc.ServerIdentity")
+	proto.RegisterType((*ClientGetServerIdentityRequest)(nil), "larpc.ClientGetServerIdentityRequest")
+	proto.RegisterType((*ClientGetServerIdentityResponse)(nil), "larpc.ClientGetServerIdentityResponse")
+	proto.RegisterType((*ClientResetServerIdentityRequest)(nil), "larpc.ClientResetServerIdentityRequest")
+	proto.RegisterType((*ClientResetServerIdentityResponse)(nil), "larpc.ClientResetServerIdentityResponse")
 }
 
 func init() { proto.RegisterFile("client.proto", fileDescriptor_014de31d7ac8c57c) }
 
 var fileDescriptor_014de31d7ac8c57c = []byte{
-	// 785 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xdd, 0x4e, 0x13, 0x41,
-	0x14, 0xce, 0xb6, 0xb4, 0xb4, 0xa7, 0x3f, 0xe8, 0x84, 0x9f, 0x75, 0x29, 0x5a, 0x06, 0x21, 0x95,
-	0xc4, 0x16, 0xc1, 0x98, 0xe8, 0x85, 0x09, 0xf6, 0x8a, 0x44, 0x03, 0x59, 0x0c, 0x89, 0x7a, 0xb1,
-	0x19, 0xb6, 0x13, 0xb2, 0x49, 0xd9, 0x1d, 0x66, 0xa6, 0xc4, 0xde, 0x7a, 0xe1, 0x03, 0xe8, 0x0b,
-	0xf8, 0x48, 0x26, 0x5e, 0xf8, 0x02, 0x3e, 0x88, 0xe9, 0xcc, 0xec, 0xb6, 0xbb, 0x6e, 0x1b, 0xe2,
-	0x5d, 0xe7, 0x9c, 0xef, 0xfc, 0x7d, 0xe7, 0x3b, 0xb0, 0x50, 0xf7, 0x87, 0x01, 0x0d, 0x65, 0x97,
-	0xf1, 0x48, 0x46, 0xa8, 0x34, 0x24, 0x9c, 0xf9, 0x4e, 0x5d, 0x50, 0x7e, 0x4b, 0xb9, 0x36, 0x3a,
-	0xad, 0xab, 0x28, 0xba, 0x1a, 0xd2, 0x1e, 0x61, 0x41, 0x8f, 0x84, 0x61, 0x24, 0x89, 0x0c, 0xa2,
-	0x50, 0x68, 0x2f, 0xfe, 0x59, 0x80, 0x66, 0x5f, 0xe5, 0xe8, 0x47, 0xa1, 0xe4, 0xc4, 0x97, 0x08,
-	0xc1, 0xd2, 0x68, 0x14, 0x0c, 0x6c, 0xab, 0x6d, 0x75, 0xaa, 0xae, 0xfa, 0x8d, 0x56, 0xa1, 0x44,
-	0x84, 0xa0, 0xd2, 0x2e, 0x28, 0xa3, 0x7e, 0xa0, 0x75, 0x28, 0x93, 0xeb, 0x68, 0x14, 0x4a, 0xbb,
-	0xd8, 0xb6, 0x3a, 0x96, 0x6b, 0x5e, 0x68, 0x1f, 0xee, 0xeb, 0x5f, 0x9e, 0x20, 0xd2, 0xbb, 0x26,
-	0xfc, 0x2a, 0x08, 0xed, 0x52, 0xdb, 0xea, 0x14, 0xdd, 0x15, 0xed, 0x38, 0x27, 0xf2, 0x9d, 0x32,
-	0xa3, 0x3d, 0x58, 0x99, 0xc1, 0x06, 0x61, 0x20, 0xed, 0xb2, 0x42, 0x36, 0x12, 0xe4, 0x49, 0x18,
-	0x48, 0xb4, 0x0b, 0x4d, 0x9d, 0xc8, 0x0b, 0xc2, 0xdb, 0x28, 0xf0, 0xa9, 0xbd, 0xac, 0x5a, 0x69,
-	0x68, 0xeb, 0x89, 0x36, 0xa2, 0x6d, 0xa8, 0x4f, 0x72, 0x24, 0xa0, 0x8a, 0x02, 0xd5, 0x26, 0xb6,
-	0x18, 0xf2, 0x12, 0x1a, 0xbe, 0x99, 0xd5, 0x93, 0x63, 0x46, 0xed, 0x6a, 0xdb, 0xea, 0x34, 0x0f,
-	0x57, 0xbb, 0x43, 0x32, 0xe0, 0xcc, 0xef, 0xc6, 0x44, 0xbc, 0x1f, 0x33, 0xea, 0xd6, 0xfd, 0x99,
-	0x17, 0xda, 0x81, 0x86, 0x49, 0x2c, 0x3c, 0x46, 0x82, 0x81, 0x0d, 0x6d, 0xab, 0x53, 0x71, 0xeb,
-	0xb1, 0xf1, 0x8c, 0x04, 0x03, 0xfc, 0xd5, 0x82, 0x4d, 0x43, 0x29, 0xa7, 0x44, 0xd2, 0x38, 0x9f,
-	0x4b, 0x6f, 0x46, 0x54, 0xc8, 0x29, 0x97, 0x56, 0x3e, 0x97, 0x85, 0x14, 0x97, 0xff, 0x74, 0x5b,
-	0xbc, 0x6b, 0xb7, 0xf8, 0x47, 0x01, 0x5a, 0xf9, 0x8d, 0x08, 0x16, 0x85, 0x82, 0xa2, 0x67, 0x50,
-	0x89, 0x03, 0x54, 0x33, 0xb5, 0xc3, 0xb5, 0xae, 0x92, 0x50, 0x37, 0x2d, 0x09, 0x37, 0x81, 0xa1,
-	0xe7, 0xb0, 0x4e, 0x3f, 0x33, 0xea, 0x4b, 0x3a, 0x30, 0x8b, 0xf5, 0x66, 0xda, 0x2e, 0xba, 0xab,
-	0xb1, 0x57, 0xaf, 0xf7, 0x58, 0x0f, 0x71, 0x00, 0x89, 0x5d, 0xad, 0xd8, 0x9b, 0x91, 0x4d, 0xd1,
-	0x45, 0xb1, 0x6f, 0xb2, 0x68, 0x13, 0xb1, 0x09, 0xd5, 0x68, 0xc4, 0x3d, 0xc6, 0x27, 0x4b, 0x5c,
-	0x52, 0x8c, 0x54, 0xa2, 0x11, 0x3f, 0xe3, 0x66, 0xc9, 0x5a, 0xe2, 0xc6, 0x5f, 0x52, 0xfe, 0x9a,
-	0xb6, 0x69, 0xc8, 0x2e, 0x34, 0x19, 0xe5, 0x3e, 0x0d, 0x13, 0xfd, 0x95, 0x15, 0xa8, 0x61, 0xac,
-	0xba, 0x3d, 0xdc, 0x83, 0x07, 0x7a, 0xd4, 0x53, 0x46, 0xc3, 0xec, 0xa2, 0x72, 0x0e, 0x01, 0x9f,
-	0x82, 0x93, 0x17, 0xf0, 0xdf, 0x84, 0xe2, 0x83, 0x38, 0x61, 0x7f, 0x18, 0x09, 0x7a, 0x97, 0x16,
-	0xb6, 0x12, 0x79, 0xa5, 0x23, 0x74, 0x0f, 0xb8, 0x15, 0x27, 0x7c, 0x1b, 0x88, 0xa4, 0xa0, 0x30,
-	0x09, 0xb1, 0x1b, 0x07, 0x67, 0xbc, 0x66, 0x80, 0x23, 0xa8, 0xc6, 0x9d, 0x09, 0xdb, 0x6a, 0x17,
-	0xe7, 0x4f, 0x30, 0xc5, 0xe1, 0x3e, 0x60, 0xed, 0x34, 0x45, 0xce, 0xc8, 0xf8, 0x7a, 0xfa, 0x8a,
-	0x47, 0xd9, 0x02, 0x98, 0x1e, 0xba, 0x1a, 0xa8, 0xe8, 0x56, 0x93, 0x1b, 0xc7, 0xaf, 0x61, 0x67,
-	0x61, 0x12, 0xd3, 0xe0, 0x06, 0x2c, 0x33, 0x32, 0xf6, 0x38, 0xbd, 0x31, 0x9c, 0x94, 0x19, 0x19,
-	0xbb, 0xf4, 0x06, 0xbf, 0x88, 0x07, 0xcb, 0x8d, 0x9f, 0x1f, 0xf7, 0x30, 0xbe, 0x91, 0x6c, 0x9c,
-	0xa1, 0x73, 0x1b, 0x1e, 0x69, 0xff, 0xf9, 0xe8, 0x52, 0xf8, 0x3c, 0xb8, 0xa4, 0x59, 0x4e, 0x0f,
-	0x7f, 0x97, 0xa0, 0x76, 0x3c, 0x39, 0x62, 0x0d, 0x44, 0x1f, 0xa0, 0x99, 0x3e, 0x38, 0x84, 0xd3,
-	0x1c, 0xe6, 0xfd, 0x59, 0x70, 0x76, 0x16, 0x62, 0xcc, 0xf8, 0xe7, 0x50, 0x9f, 0x15, 0x1e, 0x6a,
-	0xa7, 0x82, 0x72, 0x44, 0xec, 0x6c, 0x2f, 0x40, 0x98, 0xa4, 0x17, 0xd0, 0x48, 0x49, 0x09, 0xa5,
-	0x63, 0xf2, 0x84, 0xe9, 0xe0, 0x45, 0x10, 0x93, 0xf7, 0x9b, 0x05, 0x6b, 0xf9, 0xdb, 0x78, 0x92,
-	0x8a, 0x5e, 0x24, 0x1b, 0x67, 0xff, 0x2e, 0x50, 0xb3, 0x2b, 0xfc, 0xe5, 0xd7, 0x9f, 0xef, 0x85,
-	0x16, 0xde, 0xe8, 0x71, 0xed, 0xe9, 0x31, 0x0d, 0x34, 0xcf, 0x57, 0xd6, 0x3e, 0xba, 0x85, 0x66,
-	0x3a, 0x49, 0x66, 0x39, 0xb9, 0x15, 0x32, 0xcb, 0x99, 0x23, 0x95, 0x4d, 0x55, 0x7e, 0x0d, 0xdf,
-	0xcb, 0x96, 0x9f, 0xd4, 0xbd, 0x80, 0x46, 0xea, 0xe4, 0x32, 0x24, 0xe7, 0x1d, 0x6b, 0x86, 0xe4,
-	0xfc, 0x8b, 0xfd, 0x04, 0xf6, 0x54, 0x99, 0xa9, 0x13, 0x15, 0x68, 0x2f, 0x15, 0x3f, 0x57, 0xc0,
-	0x4e, 0xfe, 0x89, 0x1f, 0x58, 0x6f, 0x1e, 0x7f, 0xc4, 0x84, 0xfb, 0x24, 0xa4, 0x3e, 0x1f, 0x33,
-	0x19, 0xf5, 0x86, 0xa1, 0xfa, 0x67, 0x25, 0x9e, 0xea, 0xcf, 0x8e, 0x9e, 0x0a, 0xbb, 0x2c, 0xab,
-	0x4f, 0x89, 0xa3, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x81, 0xbb, 0xb8, 0x2f, 0x8d, 0x08, 0x00,
-	0x00,
+	// 2759 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x39, 0x4b, 0x6f, 0xe3, 0xd6,
+	0xd5, 0x43, 0xc9, 0x96, 0xa5, 0xa3, 0xa7, 0xef, 0xf8, 0xc1, 0x91, 0x27, 0xb6, 0x87, 0xc9, 0x4c,
+	0x1c, 0x67, 0x32, 0x4e, 0x26, 0xc1, 0x87, 0xef, 0x0b, 0xf0, 0x05, 0x50, 0x6c, 0xcd, 0x44, 0x81,
+	0xc7, 0x72, 0x28, 0x8f, 0xfb, 0x40, 0x51, 0xe2, 0x0e, 0x79, 0x6d, 0x13, 0x91, 0x48, 0x0e, 0x49,
+	0xb9, 0x76, 0x83, 0xa0, 0x40, 0x80, 0xae, 0x0b, 0xb4, 0x8b, 0x2e, 0xfa, 0x1b, 0xba, 0xe8, 0xa6,
+	0xe8, 0xb6, 0xbf, 0xa1, 0xcb, 0x6e, 0xbb, 0xec, 0xa6, 0xfd, 0x03, 0x2d, 0xee, 0xbd, 0xe7, 0x52,
+	0x24, 0x4d, 0x39, 0xd3, 0xae, 0xac, 0x7b, 0xce, 0xb9, 0xe7, 0x7d, 0xcf, 0x83, 0x86, 0x86, 0x3d,
+	0x76, 0x99, 0x17, 0x3f, 0x09, 0x42, 0x3f, 0xf6, 0xc9, 0xe2, 0x98, 0x86, 0x81, 0xdd, 0x6d, 0x44,
+	0x2c, 0xbc, 0x64, 0xa1, 0x04, 0x76, 0xef, 0x9f, 0xfb, 0xfe, 0xf9, 0x98, 0xed, 0xd1, 0xc0, 0xdd,
+	0xa3, 0x9e, 0xe7, 0xc7, 0x34, 0x76, 0x7d, 0x2f, 0x92, 0x58, 0xe3, 0xcf, 0x15, 0x68, 0xed, 0x0b,
+	0x1e, 0xfb, 0xbe, 0x17, 0x87, 0xd4, 0x8e, 0x09, 0x81, 0x85, 0xe9, 0xd4, 0x75, 0x74, 0x6d, 0x5b,
+	0xdb, 0xa9, 0x99, 0xe2, 0x37, 0x59, 0x81, 0x45, 0x1a, 0x45, 0x2c, 0xd6, 0x4b, 0x02, 0x28, 0x0f,
+	0x64, 0x0d, 0x2a, 0x74, 0xe2, 0x4f, 0xbd, 0x58, 0x2f, 0x6f, 0x6b, 0x3b, 0x9a, 0x89, 0x27, 0xb2,
+	0x0b, 0xcb, 0xf2, 0x97, 0x15, 0xd1, 0xd8, 0x9a, 0xd0, 0xf0, 0xdc, 0xf5, 0xf4, 0xc5, 0x6d, 0x6d,
+	0xa7, 0x6c, 0xb6, 0x25, 0x62, 0x44, 0xe3, 0x17, 0x02, 0x4c, 0x1e, 0x41, 0x3b, 0x45, 0xeb, 0x7a,
+	0x6e, 0xac, 0x57, 0x04, 0x65, 0x33, 0xa1, 0x1c, 0x78, 0x6e, 0x4c, 0x1e, 0x42, 0x4b, 0x32, 0xb2,
+	0x5c, 0xef, 0xd2, 0x77, 0x6d, 0xa6, 0x2f, 0x09, 0x55, 0x9a, 0x12, 0x3a, 0x90, 0x40, 0xf2, 0x00,
+	0x1a, 0x9c, 0x47, 0x42, 0x54, 0x15, 0x44, 0x75, 0x0e, 0x53, 0x24, 0xff, 0x07, 0x4d, 0x1b, 0x6d,
+	0xb5, 0xe2, 0xeb, 0x80, 0xe9, 0xb5, 0x6d, 0x6d, 0xa7, 0xf5, 0x74, 0xe5, 0xc9, 0x98, 0x3a, 0x61,
+	0x60, 0x3f, 0x51, 0x8e, 0x38, 0xb9, 0x0e, 0x98, 0xd9, 0xb0, 0x53, 0x27, 0xf2, 0x16, 0xc0, 0x4c,
+	0x59, 0xbd, 0x2e, 0xf4, 0xac, 0x25, 0x7a, 0x72, 0x1d, 0xbd, 0xe9, 0xc4, 0x0a, 0xd9, 0x2b, 0x3a,
+	0xa6, 0x9e, 0xcd, 0x22, 0xbd, 0x21, 0x4d, 0xf1, 0xa6, 0x13, 0x33, 0x01, 0x92, 0xb7, 0xa1, 0x29,
+	0x23, 0x64, 0x05, 0xd3, 0x57, 0x5f, 0xb3, 0x6b, 0xbd, 0x29, 0x94, 0xc4, 0xb0, 0x1d, 0x0b, 0x18,
+	0xf9, 0x00, 0x2a, 0x51, 0x4c, 0xe3, 0x69, 0xa4, 0xb7, 0x84, 0x7a, 0xab, 0x4f, 0xc6, 0x34, 0xad,
+	0xdd, 0x48, 0x20, 0x4d, 0x24, 0x22, 0x9f, 0x43, 0x4b, 0xfe, 0xb2, 0x2e, 0xdc, 0x28, 0xf6, 0xc3,
+	0x6b, 0xbd, 0xbd, 0x5d, 0xde, 0xa9, 0x3f, 0xdd, 0x28, 0xbc, 0xb6, 0x7f, 0x41, 0xbd, 0x73, 0x66,
+	0x36, 0xe5, 0x95, 0x2f, 0xe4, 0x0d, 0xf2, 0x04, 0xee, 0xa2, 0x8b, 0x03, 0x7a, 0x3d, 0x61, 0x5e,
+	0x6c, 0x5d, 0xd0, 0xe8, 0x42, 0xef, 0x08, 0xed, 0x96, 0x25, 0xea, 0x58, 0x62, 0xbe, 0xa0, 0xd1,
+	0x05, 0xd9, 0x82, 0x7a, 0x42, 0xef, 0x3a, 0xfa, 0xf2, 0xb6, 0xb6, 0x53, 0x35, 0x41, 0xd1, 0xb9,
+	0x0e, 0xcf, 0x03, 0x11, 0x8c, 0x0c, 0x3b, 0x22, 0xd8, 0xb5, 0x39, 0x22, 0xcd, 0x6c, 0x03, 0x6a,
+	0x48, 0xeb, 0x3a, 0xfa, 0x5d, 0xc1, 0xaa, 0x2a, 0x69, 0x5c, 0x87, 0xf4, 0xa0, 0xf3, 0x7a, 0xea,
+	0xc7, 0xcc, 0xba, 0xa4, 0x63, 0xd7, 0x11, 0x09, 0xac, 0xaf, 0x6c, 0x6b, 0x3b, 0xf5, 0xa7, 0x6b,
+	0x68, 0xdf, 0x57, 0x1c, 0x7d, 0x9a, 0x60, 0xcd, 0xf6, 0xeb, 0x2c, 0x80, 0x87, 0x8e, 0x5d, 0x05,
+	0x6e, 0xc8, 0x22, 0x8b, 0xc6, 0xfa, 0xaa, 0x0c, 0x1d, 0x42, 0x7a, 0x22, 0x74, 0x18, 0x59, 0x87,
+	0xd9, 0xee, 0x84, 0x8e, 0xf5, 0x35, 0x99, 0x5e, 0x12, 0x7a, 0x20, 0x81, 0x5f, 0x2e, 0x54, 0xa1,
+	0x53, 0x37, 0x9b, 0x98, 0x5d, 0x91, 0xd0, 0xd6, 0xf8, 0x06, 0x56, 0x8a, 0xdc, 0x9b, 0x0a, 0xa1,
+	0xf6, 0x26, 0x21, 0xbc, 0x0f, 0xb5, 0xd8, 0x9d, 0xb0, 0x28, 0xa6, 0x93, 0x40, 0xbc, 0xb3, 0xb2,
+	0x39, 0x03, 0xf0, 0xb7, 0x16, 0x32, 0x1a, 0xf9, 0x9e, 0x78, 0x6b, 0x35, 0x13, 0x4f, 0xc6, 0x9f,
+	0x4a, 0x50, 0x4b, 0x72, 0x8b, 0xa7, 0x56, 0x92, 0xdb, 0xa9, 0x47, 0x9c, 0x64, 0xf1, 0x4b, 0xfe,
+	0x98, 0x5b, 0x50, 0x72, 0x1d, 0x94, 0x50, 0x72, 0x1d, 0x1e, 0x47, 0xf1, 0x9e, 0xad, 0x20, 0xe4,
+	0x4f, 0x46, 0xbe, 0x65, 0x10, 0xa0, 0x63, 0x0e, 0xc9, 0xa5, 0xfd, 0x42, 0x3e, 0xed, 0xd7, 0x61,
+	0x29, 0xa0, 0xd7, 0x56, 0xc8, 0x5e, 0x8b, 0x47, 0x5e, 0x33, 0x2b, 0x01, 0xbd, 0x36, 0xd9, 0x6b,
+	0xf2, 0x3e, 0x2c, 0x72, 0xdb, 0x98, 0x5e, 0xc9, 0xd8, 0x9f, 0xa8, 0xcb, 0x1d, 0xc0, 0x4c, 0x49,
+	0xc3, 0x85, 0xd8, 0x21, 0xa3, 0x31, 0x73, 0x78, 0x80, 0x96, 0xa4, 0x10, 0x84, 0xf4, 0x62, 0xfe,
+	0xb0, 0x6d, 0x7f, 0x12, 0x8c, 0x19, 0x12, 0x54, 0x05, 0x41, 0x3d, 0x81, 0xf5, 0x62, 0x9e, 0xbf,
+	0x29, 0x3b, 0x92, 0x40, 0xd6, 0x64, 0xfe, 0xce, 0xec, 0xc1, 0x60, 0x1a, 0xbf, 0xd7, 0x60, 0x03,
+	0x6b, 0x9f, 0x10, 0xa3, 0xe2, 0x62, 0xb2, 0xd7, 0x53, 0x16, 0xc5, 0xb3, 0xa2, 0xa7, 0x15, 0x17,
+	0xbd, 0x52, 0xa6, 0xe8, 0xdd, 0x28, 0x2b, 0xe5, 0x37, 0x2e, 0x2b, 0x37, 0x93, 0x6f, 0xa1, 0x20,
+	0xf9, 0x8c, 0x7f, 0x2c, 0xc0, 0xfd, 0x62, 0x7d, 0xa3, 0xc0, 0xf7, 0x22, 0x46, 0x3e, 0x82, 0xaa,
+	0xe2, 0x2b, 0x74, 0xae, 0xcf, 0x52, 0x2e, 0x53, 0xe2, 0xcd, 0x84, 0x8c, 0x7c, 0x02, 0x6b, 0xec,
+	0x2a, 0x60, 0x36, 0xf7, 0x2a, 0x3e, 0xe6, 0x94, 0x75, 0x65, 0x73, 0x45, 0x61, 0x65, 0xb9, 0xee,
+	0x49, 0x5b, 0x3f, 0x84, 0x04, 0x2e, 0x4a, 0xb6, 0x95, 0x6a, 0x03, 0x65, 0x93, 0x28, 0x1c, 0x2f,
+	0xdc, 0x78, 0x63, 0x03, 0x6a, 0xfe, 0x34, 0xc4, 0x0c, 0x5b, 0x10, 0x8e, 0xab, 0xfa, 0xd3, 0x50,
+	0xe6, 0xd7, 0x03, 0x68, 0xa8, 0x82, 0x28, 0xf0, 0x8b, 0x02, 0x5f, 0xc7, 0x7a, 0x28, 0x48, 0x1e,
+	0x42, 0x2b, 0x60, 0xa1, 0xcd, 0xab, 0x08, 0xf6, 0x93, 0x8a, 0x20, 0x6a, 0x22, 0x14, 0xbb, 0x49,
+	0x51, 0xa1, 0x58, 0xfa, 0xcf, 0x0a, 0xc5, 0x2e, 0x2c, 0x27, 0x9a, 0x26, 0xf1, 0x90, 0x6d, 0xa4,
+	0xad, 0x34, 0xc6, 0x88, 0x70, 0x3f, 0xa4, 0x15, 0xcf, 0xa5, 0x1c, 0x49, 0x19, 0xa0, 0x6e, 0x7c,
+	0x02, 0x6b, 0x59, 0x3b, 0x92, 0x3b, 0x20, 0xee, 0xac, 0x64, 0xec, 0x51, 0xb7, 0xfe, 0x1f, 0x36,
+	0x8a, 0xa3, 0x64, 0x4d, 0x66, 0x8d, 0x48, 0x2f, 0x0a, 0xd5, 0x8b, 0x88, 0xf2, 0xd4, 0xbc, 0x57,
+	0x14, 0x2e, 0x79, 0x59, 0xb6, 0xa8, 0xb5, 0x9b, 0x31, 0xe3, 0x57, 0x8d, 0xbf, 0x6b, 0xd0, 0xce,
+	0xb9, 0x8c, 0x74, 0xa1, 0x4a, 0x6d, 0x9b, 0x05, 0x31, 0x93, 0xf5, 0xa5, 0x6a, 0x26, 0x67, 0xa2,
+	0xc3, 0x92, 0x2c, 0x4c, 0x91, 0x5e, 0xda, 0x2e, 0xef, 0xd4, 0x4c, 0x75, 0x24, 0xff, 0x03, 0xeb,
+	0xca, 0x49, 0x97, 0xae, 0x60, 0x64, 0xa1, 0xad, 0x58, 0x71, 0x56, 0x03, 0xe9, 0x28, 0xc4, 0x1e,
+	0x4b, 0x24, 0xb7, 0x7d, 0x42, 0xaf, 0xac, 0x79, 0x77, 0x65, 0x2e, 0xe9, 0x13, 0x7a, 0x75, 0x5c,
+	0x78, 0xfd, 0x31, 0x10, 0x7e, 0x5d, 0x35, 0x2a, 0xbc, 0x25, 0x33, 0xac, 0x33, 0xa1, 0x57, 0xd2,
+	0x59, 0x48, 0x6d, 0xf8, 0x70, 0x4f, 0x3e, 0x95, 0x61, 0xc0, 0xbc, 0x7c, 0x3d, 0x28, 0x1a, 0x8c,
+	0x3e, 0x83, 0xb6, 0xea, 0x6e, 0x7e, 0x10, 0xbb, 0xd2, 0xee, 0xf4, 0xcb, 0xc3, 0x1e, 0x37, 0x94,
+	0x48, 0xb3, 0x15, 0x64, 0xce, 0xc6, 0x3f, 0x35, 0x68, 0x65, 0x49, 0xc8, 0x26, 0x6f, 0xab, 0x57,
+	0xd6, 0x19, 0x63, 0xa2, 0xdc, 0x6a, 0xb2, 0x12, 0x4e, 0xe8, 0xd5, 0x33, 0xc6, 0x78, 0xb9, 0x7d,
+	0x04, 0x6d, 0x85, 0x57, 0xe6, 0xc8, 0x4a, 0xd4, 0x94, 0x34, 0xca, 0xf2, 0x77, 0xa1, 0xcd, 0xdb,
+	0x87, 0x3f, 0x8d, 0xad, 0x88, 0xd9, 0xbe, 0xe7, 0x44, 0xf8, 0x3e, 0x5b, 0x08, 0x1e, 0x49, 0x28,
+	0xd9, 0x81, 0x8e, 0x3f, 0x8d, 0xcf, 0x7d, 0xd7, 0x3b, 0xb7, 0xec, 0x0b, 0xea, 0x59, 0xae, 0x23,
+	0xdc, 0xba, 0x60, 0xb6, 0x14, 0x9c, 0x77, 0xb4, 0x81, 0x43, 0x0c, 0x68, 0x06, 0xae, 0x67, 0xc5,
+	0xbe, 0x25, 0x53, 0x5b, 0xf8, 0xb1, 0x6a, 0xd6, 0x03, 0xd7, 0x3b, 0xf1, 0x47, 0x02, 0xc4, 0x1f,
+	0x33, 0x57, 0x8f, 0xc6, 0x31, 0x9b, 0x04, 0x71, 0x24, 0xde, 0xe9, 0xa2, 0xc9, 0x4d, 0xea, 0x21,
+	0xc8, 0x18, 0x42, 0xb7, 0xc8, 0xcb, 0xff, 0x75, 0x15, 0x33, 0x3e, 0x54, 0x0c, 0xf7, 0xc7, 0x7e,
+	0xc4, 0xde, 0x20, 0x6e, 0xc6, 0x5b, 0xb0, 0x51, 0x78, 0x43, 0xea, 0x60, 0x3c, 0x57, 0x0c, 0x0f,
+	0xdd, 0x28, 0x11, 0x18, 0x29, 0x86, 0xef, 0x41, 0xc7, 0xf5, 0xec, 0xf1, 0xd4, 0x61, 0x96, 0xeb,
+	0x51, 0x3b, 0x76, 0x2f, 0x19, 0x3e, 0x84, 0x36, 0xc2, 0x07, 0x08, 0x36, 0x4c, 0xd8, 0x28, 0x64,
+	0x84, 0xb6, 0x7e, 0x0c, 0x35, 0x65, 0x04, 0x9f, 0x12, 0xca, 0xf3, 0x8d, 0x9d, 0xd1, 0x19, 0x3f,
+	0x00, 0x43, 0x22, 0x51, 0x1f, 0xcc, 0x1f, 0x3c, 0xe1, 0x9f, 0x5c, 0xd3, 0xd6, 0xf2, 0x4d, 0x5b,
+	0x39, 0xa5, 0x94, 0x72, 0xca, 0x67, 0xf0, 0xf6, 0xad, 0x8c, 0x51, 0xe9, 0x54, 0xbf, 0xd7, 0xd2,
+	0xfd, 0xde, 0xf8, 0x52, 0x19, 0x5b, 0x78, 0x7f, 0xee, 0xbd, 0x42, 0x5d, 0x36, 0x55, 0xaf, 0xcb,
+	0xf3, 0xc2, 0x08, 0x1d, 0xc2, 0x96, 0xc4, 0x8f, 0xa6, 0xaf, 0x22, 0x3b, 0x74, 0x5f, 0xb1, 0xdb,
+	0xc2, 0x14, 0x79, 0x34, 0x88, 0x2e, 0xfc, 0x38, 0x17, 0xa6, 0x11, 0x82, 0x8d, 0xdf, 0x96, 0x60,
+	0x25, 0xeb, 0xf0, 0x97, 0x81, 0x43, 0x63, 0x46, 0x3e, 0x85, 0x05, 0xd1, 0xcc, 0xe5, 0x04, 0xf7,
+	0xa8, 0x30, 0x36, 0x92, 0xf4, 0x89, 0xfc, 0x23, 0xda, 0xbb, 0xb8, 0x93, 0x49, 0xe4, 0xd2, 0x9b,
+	0x25, 0xf2, 0xaf, 0x34, 0x80, 0x19, 0x1f, 0xd2, 0x80, 0xea, 0xe8, 0xa8, 0x77, 0x3c, 0xfa, 0x62,
+	0x78, 0xd2, 0xb9, 0x43, 0xea, 0xb0, 0xb4, 0x6f, 0xf6, 0x7b, 0x27, 0xfd, 0x83, 0x8e, 0x46, 0x00,
+	0x2a, 0xc3, 0xe3, 0xfe, 0x51, 0xff, 0xa0, 0x53, 0x22, 0x2d, 0x00, 0xb3, 0xff, 0x79, 0xef, 0xb0,
+	0x77, 0xb4, 0xdf, 0x3f, 0xe8, 0x94, 0x39, 0x6e, 0xff, 0x70, 0x38, 0xea, 0x1f, 0x74, 0x16, 0xf8,
+	0x25, 0x4e, 0x37, 0x38, 0x7a, 0xde, 0x59, 0x14, 0x1c, 0x0e, 0x87, 0x23, 0x7e, 0xa8, 0x70, 0xaa,
+	0x67, 0xbd, 0xc1, 0x61, 0xff, 0xa0, 0xb3, 0xc4, 0x11, 0xfd, 0x1f, 0x1e, 0x0f, 0xcc, 0xfe, 0x41,
+	0xa7, 0xca, 0x0f, 0x66, 0xff, 0xc5, 0xf0, 0xb4, 0x7f, 0xd0, 0xa9, 0x19, 0x3f, 0x81, 0x7b, 0xb3,
+	0x04, 0xc6, 0x20, 0x44, 0xb7, 0x55, 0xc4, 0xf7, 0x61, 0x19, 0xbd, 0x6b, 0x4d, 0xbd, 0x88, 0xc5,
+	0xf1, 0x98, 0xc9, 0xc8, 0x56, 0x4d, 0x15, 0x8e, 0x97, 0x0a, 0x6e, 0x0c, 0xa0, 0x5b, 0xc4, 0x1d,
+	0x13, 0xed, 0x7d, 0xa8, 0x62, 0xb9, 0x54, 0x8f, 0xa3, 0xad, 0xa6, 0x29, 0x95, 0x0e, 0x09, 0x81,
+	0xb1, 0x0d, 0x9b, 0xb9, 0x84, 0xc8, 0x69, 0x6b, 0xe8, 0xb0, 0x26, 0x29, 0x9e, 0x33, 0xee, 0xfb,
+	0x33, 0xf7, 0x5c, 0x61, 0xce, 0x60, 0xfd, 0x06, 0x06, 0x75, 0xd8, 0x82, 0xba, 0x2d, 0x20, 0xd6,
+	0x99, 0x3b, 0x66, 0x68, 0x29, 0x48, 0xd0, 0x33, 0x77, 0xcc, 0xc8, 0x2e, 0x54, 0x2e, 0xe9, 0x78,
+	0xca, 0x64, 0xc3, 0xab, 0x3f, 0x25, 0xb3, 0x29, 0xff, 0xcc, 0x3d, 0x3f, 0xe5, 0x28, 0x13, 0x29,
+	0x8c, 0x21, 0xd4, 0x53, 0x60, 0xee, 0x3e, 0x8f, 0x4e, 0x14, 0x53, 0xf1, 0x9b, 0x0f, 0x9d, 0x82,
+	0x58, 0x6d, 0xda, 0xe2, 0xc0, 0x87, 0xce, 0xc8, 0x9f, 0x86, 0x38, 0x9d, 0xd7, 0x4c, 0x3c, 0x65,
+	0x4c, 0xc2, 0x75, 0x02, 0x4d, 0x9a, 0xc2, 0xfa, 0x0d, 0x0c, 0x9a, 0xb4, 0x07, 0x15, 0x2c, 0xdf,
+	0xb2, 0xbc, 0xae, 0xcf, 0x34, 0xf6, 0x98, 0xcd, 0x9b, 0x50, 0xb2, 0x99, 0x08, 0x32, 0xf2, 0x1e,
+	0x94, 0xc7, 0x9e, 0xa3, 0x97, 0x6e, 0xa7, 0xe6, 0x34, 0xc6, 0x1f, 0x35, 0xe8, 0xe4, 0x31, 0x7c,
+	0x28, 0xa0, 0x8e, 0x13, 0xb2, 0x28, 0x42, 0x53, 0xd5, 0x91, 0x3c, 0x56, 0x1b, 0x42, 0x49, 0xbc,
+	0xaf, 0xb5, 0x42, 0xde, 0xc9, 0x8a, 0xb0, 0x02, 0x8b, 0x91, 0xeb, 0xa1, 0x13, 0xca, 0xa6, 0x3c,
+	0xf0, 0xd1, 0x70, 0x4c, 0xa3, 0xd8, 0xb2, 0xe5, 0x25, 0xe6, 0xe0, 0x86, 0xd2, 0xe4, 0xd0, 0x7d,
+	0x05, 0xe4, 0xf5, 0x50, 0x90, 0xb1, 0x30, 0xf4, 0x43, 0x5c, 0x54, 0x6a, 0x1c, 0xd2, 0xe7, 0x00,
+	0x63, 0x4d, 0x15, 0x80, 0xe7, 0x2c, 0x1e, 0x78, 0x67, 0xbe, 0xf2, 0xe3, 0xbf, 0x4a, 0xb0, 0x9a,
+	0x43, 0xa0, 0x1b, 0x75, 0x58, 0xba, 0x64, 0x61, 0xc4, 0x47, 0x4c, 0xb4, 0x0a, 0x8f, 0x1c, 0xe3,
+	0xb1, 0xf8, 0x67, 0x7e, 0xf8, 0x35, 0x46, 0x51, 0x1d, 0xb9, 0xae, 0x38, 0x30, 0x2a, 0x87, 0xc8,
+	0x78, 0xe2, 0x07, 0x81, 0x1e, 0xba, 0x65, 0x03, 0x6a, 0x63, 0xcf, 0xb1, 0xe8, 0xd8, 0xa5, 0x11,
+	0xee, 0x02, 0xd5, 0xb1, 0xe7, 0xf4, 0xf8, 0x59, 0x18, 0xe2, 0x39, 0xea, 0xdb, 0x81, 0x32, 0xc4,
+	0x73, 0xf0, 0xc3, 0xc1, 0x16, 0xd4, 0x39, 0x5a, 0xa9, 0x56, 0x91, 0x09, 0x3b, 0xf6, 0x9c, 0x53,
+	0xd4, 0xee, 0x31, 0x10, 0xfe, 0x95, 0xc2, 0x0f, 0x98, 0x67, 0xcd, 0x9a, 0x8f, 0x5c, 0xb8, 0x3a,
+	0xde, 0x74, 0x92, 0x6e, 0xca, 0x62, 0x38, 0x88, 0xfd, 0x98, 0x8e, 0xd5, 0x04, 0x15, 0x25, 0xbb,
+	0x57, 0x4b, 0xc0, 0xe5, 0xfc, 0xc4, 0x3b, 0xca, 0x63, 0x20, 0x92, 0x32, 0x59, 0x83, 0x38, 0x6d,
+	0x4d, 0xf2, 0x15, 0x98, 0x64, 0xf1, 0xa5, 0x31, 0xd9, 0x81, 0x8a, 0x18, 0xe9, 0x22, 0x1d, 0xc4,
+	0xb3, 0xe9, 0xa8, 0x79, 0x89, 0x03, 0x85, 0x9f, 0x11, 0x6f, 0xfc, 0x41, 0x83, 0x5a, 0x02, 0x9d,
+	0xb3, 0x94, 0x65, 0x5e, 0x8d, 0xa6, 0x5e, 0x4d, 0x66, 0xa3, 0x2e, 0xe7, 0x37, 0x6a, 0xbe, 0xf6,
+	0x9e, 0xb3, 0x64, 0x36, 0x92, 0x49, 0x03, 0xf4, 0x9c, 0xa9, 0xb9, 0x68, 0x45, 0x24, 0xe7, 0x98,
+	0xe1, 0x94, 0x23, 0x0f, 0x7c, 0xc5, 0x16, 0xdc, 0x93, 0xc1, 0x5d, 0x7a, 0xb8, 0x21, 0x80, 0x6a,
+	0x55, 0xfb, 0x18, 0xd6, 0x67, 0x75, 0xad, 0xc7, 0x55, 0x4c, 0x6a, 0xa6, 0x98, 0x90, 0xcf, 0x42,
+	0x16, 0x5d, 0x60, 0x33, 0x52, 0x47, 0xe3, 0xe7, 0xa0, 0xdf, 0xbc, 0x84, 0xc9, 0xc6, 0xb7, 0x4e,
+	0x01, 0x11, 0x85, 0xb0, 0x66, 0xe2, 0x89, 0x0f, 0x79, 0x53, 0x4f, 0x38, 0xca, 0xb1, 0x90, 0x40,
+	0xce, 0xdd, 0x2d, 0x05, 0x96, 0x8c, 0x78, 0xd6, 0x9c, 0xb1, 0xd8, 0xbe, 0x90, 0xdb, 0x33, 0x3a,
+	0x03, 0x21, 0xbd, 0xd8, 0xf8, 0x9d, 0x06, 0x2d, 0x39, 0xc0, 0x0d, 0x1c, 0xe6, 0xc5, 0x6e, 0x7c,
+	0xcd, 0x45, 0x62, 0x8e, 0xa9, 0x6e, 0x3d, 0xfb, 0x32, 0x25, 0x6b, 0x51, 0x29, 0xb3, 0xd6, 0xab,
+	0x8b, 0x23, 0x81, 0x54, 0x25, 0x8a, 0xe7, 0x72, 0xe0, 0x7a, 0x5e, 0x5a, 0x6e, 0x55, 0x02, 0xe4,
+	0x67, 0x97, 0xdc, 0x7b, 0x58, 0x28, 0x78, 0x0f, 0xb3, 0xda, 0xce, 0x8b, 0x59, 0x46, 0x4b, 0xf5,
+	0x4c, 0x4f, 0x60, 0x6b, 0x2e, 0xc5, 0x6c, 0xae, 0x74, 0x11, 0x96, 0x9b, 0x2b, 0x73, 0x17, 0x12,
+	0x32, 0xc3, 0x80, 0x6d, 0x35, 0x84, 0x44, 0xf3, 0x24, 0x9f, 0xc2, 0x83, 0x5b, 0x68, 0x66, 0xb2,
+	0x83, 0x90, 0x5d, 0xba, 0xfe, 0x34, 0xfa, 0x1e, 0xd9, 0x8a, 0x6c, 0xf7, 0x0c, 0x5a, 0xd9, 0x0f,
+	0x45, 0xe9, 0xfe, 0x7f, 0x27, 0xdd, 0xd7, 0x35, 0x52, 0x85, 0x05, 0x7e, 0xe8, 0x94, 0xd2, 0x1d,
+	0x3e, 0x3b, 0x07, 0xcc, 0xba, 0xfd, 0x62, 0xba, 0xdb, 0x57, 0x76, 0x4f, 0xa1, 0x95, 0xfd, 0x20,
+	0x43, 0x56, 0x61, 0x39, 0x19, 0x27, 0xac, 0xe3, 0xfe, 0xd1, 0x01, 0xe7, 0x76, 0x87, 0xac, 0xc3,
+	0xdd, 0x19, 0x78, 0x7f, 0xf8, 0xe2, 0xf8, 0xb0, 0x2f, 0x47, 0x91, 0x15, 0xe8, 0xcc, 0x10, 0x28,
+	0xa4, 0xb4, 0xfb, 0x15, 0xb4, 0x73, 0x65, 0x9c, 0xcf, 0x29, 0xfb, 0xc3, 0xa3, 0xa3, 0xfe, 0xfe,
+	0x89, 0xe4, 0xd8, 0x84, 0x1a, 0x9e, 0x05, 0x9f, 0x0e, 0x34, 0x0e, 0x06, 0xa3, 0x19, 0xa4, 0xc4,
+	0x09, 0x8e, 0x86, 0x27, 0x96, 0xd9, 0xef, 0x1d, 0xfc, 0xa8, 0x53, 0xde, 0xfd, 0x5f, 0x68, 0x65,
+	0x93, 0x8c, 0xac, 0x01, 0x39, 0x31, 0x5f, 0x8e, 0x4e, 0xac, 0xe1, 0x91, 0xf5, 0x6c, 0x60, 0x8e,
+	0x4e, 0xac, 0x97, 0xa3, 0x7e, 0xe7, 0x0e, 0x4a, 0x7a, 0x36, 0x78, 0xfe, 0x92, 0x1b, 0xa9, 0x3d,
+	0xfd, 0x6b, 0x13, 0xea, 0xe2, 0x21, 0xc8, 0x50, 0x91, 0x08, 0x5a, 0xd9, 0x6f, 0x28, 0xc4, 0xc8,
+	0x8e, 0x66, 0x45, 0x1f, 0x84, 0xba, 0x6f, 0xdf, 0x4a, 0x83, 0x83, 0xa9, 0xfe, 0xdd, 0x5f, 0xfe,
+	0xf6, 0x9b, 0x12, 0xf9, 0x54, 0xdb, 0x35, 0x9a, 0x7b, 0x97, 0x1f, 0xed, 0x25, 0x25, 0x96, 0x5c,
+	0x43, 0x23, 0x5d, 0x5b, 0xc9, 0x76, 0x86, 0x5d, 0xc1, 0xc6, 0xd9, 0x7d, 0x70, 0x0b, 0x05, 0x8a,
+	0x7b, 0x47, 0x88, 0xdb, 0xe4, 0xe2, 0xee, 0x65, 0xc4, 0xed, 0x7d, 0xc3, 0xc7, 0xb1, 0x6f, 0xf7,
+	0x78, 0x9d, 0x27, 0xdf, 0x42, 0x33, 0xb3, 0xe8, 0x90, 0x2c, 0xe7, 0xa2, 0xb5, 0xa9, 0x6b, 0xdc,
+	0x46, 0x82, 0xd2, 0x1f, 0x0a, 0xe9, 0x5b, 0x5c, 0x7a, 0xb7, 0x50, 0xba, 0xcd, 0xaf, 0x91, 0x5f,
+	0x6b, 0xb0, 0x5a, 0xbc, 0x13, 0xbc, 0x97, 0x11, 0x72, 0xdb, 0x42, 0xd3, 0xdd, 0x7d, 0x13, 0x52,
+	0xd4, 0xcb, 0x10, 0x7a, 0xdd, 0xe7, 0x7a, 0xad, 0xef, 0x85, 0x12, 0xb9, 0x87, 0xa3, 0x22, 0x1e,
+	0xc9, 0x25, 0x4f, 0xfc, 0x34, 0x93, 0x5c, 0x0e, 0x14, 0x4a, 0xc8, 0xe5, 0xc0, 0x9c, 0xe5, 0x64,
+	0x43, 0x88, 0x5f, 0xe5, 0xe2, 0x3b, 0x79, 0xf1, 0x64, 0x02, 0xcd, 0xcc, 0x32, 0x98, 0x8b, 0x45,
+	0xd1, 0xc6, 0xd9, 0x35, 0x6e, 0x23, 0x41, 0xa1, 0xab, 0x42, 0x68, 0x9b, 0xe4, 0xb2, 0xee, 0x3b,
+	0x0d, 0xf4, 0xd9, 0x8e, 0x94, 0x59, 0x3c, 0x22, 0x92, 0x5d, 0x68, 0xe6, 0xae, 0x52, 0xdd, 0x8d,
+	0x5b, 0x16, 0x1f, 0x63, 0x4b, 0x08, 0xbe, 0x47, 0xd6, 0xb3, 0x19, 0x10, 0x29, 0x6e, 0x1f, 0x6a,
+	0xc4, 0x85, 0x46, 0x7a, 0xc2, 0xcf, 0xa5, 0x7e, 0xc1, 0x6a, 0xd1, 0x7d, 0x70, 0x0b, 0x05, 0x1a,
+	0xbc, 0x22, 0xe4, 0xb6, 0x48, 0x83, 0xcb, 0x55, 0x7b, 0x00, 0x99, 0xc0, 0xf2, 0x8d, 0x0d, 0x80,
+	0x3c, 0x2c, 0xb6, 0x33, 0x2f, 0x34, 0xbf, 0x5e, 0x18, 0x9b, 0x42, 0x84, 0x4e, 0xd6, 0xd2, 0x22,
+	0x32, 0x96, 0xfd, 0x14, 0x6a, 0xc9, 0xd2, 0x40, 0xde, 0xca, 0x88, 0xc9, 0xaf, 0x19, 0xdd, 0xcd,
+	0x79, 0x68, 0x34, 0x88, 0x08, 0x69, 0x0d, 0x02, 0xe8, 0x48, 0xce, 0x92, 0x02, 0xcc, 0xc6, 0x01,
+	0xb2, 0x79, 0xc3, 0x2b, 0x99, 0xe1, 0xa2, 0xbb, 0x35, 0x17, 0x5f, 0x24, 0x02, 0x67, 0x88, 0x53,
+	0x58, 0xc2, 0xd9, 0x96, 0x6c, 0xe4, 0x35, 0x4c, 0x8d, 0xc2, 0xdd, 0xfb, 0xc5, 0x48, 0xe4, 0xdc,
+	0x11, 0x9c, 0x81, 0x54, 0x39, 0x67, 0x97, 0x33, 0x93, 0xae, 0xc1, 0xe6, 0x75, 0xc3, 0x35, 0x99,
+	0x75, 0xa5, 0xbb, 0x39, 0x0f, 0x5d, 0xa4, 0x37, 0xfe, 0xc3, 0xe4, 0x17, 0xb0, 0x7c, 0xa3, 0xdb,
+	0xe7, 0x22, 0x3d, 0x6f, 0x5e, 0xe8, 0x3e, 0xfa, 0x3e, 0xb2, 0xec, 0x4b, 0x26, 0x77, 0x85, 0x5c,
+	0x41, 0xb3, 0xa7, 0xc6, 0x03, 0xf2, 0x4b, 0x0d, 0xee, 0x16, 0x74, 0x7d, 0xf2, 0x6e, 0xae, 0x46,
+	0xcc, 0x9b, 0x1d, 0xba, 0x3b, 0xdf, 0x4f, 0x98, 0xd5, 0x63, 0xb7, 0x48, 0x8f, 0xcf, 0xdf, 0xf9,
+	0xb1, 0x41, 0x43, 0x9b, 0x7a, 0xcc, 0x0e, 0xaf, 0x83, 0xd8, 0xdf, 0x1b, 0x7b, 0x32, 0xb2, 0x1f,
+	0xc8, 0xff, 0x0e, 0xef, 0x09, 0x21, 0xaf, 0x2a, 0xe2, 0x3f, 0xbe, 0x1f, 0xff, 0x7b, 0x00, 0x53,
+	0xfd, 0xc1, 0x4c, 0x34, 0x1e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -756,8 +2619,29 @@ type AssetClientClient interface {
 	RequestPayment(ctx context.Context, in *ClientRequestPaymentRequest, opts ...grpc.CallOption) (*ClientRequestPaymentResponse, error)
 	// ListContracts lists all contracts in the database
 	ListContracts(ctx context.Context, in *ClientListContractsRequest, opts ...grpc.CallOption) (*ClientListContractsResponse, error)
-	// SubscribeContracts returns a stream notified of all new and closed contracts
+	// SubscribeContracts returns a stream notified of all created, opened,
+	// rebalanced and closed contracts
 	SubscribeClientContracts(ctx context.Context, in *ClientSubscribeContractsRequest, opts ...grpc.CallOption) (AssetClient_SubscribeClientContractsClient, error)
+	// ListPayments lists all payments made and received for our contracts
+	ListPayments(ctx context.Context, in *ClientListPaymentsRequest, opts ...grpc.CallOption) (*ClientListPaymentsResponse, error)
+	// SubscribePayments returns a stream notified of all new and settled payments
+	SubscribePayments(ctx context.Context, in *ClientSubscribePaymentsRequest, opts ...grpc.CallOption) (AssetClient_SubscribePaymentsClient, error)
+	// GetConfig returns the effective configuration of the daemon, with
+	// secrets redacted
+	GetConfig(ctx context.Context, in *ClientGetConfigRequest, opts ...grpc.CallOption) (*ClientGetConfigResponse, error)
+	// ListAssets lists the assets contracts can be created in, which are
+	// the assets supported by the server that we have a price for
+	ListAssets(ctx context.Context, in *ClientListAssetsRequest, opts ...grpc.CallOption) (*ClientListAssetsResponse, error)
+	// GetInfo returns an overview of the daemon, its contracts and prices
+	GetInfo(ctx context.Context, in *ClientGetInfoRequest, opts ...grpc.CallOption) (*ClientGetInfoResponse, error)
+	// GetStatus returns the state of the connections of the daemon
+	GetStatus(ctx context.Context, in *ClientGetStatusRequest, opts ...grpc.CallOption) (*ClientGetStatusResponse, error)
+	// GetServerIdentity returns the lightning node of the asset server we
+	// have pinned. We only pay invoices created by this node.
+	GetServerIdentity(ctx context.Context, in *ClientGetServerIdentityRequest, opts ...grpc.CallOption) (*ClientGetServerIdentityResponse, error)
+	// ResetServerIdentity forgets the pinned node of the asset server, the
+	// node of the next invoice the server sends us is pinned instead
+	ResetServerIdentity(ctx context.Context, in *ClientResetServerIdentityRequest, opts ...grpc.CallOption) (*ClientResetServerIdentityResponse, error)
 }
 
 type assetClientClient struct {
@@ -838,7 +2722,7 @@ func (c *assetClientClient) SubscribeClientContracts(ctx context.Context, in *Cl
 }
 
 type AssetClient_SubscribeClientContractsClient interface {
-	Recv() (*ClientContract, error)
+	Recv() (*ClientContractUpdate, error)
 	grpc.ClientStream
 }
 
@@ -846,14 +2730,109 @@ type assetClientSubscribeClientContractsClient struct {
 	grpc.ClientStream
 }
 
-func (x *assetClientSubscribeClientContractsClient) Recv() (*ClientContract, error) {
-	m := new(ClientContract)
+func (x *assetClientSubscribeClientContractsClient) Recv() (*ClientContractUpdate, error) {
+	m := new(ClientContractUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *assetClientClient) ListPayments(ctx context.Context, in *ClientListPaymentsRequest, opts ...grpc.CallOption) (*ClientListPaymentsResponse, error) {
+	out := new(ClientListPaymentsResponse)
+	err := c.cc.Invoke(ctx, "/larpc.AssetClient/ListPayments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetClientClient) SubscribePayments(ctx context.Context, in *ClientSubscribePaymentsRequest, opts ...grpc.CallOption) (AssetClient_SubscribePaymentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AssetClient_serviceDesc.Streams[1], "/larpc.AssetClient/SubscribePayments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &assetClientSubscribePaymentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AssetClient_SubscribePaymentsClient interface {
+	Recv() (*Payment, error)
+	grpc.ClientStream
+}
+
+type assetClientSubscribePaymentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *assetClientSubscribePaymentsClient) Recv() (*Payment, error) {
+	m := new(Payment)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
+func (c *assetClientClient) GetConfig(ctx context.Context, in *ClientGetConfigRequest, opts ...grpc.CallOption) (*ClientGetConfigResponse, error) {
+	out := new(ClientGetConfigResponse)
+	err := c.cc.Invoke(ctx, "/larpc.AssetClient/GetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetClientClient) ListAssets(ctx context.Context, in *ClientListAssetsRequest, opts ...grpc.CallOption) (*ClientListAssetsResponse, error) {
+	out := new(ClientListAssetsResponse)
+	err := c.cc.Invoke(ctx, "/larpc.AssetClient/ListAssets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetClientClient) GetInfo(ctx context.Context, in *ClientGetInfoRequest, opts ...grpc.CallOption) (*ClientGetInfoResponse, error) {
+	out := new(ClientGetInfoResponse)
+	err := c.cc.Invoke(ctx, "/larpc.AssetClient/GetInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetClientClient) GetStatus(ctx context.Context, in *ClientGetStatusRequest, opts ...grpc.CallOption) (*ClientGetStatusResponse, error) {
+	out := new(ClientGetStatusResponse)
+	err := c.cc.Invoke(ctx, "/larpc.AssetClient/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetClientClient) GetServerIdentity(ctx context.Context, in *ClientGetServerIdentityRequest, opts ...grpc.CallOption) (*ClientGetServerIdentityResponse, error) {
+	out := new(ClientGetServerIdentityResponse)
+	err := c.cc.Invoke(ctx, "/larpc.AssetClient/GetServerIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetClientClient) ResetServerIdentity(ctx context.Context, in *ClientResetServerIdentityRequest, opts ...grpc.CallOption) (*ClientResetServerIdentityResponse, error) {
+	out := new(ClientResetServerIdentityResponse)
+	err := c.cc.Invoke(ctx, "/larpc.AssetClient/ResetServerIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AssetClientServer is the server API for AssetClient service.
 type AssetClientServer interface {
 	// CreateContract is used to create a contract with the server, but not initiate it yet
@@ -868,8 +2847,29 @@ type AssetClientServer interface {
 	RequestPayment(context.Context, *ClientRequestPaymentRequest) (*ClientRequestPaymentResponse, error)
 	// ListContracts lists all contracts in the database
 	ListContracts(context.Context, *ClientListContractsRequest) (*ClientListContractsResponse, error)
-	// SubscribeContracts returns a stream notified of all new and closed contracts
+	// SubscribeContracts returns a stream notified of all created, opened,
+	// rebalanced and closed contracts
 	SubscribeClientContracts(*ClientSubscribeContractsRequest, AssetClient_SubscribeClientContractsServer) error
+	// ListPayments lists all payments made and received for our contracts
+	ListPayments(context.Context, *ClientListPaymentsRequest) (*ClientListPaymentsResponse, error)
+	// SubscribePayments returns a stream notified of all new and settled payments
+	SubscribePayments(*ClientSubscribePaymentsRequest, AssetClient_SubscribePaymentsServer) error
+	// GetConfig returns the effective configuration of the daemon, with
+	// secrets redacted
+	GetConfig(context.Context, *ClientGetConfigRequest) (*ClientGetConfigResponse, error)
+	// ListAssets lists the assets contracts can be created in, which are
+	// the assets supported by the server that we have a price for
+	ListAssets(context.Context, *ClientListAssetsRequest) (*ClientListAssetsResponse, error)
+	// GetInfo returns an overview of the daemon, its contracts and prices
+	GetInfo(context.Context, *ClientGetInfoRequest) (*ClientGetInfoResponse, error)
+	// GetStatus returns the state of the connections of the daemon
+	GetStatus(context.Context, *ClientGetStatusRequest) (*ClientGetStatusResponse, error)
+	// GetServerIdentity returns the lightning node of the asset server we
+	// have pinned. We only pay invoices created by this node.
+	GetServerIdentity(context.Context, *ClientGetServerIdentityRequest) (*ClientGetServerIdentityResponse, error)
+	// ResetServerIdentity forgets the pinned node of the asset server, the
+	// node of the next invoice the server sends us is pinned instead
+	ResetServerIdentity(context.Context, *ClientResetServerIdentityRequest) (*ClientResetServerIdentityResponse, error)
 }
 
 // UnimplementedAssetClientServer can be embedded to have forward compatible implementations.
@@ -897,6 +2897,30 @@ func (*UnimplementedAssetClientServer) ListContracts(ctx context.Context, req *C
 func (*UnimplementedAssetClientServer) SubscribeClientContracts(req *ClientSubscribeContractsRequest, srv AssetClient_SubscribeClientContractsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeClientContracts not implemented")
 }
+func (*UnimplementedAssetClientServer) ListPayments(ctx context.Context, req *ClientListPaymentsRequest) (*ClientListPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPayments not implemented")
+}
+func (*UnimplementedAssetClientServer) SubscribePayments(req *ClientSubscribePaymentsRequest, srv AssetClient_SubscribePaymentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribePayments not implemented")
+}
+func (*UnimplementedAssetClientServer) GetConfig(ctx context.Context, req *ClientGetConfigRequest) (*ClientGetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (*UnimplementedAssetClientServer) ListAssets(ctx context.Context, req *ClientListAssetsRequest) (*ClientListAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAssets not implemented")
+}
+func (*UnimplementedAssetClientServer) GetInfo(ctx context.Context, req *ClientGetInfoRequest) (*ClientGetInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (*UnimplementedAssetClientServer) GetStatus(ctx context.Context, req *ClientGetStatusRequest) (*ClientGetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (*UnimplementedAssetClientServer) GetServerIdentity(ctx context.Context, req *ClientGetServerIdentityRequest) (*ClientGetServerIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServerIdentity not implemented")
+}
+func (*UnimplementedAssetClientServer) ResetServerIdentity(ctx context.Context, req *ClientResetServerIdentityRequest) (*ClientResetServerIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetServerIdentity not implemented")
+}
 
 func RegisterAssetClientServer(s *grpc.Server, srv AssetClientServer) {
 	s.RegisterService(&_AssetClient_serviceDesc, srv)
@@ -1019,7 +3043,7 @@ func _AssetClient_SubscribeClientContracts_Handler(srv interface{}, stream grpc.
 }
 
 type AssetClient_SubscribeClientContractsServer interface {
-	Send(*ClientContract) error
+	Send(*ClientContractUpdate) error
 	grpc.ServerStream
 }
 
@@ -1027,10 +3051,157 @@ type assetClientSubscribeClientContractsServer struct {
 	grpc.ServerStream
 }
 
-func (x *assetClientSubscribeClientContractsServer) Send(m *ClientContract) error {
+func (x *assetClientSubscribeClientContractsServer) Send(m *ClientContractUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AssetClient_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientListPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetClientServer).ListPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/larpc.AssetClient/ListPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetClientServer).ListPayments(ctx, req.(*ClientListPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetClient_SubscribePayments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ClientSubscribePaymentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AssetClientServer).SubscribePayments(m, &assetClientSubscribePaymentsServer{stream})
+}
+
+type AssetClient_SubscribePaymentsServer interface {
+	Send(*Payment) error
+	grpc.ServerStream
+}
+
+type assetClientSubscribePaymentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *assetClientSubscribePaymentsServer) Send(m *Payment) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _AssetClient_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientGetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetClientServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/larpc.AssetClient/GetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetClientServer).GetConfig(ctx, req.(*ClientGetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetClient_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientListAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetClientServer).ListAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/larpc.AssetClient/ListAssets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetClientServer).ListAssets(ctx, req.(*ClientListAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetClient_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientGetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetClientServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/larpc.AssetClient/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetClientServer).GetInfo(ctx, req.(*ClientGetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetClient_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientGetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetClientServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/larpc.AssetClient/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetClientServer).GetStatus(ctx, req.(*ClientGetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetClient_GetServerIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientGetServerIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetClientServer).GetServerIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/larpc.AssetClient/GetServerIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetClientServer).GetServerIdentity(ctx, req.(*ClientGetServerIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetClient_ResetServerIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientResetServerIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetClientServer).ResetServerIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/larpc.AssetClient/ResetServerIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetClientServer).ResetServerIdentity(ctx, req.(*ClientResetServerIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AssetClient_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "larpc.AssetClient",
 	HandlerType: (*AssetClientServer)(nil),
@@ -1059,6 +3230,34 @@ var _AssetClient_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListContracts",
 			Handler:    _AssetClient_ListContracts_Handler,
 		},
+		{
+			MethodName: "ListPayments",
+			Handler:    _AssetClient_ListPayments_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _AssetClient_GetConfig_Handler,
+		},
+		{
+			MethodName: "ListAssets",
+			Handler:    _AssetClient_ListAssets_Handler,
+		},
+		{
+			MethodName: "GetInfo",
+			Handler:    _AssetClient_GetInfo_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _AssetClient_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetServerIdentity",
+			Handler:    _AssetClient_GetServerIdentity_Handler,
+		},
+		{
+			MethodName: "ResetServerIdentity",
+			Handler:    _AssetClient_ResetServerIdentity_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1066,6 +3265,11 @@ var _AssetClient_serviceDesc = grpc.ServiceDesc{
 			Handler:       _AssetClient_SubscribeClientContracts_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribePayments",
+			Handler:       _AssetClient_SubscribePayments_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "client.proto",
 }
@@ -10,6 +10,19 @@ do
 	       -I$GOPATH/src/gitlab.com/arcanecrypto \
 	       --go_out=plugins=grpc,paths=source_relative:. \
 	       --grpc-gateway_out=logtostderr=true,paths=source_relative:. \
+	       --swagger_out=logtostderr=true:. \
 		${file}
 
-done
\ No newline at end of file
+done
+
+# Embed the swagger document of the client API, so lacd can serve it.
+{
+	echo "// Code generated by gen_protos.sh. DO NOT EDIT."
+	echo
+	echo "package larpc"
+	echo
+	echo "// ClientSwagger is the OpenAPI document of the AssetClient REST API"
+	echo "const ClientSwagger = \`"
+	cat client.swagger.json
+	echo "\`"
+} > client.swagger.go
@@ -25,6 +25,109 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type PaymentType int32
+
+const (
+	PaymentType_MARGIN    PaymentType = 0
+	PaymentType_INIT      PaymentType = 1
+	PaymentType_REBALANCE PaymentType = 2
+)
+
+var PaymentType_name = map[int32]string{
+	0: "MARGIN",
+	1: "INIT",
+	2: "REBALANCE",
+}
+
+var PaymentType_value = map[string]int32{
+	"MARGIN":    0,
+	"INIT":      1,
+	"REBALANCE": 2,
+}
+
+func (x PaymentType) String() string {
+	return proto.EnumName(PaymentType_name, int32(x))
+}
+
+func (PaymentType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{0}
+}
+
+type PaymentState int32
+
+const (
+	// the payment is not sent to lnd yet
+	PaymentState_PAYMENT_PENDING   PaymentState = 0
+	PaymentState_PAYMENT_IN_FLIGHT PaymentState = 1
+	PaymentState_PAYMENT_SUCCEEDED PaymentState = 2
+	PaymentState_PAYMENT_FAILED    PaymentState = 3
+)
+
+var PaymentState_name = map[int32]string{
+	0: "PAYMENT_PENDING",
+	1: "PAYMENT_IN_FLIGHT",
+	2: "PAYMENT_SUCCEEDED",
+	3: "PAYMENT_FAILED",
+}
+
+var PaymentState_value = map[string]int32{
+	"PAYMENT_PENDING":   0,
+	"PAYMENT_IN_FLIGHT": 1,
+	"PAYMENT_SUCCEEDED": 2,
+	"PAYMENT_FAILED":    3,
+}
+
+func (x PaymentState) String() string {
+	return proto.EnumName(PaymentState_name, int32(x))
+}
+
+func (PaymentState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{1}
+}
+
+type PaymentFailureReason int32
+
+const (
+	PaymentFailureReason_FAILURE_REASON_NONE PaymentFailureReason = 0
+	// lnd could not complete the payment before the payment timeout
+	PaymentFailureReason_FAILURE_REASON_TIMEOUT PaymentFailureReason = 1
+	// there is no route to the destination within the fee limit
+	PaymentFailureReason_FAILURE_REASON_NO_ROUTE PaymentFailureReason = 2
+	// we do not have enough outbound liquidity to make the payment
+	PaymentFailureReason_FAILURE_REASON_INSUFFICIENT_BALANCE PaymentFailureReason = 3
+	// the destination rejected the payment, ie because the invoice is
+	// unknown, already paid or expired
+	PaymentFailureReason_FAILURE_REASON_INCORRECT_PAYMENT_DETAILS PaymentFailureReason = 4
+	// the payment failed for another reason, see the error message
+	PaymentFailureReason_FAILURE_REASON_ERROR PaymentFailureReason = 5
+)
+
+var PaymentFailureReason_name = map[int32]string{
+	0: "FAILURE_REASON_NONE",
+	1: "FAILURE_REASON_TIMEOUT",
+	2: "FAILURE_REASON_NO_ROUTE",
+	3: "FAILURE_REASON_INSUFFICIENT_BALANCE",
+	4: "FAILURE_REASON_INCORRECT_PAYMENT_DETAILS",
+	5: "FAILURE_REASON_ERROR",
+}
+
+var PaymentFailureReason_value = map[string]int32{
+	"FAILURE_REASON_NONE":                      0,
+	"FAILURE_REASON_TIMEOUT":                   1,
+	"FAILURE_REASON_NO_ROUTE":                  2,
+	"FAILURE_REASON_INSUFFICIENT_BALANCE":      3,
+	"FAILURE_REASON_INCORRECT_PAYMENT_DETAILS": 4,
+	"FAILURE_REASON_ERROR":                     5,
+}
+
+func (x PaymentFailureReason) String() string {
+	return proto.EnumName(PaymentFailureReason_name, int32(x))
+}
+
+func (PaymentFailureReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{2}
+}
+
 type ContractType int32
 
 const (
@@ -47,26 +150,28 @@ func (x ContractType) String() string {
 }
 
 func (ContractType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{0}
+	return fileDescriptor_ad098daeda4239f7, []int{3}
 }
 
 // Contract is the type of our contract, used to marshal/unmarshal
 // and send between hosts
 type ServerContract struct {
-	Uuid                 string       `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Asset                string       `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
-	Amount               float64      `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
-	AmountSats           int64        `protobuf:"varint,4,opt,name=amount_sats,json=amountSats,proto3" json:"amount_sats,omitempty"`
-	ClientHost           string       `protobuf:"bytes,5,opt,name=client_host,json=clientHost,proto3" json:"client_host,omitempty"`
-	MarginPayReq         string       `protobuf:"bytes,6,opt,name=margin_pay_req,json=marginPayReq,proto3" json:"margin_pay_req,omitempty"`
-	InitiatingPayReq     string       `protobuf:"bytes,7,opt,name=initiating_pay_req,json=initiatingPayReq,proto3" json:"initiating_pay_req,omitempty"`
-	MarginPaid           bool         `protobuf:"varint,8,opt,name=margin_paid,json=marginPaid,proto3" json:"margin_paid,omitempty"`
-	InitiatingPaid       bool         `protobuf:"varint,9,opt,name=initiating_paid,json=initiatingPaid,proto3" json:"initiating_paid,omitempty"`
-	ContractType         ContractType `protobuf:"varint,10,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
-	NumUpdates           int64        `protobuf:"varint,11,opt,name=num_updates,json=numUpdates,proto3" json:"num_updates,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Uuid             string       `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Asset            string       `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount           float64      `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	AmountSats       int64        `protobuf:"varint,4,opt,name=amount_sats,json=amountSats,proto3" json:"amount_sats,omitempty"`
+	ClientHost       string       `protobuf:"bytes,5,opt,name=client_host,json=clientHost,proto3" json:"client_host,omitempty"`
+	MarginPayReq     string       `protobuf:"bytes,6,opt,name=margin_pay_req,json=marginPayReq,proto3" json:"margin_pay_req,omitempty"`
+	InitiatingPayReq string       `protobuf:"bytes,7,opt,name=initiating_pay_req,json=initiatingPayReq,proto3" json:"initiating_pay_req,omitempty"`
+	MarginPaid       bool         `protobuf:"varint,8,opt,name=margin_paid,json=marginPaid,proto3" json:"margin_paid,omitempty"`
+	InitiatingPaid   bool         `protobuf:"varint,9,opt,name=initiating_paid,json=initiatingPaid,proto3" json:"initiating_paid,omitempty"`
+	ContractType     ContractType `protobuf:"varint,10,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
+	NumUpdates       int64        `protobuf:"varint,11,opt,name=num_updates,json=numUpdates,proto3" json:"num_updates,omitempty"`
+	// the exact amount as a decimal string, amount is its nearest float
+	AmountDecimal        string   `protobuf:"bytes,12,opt,name=amount_decimal,json=amountDecimal,proto3" json:"amount_decimal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ServerContract) Reset()         { *m = ServerContract{} }
@@ -171,16 +276,35 @@ func (m *ServerContract) GetNumUpdates() int64 {
 	return 0
 }
 
+func (m *ServerContract) GetAmountDecimal() string {
+	if m != nil {
+		return m.AmountDecimal
+	}
+	return ""
+}
+
 // Payment is a payment type, used to marshal/unmarshal from the db
 type Payment struct {
 	ContractUuid   string `protobuf:"bytes,1,opt,name=contract_uuid,json=contractUuid,proto3" json:"contract_uuid,omitempty"`
 	AmountSat      int64  `protobuf:"varint,2,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
 	PaymentRequest string `protobuf:"bytes,3,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
 	// if true, this payment was outbound, ie paid by us
-	Outbound             bool     `protobuf:"varint,4,opt,name=outbound,proto3" json:"outbound,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Outbound    bool   `protobuf:"varint,4,opt,name=outbound,proto3" json:"outbound,omitempty"`
+	PaymentHash string `protobuf:"bytes,5,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	Preimage    string `protobuf:"bytes,6,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// the routing fee, only set for outbound payments
+	FeeSat    int64       `protobuf:"varint,7,opt,name=fee_sat,json=feeSat,proto3" json:"fee_sat,omitempty"`
+	Type      PaymentType `protobuf:"varint,8,opt,name=type,proto3,enum=ladrpc.PaymentType" json:"type,omitempty"`
+	Settled   bool        `protobuf:"varint,9,opt,name=settled,proto3" json:"settled,omitempty"`
+	CreatedAt int64       `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SettledAt int64       `protobuf:"varint,11,opt,name=settled_at,json=settledAt,proto3" json:"settled_at,omitempty"`
+	// how far lnd has come paying an outbound payment
+	State PaymentState `protobuf:"varint,12,opt,name=state,proto3,enum=ladrpc.PaymentState" json:"state,omitempty"`
+	// why lnd gave up paying an outbound payment, set if state is FAILED
+	FailureReason        PaymentFailureReason `protobuf:"varint,13,opt,name=failure_reason,json=failureReason,proto3,enum=ladrpc.PaymentFailureReason" json:"failure_reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *Payment) Reset()         { *m = Payment{} }
@@ -236,6 +360,126 @@ func (m *Payment) GetOutbound() bool {
 	return false
 }
 
+func (m *Payment) GetPaymentHash() string {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return ""
+}
+
+func (m *Payment) GetPreimage() string {
+	if m != nil {
+		return m.Preimage
+	}
+	return ""
+}
+
+func (m *Payment) GetFeeSat() int64 {
+	if m != nil {
+		return m.FeeSat
+	}
+	return 0
+}
+
+func (m *Payment) GetType() PaymentType {
+	if m != nil {
+		return m.Type
+	}
+	return PaymentType_MARGIN
+}
+
+func (m *Payment) GetSettled() bool {
+	if m != nil {
+		return m.Settled
+	}
+	return false
+}
+
+func (m *Payment) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Payment) GetSettledAt() int64 {
+	if m != nil {
+		return m.SettledAt
+	}
+	return 0
+}
+
+func (m *Payment) GetState() PaymentState {
+	if m != nil {
+		return m.State
+	}
+	return PaymentState_PAYMENT_PENDING
+}
+
+func (m *Payment) GetFailureReason() PaymentFailureReason {
+	if m != nil {
+		return m.FailureReason
+	}
+	return PaymentFailureReason_FAILURE_REASON_NONE
+}
+
+// PaymentError is attached to the status of rpcs that fail because a
+// payment failed, so callers can tell why
+type PaymentError struct {
+	PaymentHash          string               `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	Reason               PaymentFailureReason `protobuf:"varint,2,opt,name=reason,proto3,enum=ladrpc.PaymentFailureReason" json:"reason,omitempty"`
+	Message              string               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *PaymentError) Reset()         { *m = PaymentError{} }
+func (m *PaymentError) String() string { return proto.CompactTextString(m) }
+func (*PaymentError) ProtoMessage()    {}
+func (*PaymentError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{2}
+}
+
+func (m *PaymentError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PaymentError.Unmarshal(m, b)
+}
+func (m *PaymentError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PaymentError.Marshal(b, m, deterministic)
+}
+func (m *PaymentError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PaymentError.Merge(m, src)
+}
+func (m *PaymentError) XXX_Size() int {
+	return xxx_messageInfo_PaymentError.Size(m)
+}
+func (m *PaymentError) XXX_DiscardUnknown() {
+	xxx_messageInfo_PaymentError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PaymentError proto.InternalMessageInfo
+
+func (m *PaymentError) GetPaymentHash() string {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return ""
+}
+
+func (m *PaymentError) GetReason() PaymentFailureReason {
+	if m != nil {
+		return m.Reason
+	}
+	return PaymentFailureReason_FAILURE_REASON_NONE
+}
+
+func (m *PaymentError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 type Quote struct {
 	PercentMargin        float64  `protobuf:"fixed64,1,opt,name=percent_margin,json=percentMargin,proto3" json:"percent_margin,omitempty"`
 	AmountSats           int64    `protobuf:"varint,2,opt,name=amount_sats,json=amountSats,proto3" json:"amount_sats,omitempty"`
@@ -249,7 +493,7 @@ func (m *Quote) Reset()         { *m = Quote{} }
 func (m *Quote) String() string { return proto.CompactTextString(m) }
 func (*Quote) ProtoMessage()    {}
 func (*Quote) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{2}
+	return fileDescriptor_ad098daeda4239f7, []int{3}
 }
 
 func (m *Quote) XXX_Unmarshal(b []byte) error {
@@ -292,8 +536,10 @@ func (m *Quote) GetAssetPrice() float64 {
 }
 
 type Price struct {
-	Asset                string   `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
-	Value                float64  `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Asset string  `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Value float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	// the exact value as a decimal string, value is its nearest float
+	ValueDecimal         string   `protobuf:"bytes,3,opt,name=value_decimal,json=valueDecimal,proto3" json:"value_decimal,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -303,7 +549,7 @@ func (m *Price) Reset()         { *m = Price{} }
 func (m *Price) String() string { return proto.CompactTextString(m) }
 func (*Price) ProtoMessage()    {}
 func (*Price) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{3}
+	return fileDescriptor_ad098daeda4239f7, []int{4}
 }
 
 func (m *Price) XXX_Unmarshal(b []byte) error {
@@ -338,23 +584,113 @@ func (m *Price) GetValue() float64 {
 	return 0
 }
 
+func (m *Price) GetValueDecimal() string {
+	if m != nil {
+		return m.ValueDecimal
+	}
+	return ""
+}
+
+// MessageSignature authenticates a request or response by the lnd node that
+// sent it. The signature is made with SignMessage of lnd over the fields of
+// the signature and the signed fields of the message, see SigningPayload in
+// larpc/signing.go.
+type MessageSignature struct {
+	NodePubkey string `protobuf:"bytes,1,opt,name=node_pubkey,json=nodePubkey,proto3" json:"node_pubkey,omitempty"`
+	// unix timestamp of when the message was signed
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// random hex string, never reused by the signer. A response carries the
+	// nonce of the request it answers.
+	Nonce string `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// the signature made by lnd
+	Signature            string   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageSignature) Reset()         { *m = MessageSignature{} }
+func (m *MessageSignature) String() string { return proto.CompactTextString(m) }
+func (*MessageSignature) ProtoMessage()    {}
+func (*MessageSignature) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{5}
+}
+
+func (m *MessageSignature) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageSignature.Unmarshal(m, b)
+}
+func (m *MessageSignature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageSignature.Marshal(b, m, deterministic)
+}
+func (m *MessageSignature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageSignature.Merge(m, src)
+}
+func (m *MessageSignature) XXX_Size() int {
+	return xxx_messageInfo_MessageSignature.Size(m)
+}
+func (m *MessageSignature) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageSignature.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageSignature proto.InternalMessageInfo
+
+func (m *MessageSignature) GetNodePubkey() string {
+	if m != nil {
+		return m.NodePubkey
+	}
+	return ""
+}
+
+func (m *MessageSignature) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *MessageSignature) GetNonce() string {
+	if m != nil {
+		return m.Nonce
+	}
+	return ""
+}
+
+func (m *MessageSignature) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
 // ServerNewContractRequest is used to initiate a new contract
 // with another host
 type ServerNewContractRequest struct {
-	Asset                string       `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
-	Amount               float64      `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
-	Host                 string       `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
-	ContractType         ContractType `protobuf:"varint,4,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Asset  string  `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// the address the server can reach the client at. If empty, the server
+	// reaches the client over its push channel instead.
+	Host         string       `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	ContractType ContractType `protobuf:"varint,4,opt,name=contract_type,json=contractType,proto3,enum=ladrpc.ContractType" json:"contract_type,omitempty"`
+	// the identity pubkey of the lnd node of the client, which its push
+	// channel is registered with
+	NodePubkey string `protobuf:"bytes,5,opt,name=node_pubkey,json=nodePubkey,proto3" json:"node_pubkey,omitempty"`
+	// signs asset, amount, host, contract_type, node_pubkey and
+	// amount_decimal by the node of the client, which the contract is bound
+	// to
+	Signature *MessageSignature `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	// the exact amount as a decimal string with at most the decimals of the
+	// asset. If set, it takes precedence over amount.
+	AmountDecimal        string   `protobuf:"bytes,7,opt,name=amount_decimal,json=amountDecimal,proto3" json:"amount_decimal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ServerNewContractRequest) Reset()         { *m = ServerNewContractRequest{} }
 func (m *ServerNewContractRequest) String() string { return proto.CompactTextString(m) }
 func (*ServerNewContractRequest) ProtoMessage()    {}
 func (*ServerNewContractRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{4}
+	return fileDescriptor_ad098daeda4239f7, []int{6}
 }
 
 func (m *ServerNewContractRequest) XXX_Unmarshal(b []byte) error {
@@ -403,13 +739,40 @@ func (m *ServerNewContractRequest) GetContractType() ContractType {
 	return ContractType_FUNDED
 }
 
+func (m *ServerNewContractRequest) GetNodePubkey() string {
+	if m != nil {
+		return m.NodePubkey
+	}
+	return ""
+}
+
+func (m *ServerNewContractRequest) GetSignature() *MessageSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *ServerNewContractRequest) GetAmountDecimal() string {
+	if m != nil {
+		return m.AmountDecimal
+	}
+	return ""
+}
+
 // If successful, the ServerNewContractResponse returns the created contract
 type ServerNewContractResponse struct {
-	Uuid                 string   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	MarginPayReq         string   `protobuf:"bytes,2,opt,name=margin_pay_req,json=marginPayReq,proto3" json:"margin_pay_req,omitempty"`
-	InitiatingPayReq     string   `protobuf:"bytes,3,opt,name=initiating_pay_req,json=initiatingPayReq,proto3" json:"initiating_pay_req,omitempty"`
-	PercentMargin        float64  `protobuf:"fixed64,4,opt,name=percent_margin,json=percentMargin,proto3" json:"percent_margin,omitempty"`
-	AssetPrice           float64  `protobuf:"fixed64,5,opt,name=asset_price,json=assetPrice,proto3" json:"asset_price,omitempty"`
+	Uuid             string  `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	MarginPayReq     string  `protobuf:"bytes,2,opt,name=margin_pay_req,json=marginPayReq,proto3" json:"margin_pay_req,omitempty"`
+	InitiatingPayReq string  `protobuf:"bytes,3,opt,name=initiating_pay_req,json=initiatingPayReq,proto3" json:"initiating_pay_req,omitempty"`
+	PercentMargin    float64 `protobuf:"fixed64,4,opt,name=percent_margin,json=percentMargin,proto3" json:"percent_margin,omitempty"`
+	AssetPrice       float64 `protobuf:"fixed64,5,opt,name=asset_price,json=assetPrice,proto3" json:"asset_price,omitempty"`
+	// signs all other fields by the node of the server
+	Signature *MessageSignature `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	// the exact numbers as decimal strings, the doubles are their nearest
+	// floats. Older servers only set the doubles.
+	PercentMarginDecimal string   `protobuf:"bytes,7,opt,name=percent_margin_decimal,json=percentMarginDecimal,proto3" json:"percent_margin_decimal,omitempty"`
+	AssetPriceDecimal    string   `protobuf:"bytes,8,opt,name=asset_price_decimal,json=assetPriceDecimal,proto3" json:"asset_price_decimal,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -419,7 +782,7 @@ func (m *ServerNewContractResponse) Reset()         { *m = ServerNewContractResp
 func (m *ServerNewContractResponse) String() string { return proto.CompactTextString(m) }
 func (*ServerNewContractResponse) ProtoMessage()    {}
 func (*ServerNewContractResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{5}
+	return fileDescriptor_ad098daeda4239f7, []int{7}
 }
 
 func (m *ServerNewContractResponse) XXX_Unmarshal(b []byte) error {
@@ -475,18 +838,41 @@ func (m *ServerNewContractResponse) GetAssetPrice() float64 {
 	return 0
 }
 
+func (m *ServerNewContractResponse) GetSignature() *MessageSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *ServerNewContractResponse) GetPercentMarginDecimal() string {
+	if m != nil {
+		return m.PercentMarginDecimal
+	}
+	return ""
+}
+
+func (m *ServerNewContractResponse) GetAssetPriceDecimal() string {
+	if m != nil {
+		return m.AssetPriceDecimal
+	}
+	return ""
+}
+
 type ServerCloseContractRequest struct {
-	Uuid                 string   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// signs uuid by the node the contract is bound to
+	Signature            *MessageSignature `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *ServerCloseContractRequest) Reset()         { *m = ServerCloseContractRequest{} }
 func (m *ServerCloseContractRequest) String() string { return proto.CompactTextString(m) }
 func (*ServerCloseContractRequest) ProtoMessage()    {}
 func (*ServerCloseContractRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{6}
+	return fileDescriptor_ad098daeda4239f7, []int{8}
 }
 
 func (m *ServerCloseContractRequest) XXX_Unmarshal(b []byte) error {
@@ -514,17 +900,26 @@ func (m *ServerCloseContractRequest) GetUuid() string {
 	return ""
 }
 
+func (m *ServerCloseContractRequest) GetSignature() *MessageSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 type ServerCloseContractResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// signs uuid of the request by the node of the server
+	Signature            *MessageSignature `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *ServerCloseContractResponse) Reset()         { *m = ServerCloseContractResponse{} }
 func (m *ServerCloseContractResponse) String() string { return proto.CompactTextString(m) }
 func (*ServerCloseContractResponse) ProtoMessage()    {}
 func (*ServerCloseContractResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{7}
+	return fileDescriptor_ad098daeda4239f7, []int{9}
 }
 
 func (m *ServerCloseContractResponse) XXX_Unmarshal(b []byte) error {
@@ -545,49 +940,189 @@ func (m *ServerCloseContractResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ServerCloseContractResponse proto.InternalMessageInfo
 
-type ServerListAssetsRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ServerCloseContractResponse) GetSignature() *MessageSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
 }
 
-func (m *ServerListAssetsRequest) Reset()         { *m = ServerListAssetsRequest{} }
-func (m *ServerListAssetsRequest) String() string { return proto.CompactTextString(m) }
-func (*ServerListAssetsRequest) ProtoMessage()    {}
-func (*ServerListAssetsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{8}
+type ServerRebalanceContractRequest struct {
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// the price the rebalance was calculated with
+	AssetPrice float64 `protobuf:"fixed64,2,opt,name=asset_price,json=assetPrice,proto3" json:"asset_price,omitempty"`
+	// positive if the server owes the client, negative if the client owes the server
+	AmountSat int64 `protobuf:"varint,3,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	// the invoice the server should pay, only set if the server owes the client
+	PayReq string `protobuf:"bytes,4,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
+	// the exact price as a decimal string, asset_price is its nearest float
+	AssetPriceDecimal string `protobuf:"bytes,5,opt,name=asset_price_decimal,json=assetPriceDecimal,proto3" json:"asset_price_decimal,omitempty"`
+	// signed by the node the contract is bound to
+	Signature            *MessageSignature `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func (m *ServerListAssetsRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ServerListAssetsRequest.Unmarshal(m, b)
+func (m *ServerRebalanceContractRequest) Reset()         { *m = ServerRebalanceContractRequest{} }
+func (m *ServerRebalanceContractRequest) String() string { return proto.CompactTextString(m) }
+func (*ServerRebalanceContractRequest) ProtoMessage()    {}
+func (*ServerRebalanceContractRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{10}
 }
-func (m *ServerListAssetsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ServerListAssetsRequest.Marshal(b, m, deterministic)
+
+func (m *ServerRebalanceContractRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerRebalanceContractRequest.Unmarshal(m, b)
 }
-func (m *ServerListAssetsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ServerListAssetsRequest.Merge(m, src)
+func (m *ServerRebalanceContractRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerRebalanceContractRequest.Marshal(b, m, deterministic)
 }
-func (m *ServerListAssetsRequest) XXX_Size() int {
-	return xxx_messageInfo_ServerListAssetsRequest.Size(m)
+func (m *ServerRebalanceContractRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerRebalanceContractRequest.Merge(m, src)
 }
-func (m *ServerListAssetsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ServerListAssetsRequest.DiscardUnknown(m)
+func (m *ServerRebalanceContractRequest) XXX_Size() int {
+	return xxx_messageInfo_ServerRebalanceContractRequest.Size(m)
+}
+func (m *ServerRebalanceContractRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerRebalanceContractRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ServerListAssetsRequest proto.InternalMessageInfo
+var xxx_messageInfo_ServerRebalanceContractRequest proto.InternalMessageInfo
 
-type ServerListAssetsResponse struct {
-	SupportedAssets      []string `protobuf:"bytes,1,rep,name=supported_assets,json=supportedAssets,proto3" json:"supported_assets,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ServerRebalanceContractRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
 }
 
-func (m *ServerListAssetsResponse) Reset()         { *m = ServerListAssetsResponse{} }
-func (m *ServerListAssetsResponse) String() string { return proto.CompactTextString(m) }
-func (*ServerListAssetsResponse) ProtoMessage()    {}
-func (*ServerListAssetsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{9}
+func (m *ServerRebalanceContractRequest) GetAssetPrice() float64 {
+	if m != nil {
+		return m.AssetPrice
+	}
+	return 0
+}
+
+func (m *ServerRebalanceContractRequest) GetAmountSat() int64 {
+	if m != nil {
+		return m.AmountSat
+	}
+	return 0
+}
+
+func (m *ServerRebalanceContractRequest) GetPayReq() string {
+	if m != nil {
+		return m.PayReq
+	}
+	return ""
+}
+
+func (m *ServerRebalanceContractRequest) GetAssetPriceDecimal() string {
+	if m != nil {
+		return m.AssetPriceDecimal
+	}
+	return ""
+}
+
+func (m *ServerRebalanceContractRequest) GetSignature() *MessageSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ServerRebalanceContractResponse struct {
+	// the invoice the client should pay, only set if the client owes the server
+	PayReq string `protobuf:"bytes,1,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
+	// signed by the node of the server, with the nonce of the request
+	Signature            *MessageSignature `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ServerRebalanceContractResponse) Reset()         { *m = ServerRebalanceContractResponse{} }
+func (m *ServerRebalanceContractResponse) String() string { return proto.CompactTextString(m) }
+func (*ServerRebalanceContractResponse) ProtoMessage()    {}
+func (*ServerRebalanceContractResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{11}
+}
+
+func (m *ServerRebalanceContractResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerRebalanceContractResponse.Unmarshal(m, b)
+}
+func (m *ServerRebalanceContractResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerRebalanceContractResponse.Marshal(b, m, deterministic)
+}
+func (m *ServerRebalanceContractResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerRebalanceContractResponse.Merge(m, src)
+}
+func (m *ServerRebalanceContractResponse) XXX_Size() int {
+	return xxx_messageInfo_ServerRebalanceContractResponse.Size(m)
+}
+func (m *ServerRebalanceContractResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerRebalanceContractResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServerRebalanceContractResponse proto.InternalMessageInfo
+
+func (m *ServerRebalanceContractResponse) GetPayReq() string {
+	if m != nil {
+		return m.PayReq
+	}
+	return ""
+}
+
+func (m *ServerRebalanceContractResponse) GetSignature() *MessageSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ServerListAssetsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServerListAssetsRequest) Reset()         { *m = ServerListAssetsRequest{} }
+func (m *ServerListAssetsRequest) String() string { return proto.CompactTextString(m) }
+func (*ServerListAssetsRequest) ProtoMessage()    {}
+func (*ServerListAssetsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{12}
+}
+
+func (m *ServerListAssetsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerListAssetsRequest.Unmarshal(m, b)
+}
+func (m *ServerListAssetsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerListAssetsRequest.Marshal(b, m, deterministic)
+}
+func (m *ServerListAssetsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerListAssetsRequest.Merge(m, src)
+}
+func (m *ServerListAssetsRequest) XXX_Size() int {
+	return xxx_messageInfo_ServerListAssetsRequest.Size(m)
+}
+func (m *ServerListAssetsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerListAssetsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServerListAssetsRequest proto.InternalMessageInfo
+
+type ServerListAssetsResponse struct {
+	SupportedAssets      []string `protobuf:"bytes,1,rep,name=supported_assets,json=supportedAssets,proto3" json:"supported_assets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServerListAssetsResponse) Reset()         { *m = ServerListAssetsResponse{} }
+func (m *ServerListAssetsResponse) String() string { return proto.CompactTextString(m) }
+func (*ServerListAssetsResponse) ProtoMessage()    {}
+func (*ServerListAssetsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{13}
 }
 
 func (m *ServerListAssetsResponse) XXX_Unmarshal(b []byte) error {
@@ -615,71 +1150,634 @@ func (m *ServerListAssetsResponse) GetSupportedAssets() []string {
 	return nil
 }
 
+// PushServerMessage is a request sent from the server to the client over
+// the push channel
+type PushServerMessage struct {
+	// identifies the request, the response of the client carries the same id
+	RequestId uint64 `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Request:
+	//	*PushServerMessage_InvoiceRequest
+	//	*PushServerMessage_PaymentRequest
+	Request              isPushServerMessage_Request `protobuf_oneof:"request"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *PushServerMessage) Reset()         { *m = PushServerMessage{} }
+func (m *PushServerMessage) String() string { return proto.CompactTextString(m) }
+func (*PushServerMessage) ProtoMessage()    {}
+func (*PushServerMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{14}
+}
+
+func (m *PushServerMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushServerMessage.Unmarshal(m, b)
+}
+func (m *PushServerMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushServerMessage.Marshal(b, m, deterministic)
+}
+func (m *PushServerMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushServerMessage.Merge(m, src)
+}
+func (m *PushServerMessage) XXX_Size() int {
+	return xxx_messageInfo_PushServerMessage.Size(m)
+}
+func (m *PushServerMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushServerMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushServerMessage proto.InternalMessageInfo
+
+func (m *PushServerMessage) GetRequestId() uint64 {
+	if m != nil {
+		return m.RequestId
+	}
+	return 0
+}
+
+type isPushServerMessage_Request interface {
+	isPushServerMessage_Request()
+}
+
+type PushServerMessage_InvoiceRequest struct {
+	InvoiceRequest *PushInvoiceRequest `protobuf:"bytes,2,opt,name=invoice_request,json=invoiceRequest,proto3,oneof"`
+}
+
+type PushServerMessage_PaymentRequest struct {
+	PaymentRequest *PushPaymentRequest `protobuf:"bytes,3,opt,name=payment_request,json=paymentRequest,proto3,oneof"`
+}
+
+func (*PushServerMessage_InvoiceRequest) isPushServerMessage_Request() {}
+
+func (*PushServerMessage_PaymentRequest) isPushServerMessage_Request() {}
+
+func (m *PushServerMessage) GetRequest() isPushServerMessage_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *PushServerMessage) GetInvoiceRequest() *PushInvoiceRequest {
+	if x, ok := m.GetRequest().(*PushServerMessage_InvoiceRequest); ok {
+		return x.InvoiceRequest
+	}
+	return nil
+}
+
+func (m *PushServerMessage) GetPaymentRequest() *PushPaymentRequest {
+	if x, ok := m.GetRequest().(*PushServerMessage_PaymentRequest); ok {
+		return x.PaymentRequest
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*PushServerMessage) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*PushServerMessage_InvoiceRequest)(nil),
+		(*PushServerMessage_PaymentRequest)(nil),
+	}
+}
+
+// PushClientMessage is sent from the client to the server over the push
+// channel, either to register the channel or to respond to a request
+type PushClientMessage struct {
+	// the id of the request this is a response to, 0 for the registration
+	RequestId uint64 `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Message:
+	//	*PushClientMessage_Registration
+	//	*PushClientMessage_InvoiceResponse
+	//	*PushClientMessage_PaymentResponse
+	//	*PushClientMessage_Error
+	Message              isPushClientMessage_Message `protobuf_oneof:"message"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *PushClientMessage) Reset()         { *m = PushClientMessage{} }
+func (m *PushClientMessage) String() string { return proto.CompactTextString(m) }
+func (*PushClientMessage) ProtoMessage()    {}
+func (*PushClientMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{15}
+}
+
+func (m *PushClientMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushClientMessage.Unmarshal(m, b)
+}
+func (m *PushClientMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushClientMessage.Marshal(b, m, deterministic)
+}
+func (m *PushClientMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushClientMessage.Merge(m, src)
+}
+func (m *PushClientMessage) XXX_Size() int {
+	return xxx_messageInfo_PushClientMessage.Size(m)
+}
+func (m *PushClientMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushClientMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushClientMessage proto.InternalMessageInfo
+
+func (m *PushClientMessage) GetRequestId() uint64 {
+	if m != nil {
+		return m.RequestId
+	}
+	return 0
+}
+
+type isPushClientMessage_Message interface {
+	isPushClientMessage_Message()
+}
+
+type PushClientMessage_Registration struct {
+	Registration *PushRegistration `protobuf:"bytes,2,opt,name=registration,proto3,oneof"`
+}
+
+type PushClientMessage_InvoiceResponse struct {
+	InvoiceResponse *PushInvoiceResponse `protobuf:"bytes,3,opt,name=invoice_response,json=invoiceResponse,proto3,oneof"`
+}
+
+type PushClientMessage_PaymentResponse struct {
+	PaymentResponse *PushPaymentResponse `protobuf:"bytes,4,opt,name=payment_response,json=paymentResponse,proto3,oneof"`
+}
+
+type PushClientMessage_Error struct {
+	Error *PushError `protobuf:"bytes,5,opt,name=error,proto3,oneof"`
+}
+
+func (*PushClientMessage_Registration) isPushClientMessage_Message() {}
+
+func (*PushClientMessage_InvoiceResponse) isPushClientMessage_Message() {}
+
+func (*PushClientMessage_PaymentResponse) isPushClientMessage_Message() {}
+
+func (*PushClientMessage_Error) isPushClientMessage_Message() {}
+
+func (m *PushClientMessage) GetMessage() isPushClientMessage_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *PushClientMessage) GetRegistration() *PushRegistration {
+	if x, ok := m.GetMessage().(*PushClientMessage_Registration); ok {
+		return x.Registration
+	}
+	return nil
+}
+
+func (m *PushClientMessage) GetInvoiceResponse() *PushInvoiceResponse {
+	if x, ok := m.GetMessage().(*PushClientMessage_InvoiceResponse); ok {
+		return x.InvoiceResponse
+	}
+	return nil
+}
+
+func (m *PushClientMessage) GetPaymentResponse() *PushPaymentResponse {
+	if x, ok := m.GetMessage().(*PushClientMessage_PaymentResponse); ok {
+		return x.PaymentResponse
+	}
+	return nil
+}
+
+func (m *PushClientMessage) GetError() *PushError {
+	if x, ok := m.GetMessage().(*PushClientMessage_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*PushClientMessage) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*PushClientMessage_Registration)(nil),
+		(*PushClientMessage_InvoiceResponse)(nil),
+		(*PushClientMessage_PaymentResponse)(nil),
+		(*PushClientMessage_Error)(nil),
+	}
+}
+
+// PushRegistration is the first message of every push channel. It is
+// signed by the node of the client, so no one else can take over the push
+// channel of the client.
+type PushRegistration struct {
+	// the identity pubkey of the lnd node of the client
+	NodePubkey           string            `protobuf:"bytes,1,opt,name=node_pubkey,json=nodePubkey,proto3" json:"node_pubkey,omitempty"`
+	Signature            *MessageSignature `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PushRegistration) Reset()         { *m = PushRegistration{} }
+func (m *PushRegistration) String() string { return proto.CompactTextString(m) }
+func (*PushRegistration) ProtoMessage()    {}
+func (*PushRegistration) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{16}
+}
+
+func (m *PushRegistration) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushRegistration.Unmarshal(m, b)
+}
+func (m *PushRegistration) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushRegistration.Marshal(b, m, deterministic)
+}
+func (m *PushRegistration) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushRegistration.Merge(m, src)
+}
+func (m *PushRegistration) XXX_Size() int {
+	return xxx_messageInfo_PushRegistration.Size(m)
+}
+func (m *PushRegistration) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushRegistration.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushRegistration proto.InternalMessageInfo
+
+func (m *PushRegistration) GetNodePubkey() string {
+	if m != nil {
+		return m.NodePubkey
+	}
+	return ""
+}
+
+func (m *PushRegistration) GetSignature() *MessageSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// PushInvoiceRequest asks the client for an invoice, used when the server
+// owes the client
+type PushInvoiceRequest struct {
+	Uuid                 string   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	AmountSat            int64    `protobuf:"varint,2,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushInvoiceRequest) Reset()         { *m = PushInvoiceRequest{} }
+func (m *PushInvoiceRequest) String() string { return proto.CompactTextString(m) }
+func (*PushInvoiceRequest) ProtoMessage()    {}
+func (*PushInvoiceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{17}
+}
+
+func (m *PushInvoiceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushInvoiceRequest.Unmarshal(m, b)
+}
+func (m *PushInvoiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushInvoiceRequest.Marshal(b, m, deterministic)
+}
+func (m *PushInvoiceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushInvoiceRequest.Merge(m, src)
+}
+func (m *PushInvoiceRequest) XXX_Size() int {
+	return xxx_messageInfo_PushInvoiceRequest.Size(m)
+}
+func (m *PushInvoiceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushInvoiceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushInvoiceRequest proto.InternalMessageInfo
+
+func (m *PushInvoiceRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *PushInvoiceRequest) GetAmountSat() int64 {
+	if m != nil {
+		return m.AmountSat
+	}
+	return 0
+}
+
+type PushInvoiceResponse struct {
+	PayReq               string   `protobuf:"bytes,1,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushInvoiceResponse) Reset()         { *m = PushInvoiceResponse{} }
+func (m *PushInvoiceResponse) String() string { return proto.CompactTextString(m) }
+func (*PushInvoiceResponse) ProtoMessage()    {}
+func (*PushInvoiceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{18}
+}
+
+func (m *PushInvoiceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushInvoiceResponse.Unmarshal(m, b)
+}
+func (m *PushInvoiceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushInvoiceResponse.Marshal(b, m, deterministic)
+}
+func (m *PushInvoiceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushInvoiceResponse.Merge(m, src)
+}
+func (m *PushInvoiceResponse) XXX_Size() int {
+	return xxx_messageInfo_PushInvoiceResponse.Size(m)
+}
+func (m *PushInvoiceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushInvoiceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushInvoiceResponse proto.InternalMessageInfo
+
+func (m *PushInvoiceResponse) GetPayReq() string {
+	if m != nil {
+		return m.PayReq
+	}
+	return ""
+}
+
+// PushPaymentRequest asks the client to pay an invoice, used when the
+// client owes the server
+type PushPaymentRequest struct {
+	Uuid                 string   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	PayReq               string   `protobuf:"bytes,2,opt,name=pay_req,json=payReq,proto3" json:"pay_req,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushPaymentRequest) Reset()         { *m = PushPaymentRequest{} }
+func (m *PushPaymentRequest) String() string { return proto.CompactTextString(m) }
+func (*PushPaymentRequest) ProtoMessage()    {}
+func (*PushPaymentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{19}
+}
+
+func (m *PushPaymentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushPaymentRequest.Unmarshal(m, b)
+}
+func (m *PushPaymentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushPaymentRequest.Marshal(b, m, deterministic)
+}
+func (m *PushPaymentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushPaymentRequest.Merge(m, src)
+}
+func (m *PushPaymentRequest) XXX_Size() int {
+	return xxx_messageInfo_PushPaymentRequest.Size(m)
+}
+func (m *PushPaymentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushPaymentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushPaymentRequest proto.InternalMessageInfo
+
+func (m *PushPaymentRequest) GetUuid() string {
+	if m != nil {
+		return m.Uuid
+	}
+	return ""
+}
+
+func (m *PushPaymentRequest) GetPayReq() string {
+	if m != nil {
+		return m.PayReq
+	}
+	return ""
+}
+
+type PushPaymentResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushPaymentResponse) Reset()         { *m = PushPaymentResponse{} }
+func (m *PushPaymentResponse) String() string { return proto.CompactTextString(m) }
+func (*PushPaymentResponse) ProtoMessage()    {}
+func (*PushPaymentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{20}
+}
+
+func (m *PushPaymentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushPaymentResponse.Unmarshal(m, b)
+}
+func (m *PushPaymentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushPaymentResponse.Marshal(b, m, deterministic)
+}
+func (m *PushPaymentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushPaymentResponse.Merge(m, src)
+}
+func (m *PushPaymentResponse) XXX_Size() int {
+	return xxx_messageInfo_PushPaymentResponse.Size(m)
+}
+func (m *PushPaymentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushPaymentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushPaymentResponse proto.InternalMessageInfo
+
+// PushError is sent instead of a response if the client could not handle a
+// request
+type PushError struct {
+	// the grpc status code of the error
+	Code    uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// set if the request failed because a payment failed
+	PaymentError         *PaymentError `protobuf:"bytes,3,opt,name=payment_error,json=paymentError,proto3" json:"payment_error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *PushError) Reset()         { *m = PushError{} }
+func (m *PushError) String() string { return proto.CompactTextString(m) }
+func (*PushError) ProtoMessage()    {}
+func (*PushError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{21}
+}
+
+func (m *PushError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushError.Unmarshal(m, b)
+}
+func (m *PushError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushError.Marshal(b, m, deterministic)
+}
+func (m *PushError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushError.Merge(m, src)
+}
+func (m *PushError) XXX_Size() int {
+	return xxx_messageInfo_PushError.Size(m)
+}
+func (m *PushError) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushError proto.InternalMessageInfo
+
+func (m *PushError) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *PushError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *PushError) GetPaymentError() *PaymentError {
+	if m != nil {
+		return m.PaymentError
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("ladrpc.PaymentType", PaymentType_name, PaymentType_value)
+	proto.RegisterEnum("ladrpc.PaymentState", PaymentState_name, PaymentState_value)
+	proto.RegisterEnum("ladrpc.PaymentFailureReason", PaymentFailureReason_name, PaymentFailureReason_value)
 	proto.RegisterEnum("ladrpc.ContractType", ContractType_name, ContractType_value)
 	proto.RegisterType((*ServerContract)(nil), "ladrpc.ServerContract")
 	proto.RegisterType((*Payment)(nil), "ladrpc.Payment")
+	proto.RegisterType((*PaymentError)(nil), "ladrpc.PaymentError")
 	proto.RegisterType((*Quote)(nil), "ladrpc.Quote")
 	proto.RegisterType((*Price)(nil), "ladrpc.Price")
+	proto.RegisterType((*MessageSignature)(nil), "ladrpc.MessageSignature")
 	proto.RegisterType((*ServerNewContractRequest)(nil), "ladrpc.ServerNewContractRequest")
 	proto.RegisterType((*ServerNewContractResponse)(nil), "ladrpc.ServerNewContractResponse")
 	proto.RegisterType((*ServerCloseContractRequest)(nil), "ladrpc.ServerCloseContractRequest")
 	proto.RegisterType((*ServerCloseContractResponse)(nil), "ladrpc.ServerCloseContractResponse")
+	proto.RegisterType((*ServerRebalanceContractRequest)(nil), "ladrpc.ServerRebalanceContractRequest")
+	proto.RegisterType((*ServerRebalanceContractResponse)(nil), "ladrpc.ServerRebalanceContractResponse")
 	proto.RegisterType((*ServerListAssetsRequest)(nil), "ladrpc.ServerListAssetsRequest")
 	proto.RegisterType((*ServerListAssetsResponse)(nil), "ladrpc.ServerListAssetsResponse")
+	proto.RegisterType((*PushServerMessage)(nil), "ladrpc.PushServerMessage")
+	proto.RegisterType((*PushClientMessage)(nil), "ladrpc.PushClientMessage")
+	proto.RegisterType((*PushRegistration)(nil), "ladrpc.PushRegistration")
+	proto.RegisterType((*PushInvoiceRequest)(nil), "ladrpc.PushInvoiceRequest")
+	proto.RegisterType((*PushInvoiceResponse)(nil), "ladrpc.PushInvoiceResponse")
+	proto.RegisterType((*PushPaymentRequest)(nil), "ladrpc.PushPaymentRequest")
+	proto.RegisterType((*PushPaymentResponse)(nil), "ladrpc.PushPaymentResponse")
+	proto.RegisterType((*PushError)(nil), "ladrpc.PushError")
 }
 
 func init() { proto.RegisterFile("server.proto", fileDescriptor_ad098daeda4239f7) }
 
 var fileDescriptor_ad098daeda4239f7 = []byte{
-	// 747 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x4f, 0x53, 0xe3, 0x36,
-	0x1c, 0xad, 0xf2, 0x8f, 0xe4, 0x97, 0x10, 0x52, 0x95, 0x01, 0x93, 0x96, 0xc1, 0x35, 0x74, 0x9a,
-	0x32, 0x2d, 0xe9, 0xc0, 0xa9, 0xdc, 0xda, 0x42, 0xa7, 0x87, 0x96, 0xa1, 0xa6, 0xb9, 0xec, 0xc5,
-	0x23, 0x6c, 0x4d, 0x56, 0xb3, 0x8e, 0x24, 0x2c, 0x19, 0x26, 0xd7, 0xfd, 0x00, 0x7b, 0xd8, 0x3d,
-	0xec, 0x87, 0xda, 0xe3, 0x5e, 0xf6, 0x03, 0xec, 0x07, 0xd9, 0xb1, 0x64, 0x27, 0x0e, 0x49, 0x18,
-	0x6e, 0xd2, 0xd3, 0x4f, 0xef, 0x27, 0xbd, 0xf7, 0x64, 0x43, 0x47, 0xd1, 0xe4, 0x9e, 0x26, 0x27,
-	0x32, 0x11, 0x5a, 0xe0, 0x46, 0x4c, 0xa2, 0x44, 0x86, 0xfd, 0xef, 0xc6, 0x42, 0x8c, 0x63, 0x3a,
-	0x24, 0x92, 0x0d, 0x09, 0xe7, 0x42, 0x13, 0xcd, 0x04, 0x57, 0xb6, 0xca, 0x7b, 0x53, 0x85, 0xee,
-	0x8d, 0xd9, 0xf6, 0xa7, 0xe0, 0x3a, 0x21, 0xa1, 0xc6, 0x18, 0x6a, 0x69, 0xca, 0x22, 0x07, 0xb9,
-	0x68, 0xd0, 0xf2, 0xcd, 0x18, 0x6f, 0x43, 0x9d, 0x28, 0x45, 0xb5, 0x53, 0x31, 0xa0, 0x9d, 0xe0,
-	0x1d, 0x68, 0x90, 0x89, 0x48, 0xb9, 0x76, 0xaa, 0x2e, 0x1a, 0x20, 0x3f, 0x9f, 0xe1, 0x03, 0x68,
-	0xdb, 0x51, 0xa0, 0x88, 0x56, 0x4e, 0xcd, 0x45, 0x83, 0xaa, 0x0f, 0x16, 0xba, 0x21, 0x5a, 0x65,
-	0x05, 0x61, 0xcc, 0x28, 0xd7, 0xc1, 0x4b, 0xa1, 0xb4, 0x53, 0x37, 0xa4, 0x60, 0xa1, 0xbf, 0x85,
-	0xd2, 0xf8, 0x08, 0xba, 0x13, 0x92, 0x8c, 0x19, 0x0f, 0x24, 0x99, 0x06, 0x09, 0xbd, 0x73, 0x1a,
-	0xa6, 0xa6, 0x63, 0xd1, 0x6b, 0x32, 0xf5, 0xe9, 0x1d, 0xfe, 0x19, 0x30, 0xe3, 0x4c, 0x33, 0xa2,
-	0x19, 0x1f, 0xcf, 0x2a, 0x37, 0x4c, 0x65, 0x6f, 0xbe, 0x92, 0x57, 0x1f, 0x40, 0x7b, 0xc6, 0xc9,
-	0x22, 0xa7, 0xe9, 0xa2, 0x41, 0xd3, 0x87, 0x82, 0x90, 0x45, 0xf8, 0x47, 0xd8, 0x5a, 0xa0, 0x63,
-	0x91, 0xd3, 0x32, 0x45, 0xdd, 0x32, 0x17, 0x8b, 0xf0, 0x6f, 0xb0, 0x19, 0xe6, 0x6a, 0x05, 0x7a,
-	0x2a, 0xa9, 0x03, 0x2e, 0x1a, 0x74, 0x4f, 0xb7, 0x4f, 0xac, 0xe4, 0x27, 0x85, 0x94, 0xff, 0x4f,
-	0x25, 0xf5, 0x3b, 0x61, 0x69, 0x96, 0x1d, 0x82, 0xa7, 0x93, 0x20, 0x95, 0x11, 0xd1, 0x54, 0x39,
-	0x6d, 0x2b, 0x0d, 0x4f, 0x27, 0x23, 0x8b, 0x78, 0x6f, 0x11, 0x6c, 0x5c, 0x93, 0xe9, 0x84, 0x72,
-	0x8d, 0x0f, 0x4b, 0x7d, 0x4a, 0x96, 0xcc, 0x18, 0x47, 0x99, 0x35, 0xfb, 0x00, 0x73, 0xb1, 0x8d,
-	0x3f, 0x55, 0xbf, 0x35, 0xd3, 0x3a, 0xbb, 0x94, 0xb4, 0x74, 0x99, 0x38, 0x29, 0x55, 0xd6, 0xac,
-	0x96, 0xdf, 0xcd, 0x61, 0xdf, 0xa2, 0xb8, 0x0f, 0x4d, 0x91, 0xea, 0x5b, 0x91, 0xf2, 0xc8, 0x38,
-	0xd6, 0xf4, 0x67, 0x73, 0x4f, 0x42, 0xfd, 0xbf, 0x54, 0x68, 0x8a, 0x7f, 0x80, 0xae, 0xa4, 0x49,
-	0x98, 0xb1, 0x59, 0xe1, 0xcc, 0x91, 0x90, 0xbf, 0x99, 0xa3, 0xff, 0x1a, 0xf0, 0x71, 0x00, 0x2a,
-	0xab, 0x02, 0x60, 0x22, 0x14, 0xc8, 0x84, 0x85, 0x34, 0x8f, 0x0f, 0x18, 0xe8, 0x3a, 0x43, 0xbc,
-	0x33, 0xa8, 0x9b, 0xc1, 0x3c, 0x79, 0xa8, 0x9c, 0xbc, 0x6d, 0xa8, 0xdf, 0x93, 0x38, 0xa5, 0x86,
-	0x1a, 0xf9, 0x76, 0xe2, 0xbd, 0x47, 0xe0, 0xd8, 0x30, 0x5f, 0xd1, 0x87, 0xc2, 0x84, 0xe2, 0x7e,
-	0xab, 0x89, 0xe6, 0x11, 0xae, 0x2c, 0x44, 0x18, 0x43, 0xcd, 0x44, 0xd3, 0x6a, 0x65, 0xc6, 0xcb,
-	0xb6, 0xd7, 0x9e, 0x6b, 0xbb, 0xf7, 0x01, 0xc1, 0xde, 0x8a, 0x93, 0x29, 0x29, 0xb8, 0xa2, 0x2b,
-	0x5f, 0xdc, 0xf2, 0x0b, 0xa8, 0x3c, 0xfb, 0x05, 0x54, 0xd7, 0xbc, 0x80, 0x65, 0xf7, 0x6a, 0xeb,
-	0xdc, 0x2b, 0x99, 0x53, 0x5f, 0x32, 0xe7, 0x57, 0xe8, 0xe7, 0xdf, 0x8c, 0x58, 0x28, 0xfa, 0x58,
-	0xe8, 0x15, 0xb7, 0xf1, 0xf6, 0xe1, 0xdb, 0x95, 0x3b, 0xac, 0x00, 0xde, 0x1e, 0xec, 0xda, 0xe5,
-	0x7f, 0x98, 0xd2, 0xbf, 0x67, 0x8d, 0x54, 0xce, 0xe6, 0x5d, 0x16, 0x96, 0x96, 0x97, 0x72, 0xdd,
-	0x7e, 0x82, 0x9e, 0x4a, 0xa5, 0x14, 0x89, 0xa6, 0x51, 0x60, 0xce, 0xa7, 0x1c, 0xe4, 0x56, 0x07,
-	0x2d, 0x7f, 0x6b, 0x86, 0xdb, 0x2d, 0xc7, 0x03, 0xe8, 0x94, 0xed, 0xc1, 0x00, 0x8d, 0xbf, 0x46,
-	0x57, 0x17, 0x97, 0x17, 0xbd, 0xaf, 0x70, 0x07, 0x9a, 0xa3, 0xab, 0x7c, 0x86, 0x4e, 0x3f, 0x55,
-	0xa0, 0x6d, 0x36, 0xd9, 0xb6, 0xf8, 0x15, 0xb4, 0x4b, 0x9e, 0x61, 0xb7, 0x70, 0x7b, 0x5d, 0xd0,
-	0xfa, 0xdf, 0x3f, 0x51, 0x91, 0xdf, 0x77, 0xf7, 0xf5, 0xc7, 0xcf, 0xef, 0x2a, 0x5f, 0x7b, 0x9d,
-	0x21, 0xa7, 0x0f, 0x45, 0x50, 0xce, 0xd1, 0x31, 0x56, 0xb0, 0xb9, 0xa0, 0x10, 0xf6, 0x16, 0xc9,
-	0x56, 0x09, 0xde, 0x3f, 0x7c, 0xb2, 0xa6, 0x90, 0xd8, 0xb4, 0xfc, 0xc6, 0xeb, 0x0e, 0xc3, 0x6c,
-	0xbd, 0xdc, 0x74, 0x0c, 0x30, 0x17, 0x17, 0x1f, 0x2c, 0xb2, 0x2d, 0x39, 0xd2, 0x77, 0xd7, 0x17,
-	0xe4, 0xbd, 0x76, 0x4c, 0xaf, 0x9e, 0xd7, 0x1e, 0xc6, 0x4c, 0x69, 0x6b, 0xcc, 0x39, 0x3a, 0xfe,
-	0xe3, 0xe8, 0x85, 0x47, 0x92, 0x90, 0x70, 0x1a, 0x26, 0x53, 0xa9, 0xc5, 0x30, 0xe6, 0x76, 0xed,
-	0x17, 0xfb, 0xe5, 0x1f, 0xc6, 0x24, 0x91, 0xe1, 0x6d, 0xc3, 0xfc, 0x99, 0xce, 0xbe, 0x04, 0x00,
-	0x00, 0xff, 0xff, 0x80, 0xdb, 0x31, 0x3d, 0xcf, 0x06, 0x00, 0x00,
+	// 1679 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xcd, 0x6e, 0xe3, 0xc8,
+	0x11, 0x36, 0x29, 0xc9, 0xb6, 0x4a, 0x3f, 0x43, 0xb7, 0xbd, 0x63, 0x8e, 0x66, 0x36, 0xa3, 0xe5,
+	0x6c, 0x32, 0x5a, 0x63, 0x63, 0x05, 0xce, 0x22, 0x40, 0x72, 0x08, 0xa0, 0x91, 0x69, 0x4b, 0x80,
+	0x2d, 0x2b, 0x2d, 0x0b, 0x48, 0x72, 0x21, 0x7a, 0xa8, 0x1e, 0x89, 0x18, 0x89, 0xe4, 0x92, 0xcd,
+	0x59, 0xf8, 0x98, 0x04, 0x79, 0x82, 0x1c, 0xf2, 0x08, 0x79, 0x82, 0xdc, 0xf2, 0x06, 0x39, 0xe6,
+	0x14, 0x20, 0xc7, 0x5c, 0xf2, 0x00, 0xb9, 0x07, 0xfd, 0x43, 0x89, 0xd4, 0xcf, 0xac, 0x33, 0x37,
+	0x76, 0x75, 0xf5, 0x57, 0x55, 0x5f, 0x75, 0x57, 0x15, 0x08, 0xd5, 0x98, 0x46, 0x1f, 0x68, 0x74,
+	0x1e, 0x46, 0x01, 0x0b, 0xd0, 0xfe, 0x9c, 0x4c, 0xa2, 0xd0, 0x6d, 0xbc, 0x98, 0x06, 0xc1, 0x74,
+	0x4e, 0xdb, 0x24, 0xf4, 0xda, 0xc4, 0xf7, 0x03, 0x46, 0x98, 0x17, 0xf8, 0xb1, 0xd4, 0xb2, 0xfe,
+	0x5a, 0x80, 0xfa, 0x48, 0x1c, 0xeb, 0x06, 0x3e, 0x8b, 0x88, 0xcb, 0x10, 0x82, 0x62, 0x92, 0x78,
+	0x13, 0x53, 0x6b, 0x6a, 0xad, 0x32, 0x16, 0xdf, 0xe8, 0x04, 0x4a, 0x24, 0x8e, 0x29, 0x33, 0x75,
+	0x21, 0x94, 0x0b, 0xf4, 0x14, 0xf6, 0xc9, 0x22, 0x48, 0x7c, 0x66, 0x16, 0x9a, 0x5a, 0x4b, 0xc3,
+	0x6a, 0x85, 0x5e, 0x42, 0x45, 0x7e, 0x39, 0x31, 0x61, 0xb1, 0x59, 0x6c, 0x6a, 0xad, 0x02, 0x06,
+	0x29, 0x1a, 0x11, 0x16, 0x73, 0x05, 0x77, 0xee, 0x51, 0x9f, 0x39, 0xb3, 0x20, 0x66, 0x66, 0x49,
+	0x80, 0x82, 0x14, 0xf5, 0x82, 0x98, 0xa1, 0x2f, 0xa1, 0xbe, 0x20, 0xd1, 0xd4, 0xf3, 0x9d, 0x90,
+	0x3c, 0x38, 0x11, 0xfd, 0xd6, 0xdc, 0x17, 0x3a, 0x55, 0x29, 0x1d, 0x92, 0x07, 0x4c, 0xbf, 0x45,
+	0x5f, 0x03, 0xf2, 0x7c, 0x8f, 0x79, 0x84, 0x79, 0xfe, 0x74, 0xa9, 0x79, 0x20, 0x34, 0x8d, 0xd5,
+	0x8e, 0xd2, 0x7e, 0x09, 0x95, 0x25, 0xa6, 0x37, 0x31, 0x0f, 0x9b, 0x5a, 0xeb, 0x10, 0x43, 0x0a,
+	0xe8, 0x4d, 0xd0, 0x6b, 0x78, 0x92, 0x83, 0xf3, 0x26, 0x66, 0x59, 0x28, 0xd5, 0xb3, 0x58, 0xde,
+	0x04, 0xfd, 0x1c, 0x6a, 0xae, 0x62, 0xcb, 0x61, 0x0f, 0x21, 0x35, 0xa1, 0xa9, 0xb5, 0xea, 0x17,
+	0x27, 0xe7, 0x92, 0xf2, 0xf3, 0x94, 0xca, 0xfb, 0x87, 0x90, 0xe2, 0xaa, 0x9b, 0x59, 0x71, 0x27,
+	0xfc, 0x64, 0xe1, 0x24, 0xe1, 0x84, 0x30, 0x1a, 0x9b, 0x15, 0x49, 0x8d, 0x9f, 0x2c, 0xc6, 0x52,
+	0x82, 0x7e, 0x08, 0x75, 0xc5, 0xdd, 0x84, 0xba, 0xde, 0x82, 0xcc, 0xcd, 0xaa, 0x88, 0xa7, 0x26,
+	0xa5, 0x97, 0x52, 0x68, 0xfd, 0xab, 0x00, 0x07, 0x43, 0xf2, 0xb0, 0xa0, 0x3e, 0x43, 0xaf, 0x32,
+	0xee, 0x64, 0x32, 0xb7, 0x34, 0x3c, 0xe6, 0x19, 0xfc, 0x1c, 0x60, 0x95, 0x13, 0x91, 0xc6, 0x02,
+	0x2e, 0x2f, 0x53, 0xc2, 0x63, 0x0f, 0x25, 0x1c, 0xe7, 0x30, 0xa1, 0xb1, 0xcc, 0x69, 0x19, 0xd7,
+	0x95, 0x18, 0x4b, 0x29, 0x6a, 0xc0, 0x61, 0x90, 0xb0, 0xb7, 0x41, 0xe2, 0x4f, 0x44, 0x62, 0x0f,
+	0xf1, 0x72, 0x8d, 0xbe, 0x80, 0x6a, 0x0a, 0x32, 0x23, 0xf1, 0x4c, 0xe5, 0xb5, 0xa2, 0x64, 0x3d,
+	0x12, 0xcf, 0xf8, 0xf1, 0x30, 0xa2, 0xde, 0x82, 0x4c, 0xa9, 0x4a, 0xe9, 0x72, 0x8d, 0x4e, 0xe1,
+	0xe0, 0x1d, 0xa5, 0xc2, 0xbf, 0x03, 0xe1, 0xdf, 0xfe, 0x3b, 0x4a, 0xa5, 0x73, 0x45, 0x41, 0xf3,
+	0xa1, 0xa0, 0xf9, 0x38, 0xa5, 0x59, 0xc5, 0x2f, 0x58, 0x16, 0x0a, 0xc8, 0x84, 0x83, 0x98, 0x32,
+	0x36, 0xa7, 0x69, 0xe6, 0xd2, 0x25, 0x0f, 0xdf, 0x8d, 0x28, 0x61, 0x74, 0xe2, 0x10, 0x26, 0xf2,
+	0x55, 0xc0, 0x65, 0x25, 0xe9, 0x30, 0xbe, 0xad, 0x34, 0xf9, 0xb6, 0xcc, 0x4a, 0x59, 0x49, 0x3a,
+	0x0c, 0x9d, 0x41, 0x29, 0x66, 0x84, 0x51, 0xb3, 0x9a, 0x4f, 0xb4, 0xf2, 0x60, 0xc4, 0xf7, 0xb0,
+	0x54, 0x41, 0x5d, 0xa8, 0xbf, 0x23, 0xde, 0x3c, 0x89, 0xa8, 0x13, 0x51, 0x12, 0x07, 0xbe, 0x59,
+	0x13, 0x87, 0x5e, 0xac, 0x1d, 0xba, 0x92, 0x4a, 0x58, 0xe8, 0xe0, 0xda, 0xbb, 0xec, 0xd2, 0xfa,
+	0x9d, 0x06, 0x55, 0xa5, 0x67, 0x47, 0x51, 0x10, 0x6d, 0x50, 0xab, 0x6d, 0x52, 0xfb, 0x0d, 0xec,
+	0x2b, 0x83, 0xfa, 0x23, 0x0c, 0x2a, 0x5d, 0x4e, 0xd9, 0x82, 0xc6, 0x31, 0xcf, 0x87, 0x4c, 0x78,
+	0xba, 0xb4, 0x42, 0x28, 0xfd, 0x2a, 0x09, 0x18, 0xe5, 0x57, 0x32, 0xa4, 0x91, 0xcb, 0x6d, 0xcb,
+	0xd7, 0x22, 0xac, 0x6b, 0xb8, 0xa6, 0xa4, 0xb7, 0x42, 0xb8, 0xfe, 0xea, 0xf5, 0x6d, 0xaf, 0x5e,
+	0xd4, 0x0d, 0x27, 0x8c, 0x3c, 0x97, 0xaa, 0x9a, 0x01, 0x42, 0x34, 0xe4, 0x12, 0xeb, 0xd7, 0x50,
+	0x12, 0x1f, 0xab, 0x72, 0xa3, 0x65, 0xcb, 0xcd, 0x09, 0x94, 0x3e, 0x90, 0x79, 0x42, 0x05, 0xb4,
+	0x86, 0xe5, 0x82, 0xdf, 0x7e, 0xf1, 0xb1, 0x7c, 0x2f, 0x32, 0x8c, 0xaa, 0x10, 0xa6, 0xcf, 0xe5,
+	0x8f, 0x1a, 0x18, 0xb7, 0x32, 0xae, 0x91, 0x37, 0xf5, 0x09, 0x4b, 0x22, 0xf9, 0x16, 0x83, 0x09,
+	0x75, 0xc2, 0xe4, 0xed, 0x7b, 0xfa, 0xa0, 0x6c, 0x01, 0x17, 0x0d, 0x85, 0x04, 0xbd, 0x80, 0x32,
+	0xf3, 0x16, 0x34, 0x66, 0x64, 0x11, 0xa6, 0x4f, 0x66, 0x29, 0xe0, 0xee, 0xf8, 0x81, 0xef, 0xa6,
+	0xbc, 0xc9, 0x05, 0x3f, 0x13, 0xa7, 0x16, 0xc4, 0x03, 0x29, 0xe3, 0x95, 0xc0, 0xfa, 0xb3, 0x0e,
+	0xa6, 0x2c, 0xb7, 0x03, 0xfa, 0x5d, 0x5a, 0x26, 0xd2, 0xa7, 0xb5, 0x3d, 0xea, 0x55, 0x91, 0xd5,
+	0x73, 0x45, 0x16, 0x41, 0x51, 0x14, 0x4f, 0x69, 0x5d, 0x7c, 0x6f, 0x16, 0xa6, 0xe2, 0xff, 0x55,
+	0x98, 0x32, 0x64, 0x94, 0x36, 0xc8, 0xf8, 0x59, 0x36, 0x30, 0xfe, 0x74, 0x2b, 0x17, 0x66, 0x8a,
+	0xbb, 0x4e, 0x6d, 0x26, 0xe4, 0x2d, 0x05, 0xed, 0x60, 0x5b, 0x41, 0xfb, 0x8f, 0x0e, 0xcf, 0xb6,
+	0x30, 0x13, 0x87, 0x81, 0x1f, 0xd3, 0xad, 0x3d, 0x69, 0xb3, 0x47, 0xe8, 0x8f, 0xee, 0x11, 0x85,
+	0x1d, 0x3d, 0x62, 0xf3, 0xaa, 0x17, 0x77, 0x5d, 0xf5, 0xcc, 0x4d, 0x2e, 0xad, 0xdf, 0xe4, 0x4f,
+	0x26, 0xeb, 0x1b, 0x78, 0x9a, 0xb7, 0xbf, 0x46, 0xda, 0x49, 0xce, 0x0f, 0xc5, 0x1d, 0x3a, 0x87,
+	0xe3, 0x8c, 0x3b, 0xcb, 0x23, 0x87, 0xe2, 0xc8, 0xd1, 0xca, 0xad, 0x94, 0xeb, 0x19, 0x34, 0x54,
+	0xcf, 0x9f, 0x07, 0x31, 0x5d, 0xbf, 0x86, 0xdb, 0xb8, 0xce, 0xc5, 0xa3, 0x3f, 0x3a, 0x1e, 0x6b,
+	0x0c, 0xcf, 0xb7, 0x5a, 0x52, 0x69, 0xcd, 0xc1, 0x6a, 0x8f, 0x87, 0xfd, 0xaf, 0x06, 0x3f, 0x90,
+	0xb8, 0x98, 0xbe, 0x25, 0x73, 0xe2, 0xbb, 0x8f, 0x8a, 0x62, 0x2d, 0x6d, 0xfa, 0x46, 0xda, 0xf2,
+	0x4d, 0xb2, 0xb0, 0xde, 0x24, 0x4f, 0xe1, 0x20, 0xbd, 0x40, 0xf2, 0x65, 0xef, 0x87, 0xf2, 0xda,
+	0xec, 0x48, 0x40, 0x69, 0x47, 0x02, 0x3e, 0xf5, 0x7a, 0x58, 0x11, 0xbc, 0xdc, 0x19, 0xb6, 0xa2,
+	0x34, 0xe3, 0xa3, 0x96, 0xf3, 0xf1, 0x53, 0x53, 0xf8, 0x0c, 0x4e, 0xa5, 0xcd, 0x1b, 0x2f, 0x66,
+	0x1d, 0x1e, 0x4a, 0xac, 0x38, 0xb6, 0x6c, 0x30, 0x37, 0xb7, 0x94, 0x1f, 0x5f, 0x81, 0x11, 0x27,
+	0x61, 0x18, 0x44, 0xa2, 0xe5, 0x8a, 0x3d, 0x53, 0x6b, 0x16, 0x5a, 0x65, 0xfc, 0x64, 0x29, 0x97,
+	0x47, 0xac, 0xbf, 0x6b, 0x70, 0x34, 0x4c, 0xe2, 0x99, 0xc4, 0x52, 0xbe, 0xf0, 0x5c, 0xa8, 0x49,
+	0xc4, 0x51, 0x69, 0x2c, 0xe2, 0xb2, 0x92, 0xf4, 0x27, 0xc8, 0xe6, 0xc3, 0xda, 0x87, 0x80, 0xd3,
+	0xad, 0x84, 0x2a, 0xa8, 0xc6, 0xb2, 0xed, 0x25, 0xf1, 0xac, 0x2f, 0x55, 0x94, 0xc3, 0xbd, 0x3d,
+	0x3e, 0xca, 0x65, 0x25, 0x1c, 0x66, 0xdb, 0xdc, 0xb3, 0x06, 0x33, 0xcc, 0xcd, 0x40, 0x1c, 0x26,
+	0x3f, 0x15, 0xbd, 0x29, 0xc3, 0x81, 0x3a, 0x6e, 0xfd, 0x4d, 0x97, 0xd1, 0x74, 0xc5, 0x34, 0xfb,
+	0xc8, 0x68, 0x7e, 0x09, 0xd5, 0x88, 0x4e, 0xbd, 0x98, 0x45, 0x62, 0x3a, 0x5f, 0xcf, 0x0f, 0xc7,
+	0xc3, 0x99, 0xfd, 0xde, 0x1e, 0xce, 0xe9, 0xa3, 0x1e, 0x18, 0x2b, 0x36, 0x64, 0x06, 0x54, 0x1c,
+	0xcf, 0xb7, 0xd2, 0x21, 0x55, 0x7a, 0x7b, 0xf8, 0x89, 0x97, 0x17, 0x71, 0xa4, 0x15, 0x21, 0x0a,
+	0xa9, 0xb8, 0x89, 0xb4, 0x64, 0x64, 0x85, 0x14, 0xe6, 0x45, 0xe8, 0x2b, 0x28, 0xd1, 0x28, 0x0a,
+	0x22, 0xf1, 0x0c, 0x2a, 0x17, 0x47, 0xd9, 0xe3, 0x62, 0xa8, 0xe9, 0xed, 0x61, 0xa9, 0xc1, 0xe9,
+	0x4b, 0xa7, 0x8e, 0xf7, 0x60, 0xac, 0x47, 0xfb, 0xfd, 0x8d, 0xfa, 0x53, 0xef, 0xf6, 0x35, 0xa0,
+	0xcd, 0x5b, 0xb2, 0xb5, 0x74, 0x7c, 0x7c, 0x7c, 0xb6, 0xce, 0xe1, 0x78, 0x0b, 0xbf, 0x3b, 0x1f,
+	0xa3, 0xd5, 0x01, 0x94, 0x63, 0x71, 0xb7, 0xe1, 0x0c, 0x84, 0x9e, 0x83, 0xf8, 0x0c, 0x8e, 0x73,
+	0x10, 0xd2, 0xa4, 0xc5, 0xa0, 0xbc, 0x24, 0x98, 0x03, 0xba, 0xc1, 0x44, 0x96, 0xd6, 0x1a, 0x16,
+	0xdf, 0xd9, 0x81, 0x4f, 0xcf, 0x0d, 0x7c, 0x7c, 0x7a, 0x48, 0x53, 0x2f, 0x13, 0x27, 0x6f, 0xd0,
+	0xfa, 0xb4, 0x2b, 0xa0, 0x71, 0x35, 0xcc, 0xac, 0xce, 0x2e, 0xa0, 0x92, 0x99, 0xc6, 0x11, 0xc0,
+	0xfe, 0x6d, 0x07, 0x5f, 0xf7, 0x07, 0xc6, 0x1e, 0x3a, 0x84, 0x62, 0x7f, 0xd0, 0xbf, 0x37, 0x34,
+	0x54, 0x83, 0x32, 0xb6, 0xdf, 0x74, 0x6e, 0x3a, 0x83, 0xae, 0x6d, 0xe8, 0x67, 0x14, 0xaa, 0xd9,
+	0xf9, 0x19, 0x1d, 0xc3, 0x93, 0x61, 0xe7, 0x37, 0xb7, 0xf6, 0xe0, 0xde, 0x19, 0xda, 0x83, 0xcb,
+	0xfe, 0xe0, 0xda, 0xd8, 0x43, 0x9f, 0xc1, 0x51, 0x2a, 0xec, 0x0f, 0x9c, 0xab, 0x9b, 0xfe, 0x75,
+	0x8f, 0x43, 0x65, 0xc4, 0xa3, 0x71, 0xb7, 0x6b, 0xdb, 0x97, 0xf6, 0xa5, 0xa1, 0x23, 0x04, 0xf5,
+	0x54, 0x7c, 0xd5, 0xe9, 0xdf, 0xd8, 0x97, 0x46, 0xe1, 0xec, 0x9f, 0x1a, 0x9c, 0x6c, 0x9b, 0x80,
+	0xd1, 0x29, 0x1c, 0x73, 0xa5, 0x31, 0xb6, 0x1d, 0x6c, 0x77, 0x46, 0x77, 0x03, 0x67, 0x70, 0x37,
+	0xb0, 0x8d, 0x3d, 0xd4, 0x80, 0xa7, 0x6b, 0x1b, 0xf7, 0xfd, 0x5b, 0xfb, 0x6e, 0xcc, 0x0d, 0x3f,
+	0x87, 0xd3, 0x8d, 0x43, 0x0e, 0xbe, 0x1b, 0xdf, 0xdb, 0x86, 0x8e, 0x5e, 0xc3, 0xab, 0xb5, 0xcd,
+	0xfe, 0x60, 0x34, 0xbe, 0xba, 0xea, 0x77, 0xfb, 0xdc, 0xa5, 0x34, 0xf4, 0x02, 0xfa, 0x1a, 0x5a,
+	0x1b, 0x8a, 0xdd, 0x3b, 0x8c, 0xed, 0xee, 0xbd, 0x93, 0x06, 0x70, 0x69, 0xdf, 0x77, 0xfa, 0x37,
+	0x23, 0xa3, 0x88, 0x4c, 0x38, 0x59, 0xd3, 0xb6, 0x31, 0xbe, 0xc3, 0x46, 0xe9, 0xac, 0x05, 0xd5,
+	0xec, 0x48, 0xc7, 0x79, 0xbf, 0x1a, 0x0f, 0x38, 0x17, 0x7b, 0xa8, 0x0a, 0x87, 0xe3, 0x81, 0x5a,
+	0x69, 0x17, 0x7f, 0x29, 0x42, 0x45, 0x94, 0x5b, 0x59, 0x64, 0xd1, 0x7b, 0xa8, 0x64, 0xe6, 0x2c,
+	0xd4, 0x4c, 0x73, 0xbc, 0x6b, 0x38, 0x6d, 0x7c, 0xf1, 0x11, 0x0d, 0x75, 0xf5, 0x4e, 0x7f, 0xff,
+	0x8f, 0x7f, 0xff, 0x49, 0x3f, 0xfa, 0x85, 0x76, 0x66, 0x55, 0xdb, 0x3e, 0xfd, 0x2e, 0x9d, 0x2f,
+	0x51, 0x0c, 0xb5, 0x5c, 0xff, 0x47, 0x56, 0x1e, 0x6c, 0xdb, 0x18, 0xd2, 0x78, 0xf5, 0x51, 0x1d,
+	0x65, 0xf2, 0x99, 0x30, 0x79, 0xcc, 0x4d, 0xd6, 0xdb, 0x2e, 0x57, 0x59, 0x1a, 0xfd, 0x83, 0x06,
+	0x47, 0x1b, 0x6d, 0x12, 0xfd, 0x28, 0x8f, 0xba, 0x6b, 0x7c, 0x68, 0xbc, 0xfe, 0x5e, 0x3d, 0xe5,
+	0xc1, 0xe7, 0xc2, 0x83, 0x53, 0xee, 0x01, 0x6a, 0x47, 0xa9, 0xda, 0xd2, 0x8b, 0x29, 0xc0, 0xaa,
+	0x39, 0xa2, 0x97, 0x79, 0xd4, 0x8d, 0x8e, 0xda, 0x68, 0xee, 0x56, 0x50, 0xf6, 0x9e, 0x0a, 0x7b,
+	0x06, 0xb7, 0x57, 0x69, 0xcf, 0xbd, 0x98, 0xc9, 0xde, 0x8a, 0xae, 0xa1, 0x22, 0xba, 0xce, 0x8c,
+	0xf8, 0x3e, 0x9d, 0xa3, 0x67, 0xd9, 0x6a, 0x9b, 0x6b, 0x45, 0x8d, 0xdc, 0x56, 0xae, 0xe7, 0xb6,
+	0xb4, 0x9f, 0x68, 0x6f, 0xbe, 0xfc, 0xad, 0x45, 0x22, 0x97, 0xf8, 0xd4, 0x8d, 0x1e, 0x42, 0x16,
+	0xb4, 0xe7, 0xbe, 0xb4, 0xf0, 0x63, 0xf9, 0x7b, 0xa6, 0x3d, 0x27, 0x51, 0xe8, 0xbe, 0xdd, 0x17,
+	0xbf, 0x8f, 0x7e, 0xfa, 0xbf, 0x01, 0x00, 0xff, 0x37, 0xbc, 0x2e, 0x74, 0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -694,11 +1792,25 @@ const _ = grpc.SupportPackageIsVersion4
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type AssetServerClient interface {
+	// NewContract is used to initiate a new contract with this server. The
+	// client pins the node that creates the invoices, and refuses invoices
+	// created by any other node.
 	NewContract(ctx context.Context, in *ServerNewContractRequest, opts ...grpc.CallOption) (*ServerNewContractResponse, error)
 	// CloseContract is used to close a contract with a specific uuid
 	CloseContract(ctx context.Context, in *ServerCloseContractRequest, opts ...grpc.CallOption) (*ServerCloseContractResponse, error)
+	// RebalanceContract is used to settle the difference between the current
+	// value of a contract and the value it was last settled at. If the server
+	// owes the client, the request contains an invoice for the server to pay.
+	// If the client owes the server, the response contains an invoice for the
+	// client to pay.
+	RebalanceContract(ctx context.Context, in *ServerRebalanceContractRequest, opts ...grpc.CallOption) (*ServerRebalanceContractResponse, error)
 	// ListAssets lists all supported assets
 	ListAssets(ctx context.Context, in *ServerListAssetsRequest, opts ...grpc.CallOption) (*ServerListAssetsResponse, error)
+	// PushChannel is a long-lived stream opened by the client, over which
+	// the server asks the client for invoices and payments. It lets clients
+	// that can not accept incoming connections, ie behind NAT, take part in
+	// contracts. The first message from the client must be a registration.
+	PushChannel(ctx context.Context, opts ...grpc.CallOption) (AssetServer_PushChannelClient, error)
 }
 
 type assetServerClient struct {
@@ -727,6 +1839,15 @@ func (c *assetServerClient) CloseContract(ctx context.Context, in *ServerCloseCo
 	return out, nil
 }
 
+func (c *assetServerClient) RebalanceContract(ctx context.Context, in *ServerRebalanceContractRequest, opts ...grpc.CallOption) (*ServerRebalanceContractResponse, error) {
+	out := new(ServerRebalanceContractResponse)
+	err := c.cc.Invoke(ctx, "/ladrpc.AssetServer/RebalanceContract", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *assetServerClient) ListAssets(ctx context.Context, in *ServerListAssetsRequest, opts ...grpc.CallOption) (*ServerListAssetsResponse, error) {
 	out := new(ServerListAssetsResponse)
 	err := c.cc.Invoke(ctx, "/ladrpc.AssetServer/ListAssets", in, out, opts...)
@@ -736,13 +1857,58 @@ func (c *assetServerClient) ListAssets(ctx context.Context, in *ServerListAssets
 	return out, nil
 }
 
+func (c *assetServerClient) PushChannel(ctx context.Context, opts ...grpc.CallOption) (AssetServer_PushChannelClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AssetServer_serviceDesc.Streams[0], "/ladrpc.AssetServer/PushChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &assetServerPushChannelClient{stream}
+	return x, nil
+}
+
+type AssetServer_PushChannelClient interface {
+	Send(*PushClientMessage) error
+	Recv() (*PushServerMessage, error)
+	grpc.ClientStream
+}
+
+type assetServerPushChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *assetServerPushChannelClient) Send(m *PushClientMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *assetServerPushChannelClient) Recv() (*PushServerMessage, error) {
+	m := new(PushServerMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AssetServerServer is the server API for AssetServer service.
 type AssetServerServer interface {
+	// NewContract is used to initiate a new contract with this server. The
+	// client pins the node that creates the invoices, and refuses invoices
+	// created by any other node.
 	NewContract(context.Context, *ServerNewContractRequest) (*ServerNewContractResponse, error)
 	// CloseContract is used to close a contract with a specific uuid
 	CloseContract(context.Context, *ServerCloseContractRequest) (*ServerCloseContractResponse, error)
+	// RebalanceContract is used to settle the difference between the current
+	// value of a contract and the value it was last settled at. If the server
+	// owes the client, the request contains an invoice for the server to pay.
+	// If the client owes the server, the response contains an invoice for the
+	// client to pay.
+	RebalanceContract(context.Context, *ServerRebalanceContractRequest) (*ServerRebalanceContractResponse, error)
 	// ListAssets lists all supported assets
 	ListAssets(context.Context, *ServerListAssetsRequest) (*ServerListAssetsResponse, error)
+	// PushChannel is a long-lived stream opened by the client, over which
+	// the server asks the client for invoices and payments. It lets clients
+	// that can not accept incoming connections, ie behind NAT, take part in
+	// contracts. The first message from the client must be a registration.
+	PushChannel(AssetServer_PushChannelServer) error
 }
 
 // UnimplementedAssetServerServer can be embedded to have forward compatible implementations.
@@ -755,9 +1921,15 @@ func (*UnimplementedAssetServerServer) NewContract(ctx context.Context, req *Ser
 func (*UnimplementedAssetServerServer) CloseContract(ctx context.Context, req *ServerCloseContractRequest) (*ServerCloseContractResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CloseContract not implemented")
 }
+func (*UnimplementedAssetServerServer) RebalanceContract(ctx context.Context, req *ServerRebalanceContractRequest) (*ServerRebalanceContractResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebalanceContract not implemented")
+}
 func (*UnimplementedAssetServerServer) ListAssets(ctx context.Context, req *ServerListAssetsRequest) (*ServerListAssetsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAssets not implemented")
 }
+func (*UnimplementedAssetServerServer) PushChannel(srv AssetServer_PushChannelServer) error {
+	return status.Errorf(codes.Unimplemented, "method PushChannel not implemented")
+}
 
 func RegisterAssetServerServer(s *grpc.Server, srv AssetServerServer) {
 	s.RegisterService(&_AssetServer_serviceDesc, srv)
@@ -799,6 +1971,24 @@ func _AssetServer_CloseContract_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetServer_RebalanceContract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerRebalanceContractRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetServerServer).RebalanceContract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ladrpc.AssetServer/RebalanceContract",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetServerServer).RebalanceContract(ctx, req.(*ServerRebalanceContractRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AssetServer_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ServerListAssetsRequest)
 	if err := dec(in); err != nil {
@@ -817,6 +2007,32 @@ func _AssetServer_ListAssets_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetServer_PushChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AssetServerServer).PushChannel(&assetServerPushChannelServer{stream})
+}
+
+type AssetServer_PushChannelServer interface {
+	Send(*PushServerMessage) error
+	Recv() (*PushClientMessage, error)
+	grpc.ServerStream
+}
+
+type assetServerPushChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *assetServerPushChannelServer) Send(m *PushServerMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *assetServerPushChannelServer) Recv() (*PushClientMessage, error) {
+	m := new(PushClientMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _AssetServer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "ladrpc.AssetServer",
 	HandlerType: (*AssetServerServer)(nil),
@@ -829,11 +2045,22 @@ var _AssetServer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CloseContract",
 			Handler:    _AssetServer_CloseContract_Handler,
 		},
+		{
+			MethodName: "RebalanceContract",
+			Handler:    _AssetServer_RebalanceContract_Handler,
+		},
 		{
 			MethodName: "ListAssets",
 			Handler:    _AssetServer_ListAssets_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushChannel",
+			Handler:       _AssetServer_PushChannel_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "server.proto",
 }
@@ -0,0 +1,108 @@
+package larpc
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// MaxSignatureAge is how far the timestamp of a MessageSignature may be from
+// the time it is verified at
+const MaxSignatureAge = 5 * time.Minute
+
+// signingPayload joins what is signed for a message of method: the fields
+// of the signature, except the signature itself, and the signed fields of
+// the message. The method is included so a signature can not be replayed
+// for another rpc. Every field is prefixed with its length, so the content
+// of one field can not be moved into the next.
+func signingPayload(method string, sig *MessageSignature, fields ...string) []byte {
+	fields = append([]string{
+		"lassets/" + method,
+		sig.GetNodePubkey(),
+		strconv.FormatInt(sig.GetTimestamp(), 10),
+		sig.GetNonce(),
+	}, fields...)
+
+	var payload bytes.Buffer
+	for _, field := range fields {
+		payload.WriteString(strconv.Itoa(len(field)))
+		payload.WriteByte(':')
+		payload.WriteString(field)
+		payload.WriteByte('\n')
+	}
+
+	return payload.Bytes()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// SigningPayload returns the message the client node signs for the request
+func (m *ServerNewContractRequest) SigningPayload() []byte {
+	return signingPayload("NewContractRequest", m.GetSignature(),
+		m.GetAsset(),
+		formatFloat(m.GetAmount()),
+		m.GetHost(),
+		strconv.Itoa(int(m.GetContractType())),
+		m.GetNodePubkey(),
+		m.GetAmountDecimal(),
+	)
+}
+
+// SigningPayload returns the message the server node signs for the response
+func (m *ServerNewContractResponse) SigningPayload() []byte {
+	return signingPayload("NewContractResponse", m.GetSignature(),
+		m.GetUuid(),
+		m.GetMarginPayReq(),
+		m.GetInitiatingPayReq(),
+		formatFloat(m.GetPercentMargin()),
+		formatFloat(m.GetAssetPrice()),
+		m.GetPercentMarginDecimal(),
+		m.GetAssetPriceDecimal(),
+	)
+}
+
+// SigningPayload returns the message the client node signs for the request
+func (m *ServerCloseContractRequest) SigningPayload() []byte {
+	return signingPayload("CloseContractRequest", m.GetSignature(),
+		m.GetUuid(),
+	)
+}
+
+// SigningPayload returns the message the server node signs for the response
+// to closing the contract with uuid. The response has no fields of its own,
+// so the uuid of the request is signed instead.
+func (m *ServerCloseContractResponse) SigningPayload(uuid string) []byte {
+	return signingPayload("CloseContractResponse", m.GetSignature(),
+		uuid,
+	)
+}
+
+// SigningPayload returns the message the client node signs for the request
+func (m *ServerRebalanceContractRequest) SigningPayload() []byte {
+	return signingPayload("RebalanceContractRequest", m.GetSignature(),
+		m.GetUuid(),
+		formatFloat(m.GetAssetPrice()),
+		strconv.FormatInt(m.GetAmountSat(), 10),
+		m.GetPayReq(),
+		m.GetAssetPriceDecimal(),
+	)
+}
+
+// SigningPayload returns the message the server node signs for the response
+// to rebalancing the contract with uuid
+func (m *ServerRebalanceContractResponse) SigningPayload(uuid string) []byte {
+	return signingPayload("RebalanceContractResponse", m.GetSignature(),
+		uuid,
+		m.GetPayReq(),
+	)
+}
+
+// SigningPayload returns the message the client node signs to register its
+// push channel
+func (m *PushRegistration) SigningPayload() []byte {
+	return signingPayload("PushRegistration", m.GetSignature(),
+		m.GetNodePubkey(),
+	)
+}
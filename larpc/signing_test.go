@@ -0,0 +1,42 @@
+package larpc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSigningPayloadFieldBoundaries(t *testing.T) {
+	sig := &MessageSignature{NodePubkey: "02ab", Timestamp: 5, Nonce: "cd"}
+
+	tests := []struct {
+		name string
+		a, b []string
+	}{
+		{
+			name: "separator moved between fields",
+			a:    []string{"uuid\nx", "y"},
+			b:    []string{"uuid", "x\ny"},
+		},
+		{
+			name: "field moved into the next",
+			a:    []string{"ab", ""},
+			b:    []string{"", "ab"},
+		},
+		{
+			name: "length moved between fields",
+			a:    []string{"1:a", "b"},
+			b:    []string{"1", "a\n1:b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := signingPayload("Method", sig, test.a...)
+			b := signingPayload("Method", sig, test.b...)
+			if bytes.Equal(a, b) {
+				t.Fatalf("fields %q and %q have the same payload %q", test.a,
+					test.b, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxScale is the most decimals a Decimal can have
+const MaxScale = 18
+
+// Decimal is an exact decimal number of units / 10^scale. The zero value
+// is 0.
+type Decimal struct {
+	units int64
+	scale int32
+}
+
+// OneHundredPercent is the percentage of the full value of an amount
+var OneHundredPercent = NewDecimal(100, 0)
+
+// NewDecimal creates the decimal units / 10^scale. It panics if scale is
+// negative or larger than MaxScale.
+func NewDecimal(units int64, scale int32) Decimal {
+	if scale < 0 || scale > MaxScale {
+		panic(fmt.Sprintf("money: invalid scale %d", scale))
+	}
+
+	return Decimal{units: units, scale: scale}
+}
+
+// ParseDecimal parses a decimal number like "-12.345". Exponents are not
+// supported, and the number may have at most MaxScale decimals.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	var scale int
+	if dot := strings.IndexByte(digits, '.'); dot >= 0 {
+		scale = len(digits) - dot - 1
+		digits = digits[:dot] + digits[dot+1:]
+	}
+
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if scale > MaxScale {
+		return Decimal{}, fmt.Errorf("decimal %q has more than %d decimals",
+			s, MaxScale)
+	}
+
+	units, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if strings.HasPrefix(s, "-") {
+		units.Neg(units)
+	}
+
+	i, err := toInt64(units)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("decimal %q: %w", s, err)
+	}
+
+	return Decimal{units: i, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal, but panics if s is invalid
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// DecimalFromFloat converts f to the shortest decimal that parses back to
+// f, so 0.1 becomes exactly 0.1. It is rounded half to even if it has more
+// than MaxScale decimals.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("invalid decimal %v", f)
+	}
+
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %v", f)
+	}
+
+	scale := int32(0)
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		scale = int32(len(s) - dot - 1)
+	}
+	if scale > MaxScale {
+		scale = MaxScale
+	}
+
+	return fromRat(r, scale, RoundHalfEven)
+}
+
+// ParseDecimalOr parses s, or converts fallback if s is empty. It is used
+// for messages that carry a number both as an exact string and as a float,
+// where older senders only set the float.
+func ParseDecimalOr(s string, fallback float64) (Decimal, error) {
+	if s == "" {
+		return DecimalFromFloat(fallback)
+	}
+
+	return ParseDecimal(s)
+}
+
+// fromRat rounds r to a decimal with scale decimals
+func fromRat(r *big.Rat, scale int32, mode Rounding) (Decimal, error) {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+
+	units, err := toInt64(mode.round(scaled))
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return Decimal{units: units, scale: scale}, nil
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// rat returns d as a rational number
+func (d Decimal) rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(d.units), pow10(d.scale))
+}
+
+// Units returns the unscaled value of d
+func (d Decimal) Units() int64 {
+	return d.units
+}
+
+// Scale returns the number of decimals of d
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or 1 if d is negative, zero or positive
+func (d Decimal) Sign() int {
+	switch {
+	case d.units < 0:
+		return -1
+	case d.units > 0:
+		return 1
+	}
+
+	return 0
+}
+
+// IsZero returns true if d is 0
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+// Cmp returns -1, 0 or 1 if d is less than, equal to or greater than o
+func (d Decimal) Cmp(o Decimal) int {
+	return d.rat().Cmp(o.rat())
+}
+
+// Round rounds d to scale decimals. Rounding to more decimals than d has is
+// always exact, but may overflow.
+func (d Decimal) Round(scale int32, mode Rounding) (Decimal, error) {
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("invalid scale %d", scale)
+	}
+
+	return fromRat(d.rat(), scale, mode)
+}
+
+// Add returns d + o, with the decimals of whichever has more
+func (d Decimal) Add(o Decimal) (Decimal, error) {
+	return fromRat(new(big.Rat).Add(d.rat(), o.rat()), maxScale(d, o), RoundDown)
+}
+
+// Sub returns d - o, with the decimals of whichever has more
+func (d Decimal) Sub(o Decimal) (Decimal, error) {
+	return fromRat(new(big.Rat).Sub(d.rat(), o.rat()), maxScale(d, o), RoundDown)
+}
+
+// Mul returns d * o, with the decimals of both added up. The product is
+// rounded half to even if that is more than MaxScale decimals.
+func (d Decimal) Mul(o Decimal) (Decimal, error) {
+	scale := d.scale + o.scale
+	if scale > MaxScale {
+		scale = MaxScale
+	}
+
+	return fromRat(new(big.Rat).Mul(d.rat(), o.rat()), scale, RoundHalfEven)
+}
+
+// Quo returns d / o rounded to scale decimals
+func (d Decimal) Quo(o Decimal, scale int32, mode Rounding) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, errors.New("division by zero")
+	}
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("invalid scale %d", scale)
+	}
+
+	return fromRat(new(big.Rat).Quo(d.rat(), o.rat()), scale, mode)
+}
+
+func maxScale(d, o Decimal) int32 {
+	if o.scale > d.scale {
+		return o.scale
+	}
+
+	return d.scale
+}
+
+// Float64 returns the float64 nearest to d, for display and for messages
+// that only carry floats
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String formats d with all of its decimals, ie "-0.50"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(big.NewInt(d.units)).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		split := len(digits) - int(d.scale)
+		digits = digits[:split] + "." + digits[split:]
+	}
+
+	if d.units < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
@@ -0,0 +1,237 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "12.345", want: "12.345"},
+		{in: "-12.345", want: "-12.345"},
+		{in: "+1.5", want: "1.5"},
+		{in: "-0.50", want: "-0.50"},
+		{in: ".5", want: "0.5"},
+		{in: "5.", want: "5"},
+		{in: "9223372036854775807", want: "9223372036854775807"},
+		{in: "-9223372036854775808", want: "-9223372036854775808"},
+		{in: "0.000000000000000001", want: "0.000000000000000001"},
+
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "+-1", wantErr: true},
+		{in: "1-", wantErr: true},
+		{in: "1e3", wantErr: true},
+		{in: "1E3", wantErr: true},
+		{in: "-1.5e-3", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: " 1", wantErr: true},
+		{in: "0x10", wantErr: true},
+		{in: "NaN", wantErr: true},
+		{in: "Inf", wantErr: true},
+		{in: "9223372036854775808", wantErr: true},
+		{in: "0.0000000000000000001", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := ParseDecimal(test.in)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if err == nil && got.String() != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecimalFromFloat(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      float64
+		want    string
+		wantErr bool
+	}{
+		{name: "exact", in: 0.1, want: "0.1"},
+		{name: "negative", in: -7100.5, want: "-7100.5"},
+		{name: "integer", in: 10000, want: "10000"},
+		{name: "rounded to max scale", in: 1e-19, want: "0.000000000000000000"},
+		{name: "too large", in: 1e19, wantErr: true},
+		{name: "nan", in: math.NaN(), wantErr: true},
+		{name: "inf", in: math.Inf(-1), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DecimalFromFloat(test.in)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if err == nil && got.String() != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in    string
+		scale int32
+		mode  Rounding
+		want  string
+	}{
+		{in: "1.25", scale: 1, mode: RoundDown, want: "1.2"},
+		{in: "-1.25", scale: 1, mode: RoundDown, want: "-1.2"},
+		{in: "1.21", scale: 1, mode: RoundUp, want: "1.3"},
+		{in: "-1.21", scale: 1, mode: RoundUp, want: "-1.3"},
+		{in: "1.25", scale: 1, mode: RoundHalfUp, want: "1.3"},
+		{in: "-1.25", scale: 1, mode: RoundHalfUp, want: "-1.3"},
+		{in: "1.24", scale: 1, mode: RoundHalfUp, want: "1.2"},
+		{in: "1.25", scale: 1, mode: RoundHalfEven, want: "1.2"},
+		{in: "1.35", scale: 1, mode: RoundHalfEven, want: "1.4"},
+		{in: "-1.25", scale: 1, mode: RoundHalfEven, want: "-1.2"},
+		{in: "1.251", scale: 1, mode: RoundHalfEven, want: "1.3"},
+		{in: "1.2", scale: 3, mode: RoundDown, want: "1.200"},
+		{in: "1.2", scale: 1, mode: RoundUp, want: "1.2"},
+	}
+
+	for _, test := range tests {
+		d := MustParseDecimal(test.in)
+		got, err := d.Round(test.scale, test.mode)
+		if err != nil {
+			t.Errorf("%s to %d decimals: %v", test.in, test.scale, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("%s to %d decimals with mode %d: got %s, want %s",
+				test.in, test.scale, test.mode, got, test.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      func(a, b Decimal) (Decimal, error)
+		a, b    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "add keeps the most decimals",
+			op:   Decimal.Add,
+			a:    "1.5", b: "0.25",
+			want: "1.75",
+		},
+		{
+			name: "sub below zero",
+			op:   Decimal.Sub,
+			a:    "1", b: "1.5",
+			want: "-0.5",
+		},
+		{
+			name: "add overflows",
+			op:   Decimal.Add,
+			a:    "9223372036854775807", b: "1",
+			wantErr: ErrOverflow,
+		},
+		{
+			name: "sub overflows",
+			op:   Decimal.Sub,
+			a:    "-9223372036854775808", b: "1",
+			wantErr: ErrOverflow,
+		},
+		{
+			name: "mul adds up the decimals",
+			op:   Decimal.Mul,
+			a:    "1.5", b: "-0.25",
+			want: "-0.375",
+		},
+		{
+			name: "mul overflows",
+			op:   Decimal.Mul,
+			a:    "4611686018427387904", b: "2",
+			wantErr: ErrOverflow,
+		},
+		{
+			name: "mul overflows with decimals",
+			op:   Decimal.Mul,
+			a:    "100000000000.5", b: "100000000",
+			wantErr: ErrOverflow,
+		},
+		{
+			name: "mul rounds half to even past the max scale",
+			op:   Decimal.Mul,
+			a:    "0.000000001", b: "0.0000000005",
+			want: "0.000000000000000000",
+		},
+		{
+			name: "quo rounds",
+			op: func(a, b Decimal) (Decimal, error) {
+				return a.Quo(b, 2, RoundUp)
+			},
+			a: "1", b: "3",
+			want: "0.34",
+		},
+		{
+			name: "quo rounds negative away from zero",
+			op: func(a, b Decimal) (Decimal, error) {
+				return a.Quo(b, 2, RoundUp)
+			},
+			a: "-1", b: "3",
+			want: "-0.34",
+		},
+		{
+			name: "quo overflows",
+			op: func(a, b Decimal) (Decimal, error) {
+				return a.Quo(b, 0, RoundDown)
+			},
+			a: "9223372036854775807", b: "0.5",
+			wantErr: ErrOverflow,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.op(MustParseDecimal(test.a), MustParseDecimal(test.b))
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if err == nil && got.String() != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestQuoByZero(t *testing.T) {
+	if _, err := MustParseDecimal("1").Quo(Decimal{}, 2, RoundDown); err == nil {
+		t.Fatal("divided by zero")
+	}
+}
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.50", b: "1.5", want: 0},
+		{a: "-1", b: "0.001", want: -1},
+		{a: "10000", b: "9999.99", want: 1},
+	}
+
+	for _, test := range tests {
+		got := MustParseDecimal(test.a).Cmp(MustParseDecimal(test.b))
+		if got != test.want {
+			t.Errorf("%s cmp %s: got %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package money does exact arithmetic on amounts of assets and bitcoin.
+// Amounts of assets and prices are fixed-point decimals, and amounts of
+// bitcoin are integer millisatoshis. Every conversion that can lose precision
+// takes an explicit rounding mode, and every operation checks for overflow.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrOverflow is returned when the result of an operation does not fit in
+// 64 bits
+var ErrOverflow = errors.New("amount overflows")
+
+// Rounding is how a value that can not be represented exactly is rounded
+type Rounding int
+
+const (
+	// RoundDown rounds toward zero
+	RoundDown Rounding = iota
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundHalfUp rounds to the nearest value, and away from zero when
+	// halfway between two values
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest value, and to the even value when
+	// halfway between two values
+	RoundHalfEven
+)
+
+// round rounds r to an integer
+func (m Rounding) round(r *big.Rat) *big.Int {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	switch m {
+	case RoundDown:
+		return quo
+
+	case RoundHalfUp, RoundHalfEven:
+		// compare the remainder to half of the denominator
+		twice := new(big.Int).Abs(rem)
+		twice.Lsh(twice, 1)
+
+		switch twice.Cmp(r.Denom()) {
+		case -1:
+			return quo
+		case 0:
+			if m == RoundHalfEven && quo.Bit(0) == 0 {
+				return quo
+			}
+		}
+	}
+
+	// QuoRem truncates toward zero, so rounding away from zero moves
+	// the quotient in the direction of r
+	return quo.Add(quo, big.NewInt(int64(r.Sign())))
+}
+
+// toInt64 returns i as an int64, or ErrOverflow if it does not fit
+func toInt64(i *big.Int) (int64, error) {
+	if !i.IsInt64() {
+		return 0, ErrOverflow
+	}
+
+	return i.Int64(), nil
+}
+
+// DefaultDecimals is the number of decimals of assets not in assetDecimals
+const DefaultDecimals = 8
+
+// assetDecimals is the number of decimals amounts of an asset are given in
+var assetDecimals = map[string]int32{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"NOK": 2,
+	"JPY": 0,
+}
+
+// Decimals returns the number of decimals amounts of asset are given in
+func Decimals(asset string) int32 {
+	if decimals, ok := assetDecimals[asset]; ok {
+		return decimals
+	}
+
+	return DefaultDecimals
+}
+
+// ParseAmount parses an amount of asset, which may not have more decimals
+// than the asset has. The amount is returned with the decimals of the asset.
+func ParseAmount(asset, s string) (Decimal, error) {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	decimals := Decimals(asset)
+	amount, err := d.Round(decimals, RoundDown)
+	if err != nil {
+		return Decimal{}, err
+	}
+	if amount.Cmp(d) != 0 {
+		return Decimal{}, fmt.Errorf("amount %s has more than the %d "+
+			"decimals of %s", s, decimals, asset)
+	}
+
+	return amount, nil
+}
+
+// AmountFromFloat converts an amount of asset from a float, rounded half to
+// even to the decimals of the asset. It is used for amounts from before they
+// were stored exactly.
+func AmountFromFloat(asset string, f float64) (Decimal, error) {
+	d, err := DecimalFromFloat(f)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return d.Round(Decimals(asset), RoundHalfEven)
+}
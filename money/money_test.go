@@ -0,0 +1,177 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		asset   string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{asset: "USD", in: "10", want: "10.00"},
+		{asset: "USD", in: "0.01", want: "0.01"},
+		{asset: "USD", in: "-1.5", want: "-1.50"},
+		{asset: "USD", in: "0.001", wantErr: true},
+		{asset: "JPY", in: "100", want: "100"},
+		{asset: "JPY", in: "100.5", wantErr: true},
+		{asset: "XAU", in: "0.00000001", want: "0.00000001"},
+		{asset: "USD", in: "1e2", wantErr: true},
+		{asset: "USD", in: "92233720368547758.08", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.asset+" "+test.in, func(t *testing.T) {
+			got, err := ParseAmount(test.asset, test.in)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if err == nil && got.String() != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAmountFromFloat(t *testing.T) {
+	tests := []struct {
+		asset string
+		in    float64
+		want  string
+	}{
+		{asset: "USD", in: 0.1, want: "0.10"},
+		{asset: "USD", in: 0.125, want: "0.12"},
+		{asset: "USD", in: 0.135, want: "0.14"},
+		{asset: "JPY", in: 2.5, want: "2"},
+	}
+
+	for _, test := range tests {
+		got, err := AmountFromFloat(test.asset, test.in)
+		if err != nil {
+			t.Errorf("%v %s: %v", test.in, test.asset, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("%v %s: got %s, want %s", test.in, test.asset, got, test.want)
+		}
+	}
+}
+
+func TestAssetValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  string
+		price   string
+		percent string
+		mode    Rounding
+		want    MilliSatoshi
+		wantErr error
+	}{
+		{
+			name:   "whole contract",
+			amount: "10", price: "10000", percent: "100",
+			mode: RoundDown,
+			want: 100000 * MsatPerSat,
+		},
+		{
+			name:   "margin",
+			amount: "10", price: "10000", percent: "10",
+			mode: RoundDown,
+			want: 10000 * MsatPerSat,
+		},
+		{
+			name:   "rounded down",
+			amount: "1", price: "3", percent: "100",
+			mode: RoundDown,
+			want: 33333333333,
+		},
+		{
+			name:   "rounded up",
+			amount: "1", price: "3", percent: "100",
+			mode: RoundUp,
+			want: 33333333334,
+		},
+		{
+			name:   "rounded half up",
+			amount: "0.00000000001", price: "2", percent: "100",
+			mode: RoundHalfUp,
+			want: 1,
+		},
+		{
+			name:   "rounded half even",
+			amount: "0.00000000001", price: "2", percent: "100",
+			mode: RoundHalfEven,
+			want: 0,
+		},
+		{
+			name:   "negative amount rounds away from zero",
+			amount: "-1", price: "3", percent: "100",
+			mode: RoundUp,
+			want: -33333333334,
+		},
+		{
+			name:   "overflows",
+			amount: "1000000000", price: "0.01", percent: "100",
+			mode:    RoundDown,
+			wantErr: ErrOverflow,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := AssetValue(MustParseDecimal(test.amount),
+				MustParseDecimal(test.price), MustParseDecimal(test.percent),
+				test.mode)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAssetValueInvalidPrice(t *testing.T) {
+	for _, price := range []string{"0", "-10000"} {
+		_, err := AssetValue(MustParseDecimal("10"), MustParseDecimal(price),
+			OneHundredPercent, RoundDown)
+		if err == nil {
+			t.Errorf("got the value at a price of %s", price)
+		}
+	}
+}
+
+func TestMilliSatoshi(t *testing.T) {
+	tests := []struct {
+		msat MilliSatoshi
+		mode Rounding
+		want int64
+	}{
+		{msat: 1500, mode: RoundDown, want: 1},
+		{msat: 1500, mode: RoundUp, want: 2},
+		{msat: 1500, mode: RoundHalfUp, want: 2},
+		{msat: 1500, mode: RoundHalfEven, want: 2},
+		{msat: 2500, mode: RoundHalfEven, want: 2},
+		{msat: -1500, mode: RoundHalfUp, want: -2},
+		{msat: -1001, mode: RoundDown, want: -1},
+		{msat: 1001, mode: RoundUp, want: 2},
+	}
+
+	for _, test := range tests {
+		if got := test.msat.ToSatoshis(test.mode); got != test.want {
+			t.Errorf("%s with mode %d: got %d sats, want %d", test.msat,
+				test.mode, got, test.want)
+		}
+	}
+
+	if _, err := FromSatoshis(9223372036854776); !errors.Is(err, ErrOverflow) {
+		t.Errorf("got error %v converting too many sats, want %v", err, ErrOverflow)
+	}
+	if msat, err := FromSatoshis(-5); err != nil || msat != -5000 {
+		t.Errorf("got %v (%v) for -5 sats, want -5000 msat", msat, err)
+	}
+}
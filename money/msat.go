@@ -0,0 +1,58 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// MsatPerSat is the number of millisatoshis in a satoshi
+	MsatPerSat = 1000
+	// MsatPerBitcoin is the number of millisatoshis in a bitcoin
+	MsatPerBitcoin = 100000000 * MsatPerSat
+)
+
+// MilliSatoshi is an amount of bitcoin in thousandths of a satoshi, the
+// unit lightning payments are made in
+type MilliSatoshi int64
+
+// FromSatoshis converts sat to millisatoshis
+func FromSatoshis(sat int64) (MilliSatoshi, error) {
+	msat, err := toInt64(new(big.Int).Mul(big.NewInt(sat), big.NewInt(MsatPerSat)))
+	if err != nil {
+		return 0, fmt.Errorf("%d sats: %w", sat, err)
+	}
+
+	return MilliSatoshi(msat), nil
+}
+
+// ToSatoshis rounds m to whole satoshis
+func (m MilliSatoshi) ToSatoshis(mode Rounding) int64 {
+	// a satoshi is larger than a millisatoshi, so this can not overflow
+	return mode.round(big.NewRat(int64(m), MsatPerSat)).Int64()
+}
+
+func (m MilliSatoshi) String() string {
+	return fmt.Sprintf("%d msat", int64(m))
+}
+
+// AssetValue returns percent percent of the value of amount of an asset in
+// millisatoshis, when one bitcoin costs price of the asset
+func AssetValue(amount, price, percent Decimal, mode Rounding) (MilliSatoshi, error) {
+	if price.Sign() <= 0 {
+		return 0, errors.New("price must be positive")
+	}
+
+	value := new(big.Rat).Quo(amount.rat(), price.rat())
+	value.Mul(value, big.NewRat(MsatPerBitcoin, 1))
+	value.Mul(value, percent.rat())
+	value.Quo(value, OneHundredPercent.rat())
+
+	msat, err := toInt64(mode.round(value))
+	if err != nil {
+		return 0, fmt.Errorf("%s of %s at %s: %w", percent, amount, price, err)
+	}
+
+	return MilliSatoshi(msat), nil
+}
@@ -0,0 +1,218 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/qct/bitmex-go/swagger"
+
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+const (
+	// DefaultBitmexURL is the address of the bitmex realtime websocket API
+	DefaultBitmexURL = "wss://www.bitmex.com/realtime"
+
+	// DefaultBitmexRESTURL is the address of the bitmex REST API
+	DefaultBitmexRESTURL = swagger.BASE_URL
+
+	// how long we wait for the current prices from the REST API
+	bitmexRESTTimeout = 10 * time.Second
+
+	// bitmex pings us every 5 seconds, if we do not hear anything
+	// for a while we assume the connection is dead
+	bitmexReadTimeout = 30 * time.Second
+)
+
+// DefaultBitmexSymbols maps the bitmex instruments we listen to, to the
+// asset they are priced in
+var DefaultBitmexSymbols = map[string]string{
+	"XBTUSD": "USD",
+}
+
+// BitmexSource is a price source that listens to the instrument table of
+// the bitmex websocket API. The current prices are fetched from the REST API
+// with the bitmex-go client when connecting, and instruments are decoded into
+// its types. bitmex-go has no websocket client, so the websocket is ours.
+type BitmexSource struct {
+	// URL is the websocket address to connect to
+	URL string
+
+	// RESTURL is the base address of the REST API
+	RESTURL string
+
+	// Symbols maps bitmex instrument symbols to the asset they are
+	// priced in, ie XBTUSD -> USD
+	Symbols map[string]string
+}
+
+var _ PriceSource = &BitmexSource{}
+
+// NewBitmexSource creates a bitmex price source using the default url and
+// symbols
+func NewBitmexSource() *BitmexSource {
+	return &BitmexSource{
+		URL:     DefaultBitmexURL,
+		RESTURL: DefaultBitmexRESTURL,
+		Symbols: DefaultBitmexSymbols,
+	}
+}
+
+// Name returns the name of the source
+func (b *BitmexSource) Name() string {
+	return "bitmex"
+}
+
+// bitmexMessage is the format of messages received from the bitmex
+// websocket. Updates only contain the fields that changed, so instruments
+// without a last price are skipped.
+type bitmexMessage struct {
+	Table  string               `json:"table"`
+	Action string               `json:"action"`
+	Data   []swagger.Instrument `json:"data"`
+
+	Error string `json:"error"`
+}
+
+// Run connects to bitmex and sends price updates on updates
+func (b *BitmexSource) Run(ctx context.Context, updates chan<- Price) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, b.subscribeURL(), nil)
+	if err != nil {
+		return fmt.Errorf("could not connect to bitmex: %w", err)
+	}
+	defer conn.Close()
+
+	// close the connection when the context is canceled, which makes
+	// the blocking read below return. done stops the goroutine when the
+	// connection is lost, so it does not outlive the connection.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	log.WithField("url", b.URL).Info("connected to bitmex")
+
+	// the websocket only sends prices once they change, so we start with
+	// the current ones
+	instruments, err := b.currentInstruments()
+	if err != nil {
+		return err
+	}
+	if err := b.sendPrices(ctx, instruments, updates); err != nil {
+		return err
+	}
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(bitmexReadTimeout)); err != nil {
+			return err
+		}
+
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("could not read from bitmex: %w", err)
+		}
+
+		var msg bitmexMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			log.WithError(err).Warn("could not unmarshal bitmex message")
+			continue
+		}
+
+		if msg.Error != "" {
+			return fmt.Errorf("bitmex returned error: %s", msg.Error)
+		}
+
+		if msg.Table != "instrument" {
+			continue
+		}
+
+		if err := b.sendPrices(ctx, msg.Data, updates); err != nil {
+			return err
+		}
+	}
+}
+
+// currentInstruments gets the instruments of all our symbols from the
+// bitmex REST API
+func (b *BitmexSource) currentInstruments() ([]swagger.Instrument, error) {
+	cfg := swagger.NewConfiguration()
+	cfg.BasePath = b.RESTURL
+	cfg.HTTPClient = &http.Client{Timeout: bitmexRESTTimeout}
+	client := swagger.NewAPIClient(cfg)
+
+	var instruments []swagger.Instrument
+	for symbol := range b.Symbols {
+		res, _, err := client.InstrumentApi.InstrumentGet(map[string]interface{}{
+			"symbol": symbol,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not get %s from bitmex: %w", symbol, err)
+		}
+
+		instruments = append(instruments, res...)
+	}
+
+	return instruments, nil
+}
+
+// sendPrices sends the last price of every instrument we listen to on
+// updates
+func (b *BitmexSource) sendPrices(ctx context.Context,
+	instruments []swagger.Instrument, updates chan<- Price) error {
+
+	for _, instrument := range instruments {
+		asset, ok := b.Symbols[instrument.Symbol]
+		if !ok || instrument.LastPrice == 0 {
+			continue
+		}
+
+		// the shortest representation of the float is the price bitmex
+		// sent, so no precision is lost
+		value, err := money.ParseDecimal(
+			strconv.FormatFloat(instrument.LastPrice, 'f', -1, 64))
+		if err != nil {
+			log.WithError(err).WithField("symbol", instrument.Symbol).
+				Warn("ignoring invalid bitmex price")
+			continue
+		}
+
+		timestamp := instrument.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+
+		select {
+		case updates <- Price{
+			Asset:     asset,
+			Value:     value,
+			Timestamp: timestamp,
+		}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// subscribeURL returns the url that subscribes to the instrument
+// table of all our symbols
+func (b *BitmexSource) subscribeURL() string {
+	var topics []string
+	for symbol := range b.Symbols {
+		topics = append(topics, "instrument:"+symbol)
+	}
+
+	return fmt.Sprintf("%s?subscribe=%s", b.URL, strings.Join(topics, ","))
+}
@@ -0,0 +1,293 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// bitmexServer is a websocket server speaking the bitmex realtime API, that
+// also serves instruments from the REST API. Every websocket connection is
+// handed to the next handler in handlers, the last handler is used for all
+// remaining connections.
+type bitmexServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	instruments string
+	handlers    []func(conn *websocket.Conn)
+	connections int
+	subscribed  []string
+}
+
+func newBitmexServer(t *testing.T, handlers ...func(conn *websocket.Conn)) *bitmexServer {
+	s := &bitmexServer{
+		instruments: "[]",
+		handlers:    handlers,
+	}
+
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v1/instrument" {
+				if symbol := r.URL.Query().Get("symbol"); symbol != "XBTUSD" {
+					t.Errorf("got instruments of %q", symbol)
+				}
+
+				s.mu.Lock()
+				instruments := s.instruments
+				s.mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(instruments))
+				return
+			}
+
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Errorf("could not upgrade connection: %v", err)
+				return
+			}
+			defer conn.Close()
+
+			s.mu.Lock()
+			handler := s.handlers[len(s.handlers)-1]
+			if s.connections < len(s.handlers) {
+				handler = s.handlers[s.connections]
+			}
+			s.connections++
+			s.subscribed = append(s.subscribed, r.URL.Query().Get("subscribe"))
+			s.mu.Unlock()
+
+			handler(conn)
+		}))
+
+	return s
+}
+
+func (s *bitmexServer) source() *BitmexSource {
+	return &BitmexSource{
+		URL:     "ws" + strings.TrimPrefix(s.URL, "http"),
+		RESTURL: s.URL + "/api/v1",
+		Symbols: DefaultBitmexSymbols,
+	}
+}
+
+func (s *bitmexServer) numConnections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connections
+}
+
+// send writes messages to conn, and then keeps it open until the client
+// goes away
+func send(messages ...string) func(conn *websocket.Conn) {
+	return func(conn *websocket.Conn) {
+		for _, msg := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendAndClose writes messages to conn and closes it
+func sendAndClose(messages ...string) func(conn *websocket.Conn) {
+	return func(conn *websocket.Conn) {
+		for _, msg := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestBitmexSourcePrices(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// the instruments served by the REST API, if not empty
+		instruments string
+		messages    []string
+		want        []string
+	}{
+		{
+			name:        "current price and update",
+			instruments: `[{"symbol":"XBTUSD","lastPrice":7000.5,"timestamp":"2020-01-02T03:04:00.000Z"}]`,
+			messages: []string{
+				`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","lastPrice":7100,"timestamp":"2020-01-02T03:04:05.000Z"}]}`,
+			},
+			want: []string{"7000.5", "7100"},
+		},
+		{
+			name: "partial and update",
+			messages: []string{
+				`{"table":"instrument","action":"partial","data":[{"symbol":"XBTUSD","lastPrice":7100.5,"timestamp":"2020-01-02T03:04:05.000Z"}]}`,
+				`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","lastPrice":7101,"timestamp":"2020-01-02T03:04:06.000Z"}]}`,
+			},
+			want: []string{"7100.5", "7101"},
+		},
+		{
+			name: "keeps the precision of the price",
+			messages: []string{
+				`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","lastPrice":7100.123456789}]}`,
+			},
+			want: []string{"7100.123456789"},
+		},
+		{
+			name: "ignores irrelevant messages",
+			messages: []string{
+				`{"info":"Welcome to the BitMEX Realtime API."}`,
+				`not json`,
+				`{"table":"trade","action":"insert","data":[{"symbol":"XBTUSD","lastPrice":1}]}`,
+				`{"table":"instrument","action":"update","data":[{"symbol":"ETHUSD","lastPrice":150}]}`,
+				`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","fairPrice":7000}]}`,
+				`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","lastPrice":7200}]}`,
+			},
+			want: []string{"7200"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newBitmexServer(t, send(test.messages...))
+			defer server.Close()
+			if test.instruments != "" {
+				server.instruments = test.instruments
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			updates := make(chan Price)
+			errs := make(chan error, 1)
+			go func() {
+				errs <- server.source().Run(ctx, updates)
+			}()
+
+			for _, want := range test.want {
+				select {
+				case price := <-updates:
+					if price.Asset != "USD" {
+						t.Errorf("got price of %s, want USD", price.Asset)
+					}
+					if got := price.Value.String(); got != want {
+						t.Errorf("got price %s, want %s", got, want)
+					}
+					if price.Timestamp.IsZero() {
+						t.Error("price has no timestamp")
+					}
+				case err := <-errs:
+					t.Fatalf("source stopped: %v", err)
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out waiting for price %s", want)
+				}
+			}
+
+			cancel()
+			select {
+			case <-errs:
+			case <-time.After(5 * time.Second):
+				t.Fatal("source did not stop when the context was canceled")
+			}
+
+			server.mu.Lock()
+			subscribed := server.subscribed[0]
+			server.mu.Unlock()
+			if subscribed != "instrument:XBTUSD" {
+				t.Errorf("subscribed to %q", subscribed)
+			}
+		})
+	}
+}
+
+func TestBitmexSourceStops(t *testing.T) {
+	tests := []struct {
+		name        string
+		instruments string
+		handler     func(conn *websocket.Conn)
+	}{
+		{
+			name: "error message",
+			handler: send(
+				`{"status":400,"error":"Unknown table: instrumnt"}`,
+			),
+		},
+		{
+			name:    "connection closed",
+			handler: sendAndClose(),
+		},
+		{
+			name:        "invalid instruments",
+			instruments: `{"error":"not an array"}`,
+			handler:     send(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newBitmexServer(t, test.handler)
+			defer server.Close()
+			if test.instruments != "" {
+				server.instruments = test.instruments
+			}
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- server.source().Run(context.Background(), make(chan Price))
+			}()
+
+			select {
+			case err := <-errs:
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("source did not stop")
+			}
+		})
+	}
+}
+
+func TestBitmexOracleReconnects(t *testing.T) {
+	server := newBitmexServer(t,
+		sendAndClose(`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","lastPrice":7000}]}`),
+		send(`{"table":"instrument","action":"update","data":[{"symbol":"XBTUSD","lastPrice":8000}]}`),
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o := New(0, server.source())
+	o.Start(ctx)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		price, err := o.Price("USD")
+		if err == nil && price.Value.String() == "8000" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("oracle did not reconnect, price is %v (%v)", price.Value, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if n := server.numConnections(); n != 2 {
+		t.Fatalf("got %d connections, want 2", n)
+	}
+}
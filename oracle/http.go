@@ -0,0 +1,123 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+const (
+	// DefaultPollInterval is how often we poll the price server by default
+	DefaultPollInterval = 10 * time.Second
+
+	// pricesPath is the path on the price server that returns all prices
+	pricesPath = "/prices"
+)
+
+// HTTPSource is a price source that polls a price server over http. The
+// price server is expected to return a json list of prices on the form
+// [{"asset": "USD", "value": 7100.5}], where value is the price of one
+// bitcoin denominated in asset.
+type HTTPSource struct {
+	// Address is the base url of the price server, ie http://127.0.0.1:3001
+	Address string
+
+	// Interval is how often the price server is polled
+	Interval time.Duration
+
+	Client *http.Client
+}
+
+var _ PriceSource = &HTTPSource{}
+
+// NewHTTPSource creates a http price source polling address at the
+// default interval
+func NewHTTPSource(address string) *HTTPSource {
+	return &HTTPSource{
+		Address:  address,
+		Interval: DefaultPollInterval,
+		Client: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// Name returns the name of the source
+func (h *HTTPSource) Name() string {
+	return fmt.Sprintf("priceserver %s", h.Address)
+}
+
+// Run polls the price server until ctx is canceled or a request fails
+func (h *HTTPSource) Run(ctx context.Context, updates chan<- Price) error {
+	ticker := time.NewTicker(h.Interval)
+	defer ticker.Stop()
+
+	for {
+		prices, err := h.fetch(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, price := range prices {
+			select {
+			case updates <- price:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// fetch gets the current prices from the price server
+func (h *HTTPSource) fetch(ctx context.Context) ([]Price, error) {
+	url := strings.TrimSuffix(h.Address, "/") + pricesPath
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("could not get prices: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price server returned status %s", res.Status)
+	}
+
+	var raw []larpc.Price
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("could not decode prices: %w", err)
+	}
+
+	now := time.Now()
+	prices := make([]Price, 0, len(raw))
+	for _, p := range raw {
+		value, err := money.ParseDecimalOr(p.ValueDecimal, p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price of %s: %w", p.Asset, err)
+		}
+
+		prices = append(prices, Price{
+			Asset:     p.Asset,
+			Value:     value,
+			Timestamp: now,
+		})
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPSourceFetch(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "decimal value",
+			status: http.StatusOK,
+			body:   `[{"asset": "USD", "value": 7100.5, "value_decimal": "7100.55"}]`,
+			want:   map[string]string{"USD": "7100.55"},
+		},
+		{
+			name:   "float value",
+			status: http.StatusOK,
+			body:   `[{"asset": "USD", "value": 7100.5}, {"asset": "NOK", "value": 65000}]`,
+			want:   map[string]string{"USD": "7100.5", "NOK": "65000"},
+		},
+		{
+			name:    "invalid decimal",
+			status:  http.StatusOK,
+			body:    `[{"asset": "USD", "value_decimal": "seven"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			status:  http.StatusOK,
+			body:    `{"asset": "USD"`,
+			wantErr: true,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != pricesPath {
+						http.NotFound(w, r)
+						return
+					}
+					w.WriteHeader(test.status)
+					w.Write([]byte(test.body))
+				}))
+			defer server.Close()
+
+			prices, err := NewHTTPSource(server.URL).fetch(context.Background())
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got prices %v", prices)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(prices) != len(test.want) {
+				t.Fatalf("got %d prices, want %d", len(prices), len(test.want))
+			}
+			for _, price := range prices {
+				if got := price.Value.String(); got != test.want[price.Asset] {
+					t.Errorf("price of %s is %s, want %s", price.Asset, got,
+						test.want[price.Asset])
+				}
+				if price.Age() > time.Minute {
+					t.Errorf("price of %s has timestamp %s", price.Asset, price.Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPSourceRunStopsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+	defer server.Close()
+
+	err := NewHTTPSource(server.URL).Run(context.Background(), make(chan Price))
+	if err == nil {
+		t.Fatal("expected Run to return an error")
+	}
+}
@@ -0,0 +1,178 @@
+// Package oracle keeps track of the latest price of every asset we support,
+// as reported by one or more price sources.
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+var log = logrus.New()
+
+var (
+	// ErrNoPrice is returned when we have not received a price for an asset
+	ErrNoPrice = errors.New("no price available")
+	// ErrStalePrice is returned when the latest price of an asset is older
+	// than the max age of the oracle
+	ErrStalePrice = errors.New("price is stale")
+)
+
+const (
+	minBackoff = 1 * time.Second
+	maxBackoff = 1 * time.Minute
+)
+
+// Price is the price of one bitcoin denominated in asset, at a given time
+type Price struct {
+	Asset     string
+	Value     money.Decimal
+	Timestamp time.Time
+}
+
+// Age returns how long ago the price was observed
+func (p Price) Age() time.Duration {
+	return time.Since(p.Timestamp)
+}
+
+// PriceSource is something that is able to produce price updates
+type PriceSource interface {
+	// Name is a human readable name of the source, used for logging
+	Name() string
+
+	// Run connects to the source and sends every price update on updates.
+	// It blocks until ctx is canceled, or the connection to the source
+	// is lost, in which case an error is returned.
+	Run(ctx context.Context, updates chan<- Price) error
+}
+
+// Oracle aggregates the prices from all of its sources, and provides
+// thread safe access to the latest price of each asset
+type Oracle struct {
+	sources []PriceSource
+	maxAge  time.Duration
+
+	mu     sync.RWMutex
+	prices map[string]Price
+}
+
+// New creates a new oracle. A price older than maxAge is considered stale,
+// a maxAge of 0 means prices never go stale.
+func New(maxAge time.Duration, sources ...PriceSource) *Oracle {
+	return &Oracle{
+		sources: sources,
+		maxAge:  maxAge,
+		prices:  make(map[string]Price),
+	}
+}
+
+// Start starts listening to all sources of the oracle. If a source fails
+// it is restarted with an exponential backoff. All sources are stopped
+// when ctx is canceled.
+func (o *Oracle) Start(ctx context.Context) {
+	updates := make(chan Price)
+
+	for _, source := range o.sources {
+		go o.runSource(ctx, source, updates)
+	}
+
+	go func() {
+		for {
+			select {
+			case price := <-updates:
+				o.setPrice(price)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// runSource keeps source running until ctx is canceled
+func (o *Oracle) runSource(ctx context.Context, source PriceSource, updates chan<- Price) {
+	backoff := minBackoff
+
+	for {
+		started := time.Now()
+		err := source.Run(ctx, updates)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// if the source was running for a while, we consider the
+		// connection to have been healthy and reset the backoff
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+
+		log.WithError(err).WithField("source", source.Name()).
+			Warnf("price source stopped, restarting in %s", backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (o *Oracle) setPrice(price Price) {
+	if price.Value.Sign() <= 0 {
+		log.WithField("asset", price.Asset).Warnf("ignoring invalid price %s", price.Value)
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// never let an older update overwrite a newer one
+	if latest, ok := o.prices[price.Asset]; ok && latest.Timestamp.After(price.Timestamp) {
+		return
+	}
+
+	o.prices[price.Asset] = price
+}
+
+// Price returns the latest price of asset. If we do not have a price, or the
+// price is older than the max age of the oracle, an error is returned.
+func (o *Oracle) Price(asset string) (Price, error) {
+	o.mu.RLock()
+	price, ok := o.prices[asset]
+	o.mu.RUnlock()
+
+	if !ok {
+		return Price{}, fmt.Errorf("%w for %s", ErrNoPrice, asset)
+	}
+
+	if o.maxAge != 0 && price.Age() > o.maxAge {
+		return price, fmt.Errorf("%w: price for %s is %s old", ErrStalePrice,
+			asset, price.Age().Round(time.Second))
+	}
+
+	return price, nil
+}
+
+// Prices returns the latest price of every asset we know about, including
+// stale prices
+func (o *Oracle) Prices() map[string]Price {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	prices := make(map[string]Price, len(o.prices))
+	for asset, price := range o.prices {
+		prices[asset] = price
+	}
+
+	return prices
+}
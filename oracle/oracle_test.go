@@ -0,0 +1,95 @@
+package oracle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ArcaneCryptoAS/lassets-client/money"
+)
+
+func TestOraclePrice(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		maxAge  time.Duration
+		updates []Price
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "no price",
+			maxAge:  time.Minute,
+			wantErr: ErrNoPrice,
+		},
+		{
+			name:   "fresh price",
+			maxAge: time.Minute,
+			updates: []Price{
+				{Asset: "USD", Value: money.MustParseDecimal("7100.5"), Timestamp: now},
+			},
+			want: "7100.5",
+		},
+		{
+			name:   "stale price",
+			maxAge: time.Minute,
+			updates: []Price{
+				{Asset: "USD", Value: money.MustParseDecimal("7100.5"),
+					Timestamp: now.Add(-2 * time.Minute)},
+			},
+			want:    "7100.5",
+			wantErr: ErrStalePrice,
+		},
+		{
+			name:   "prices never go stale without max age",
+			maxAge: 0,
+			updates: []Price{
+				{Asset: "USD", Value: money.MustParseDecimal("7100.5"),
+					Timestamp: now.Add(-24 * time.Hour)},
+			},
+			want: "7100.5",
+		},
+		{
+			name:   "older update is ignored",
+			maxAge: time.Minute,
+			updates: []Price{
+				{Asset: "USD", Value: money.MustParseDecimal("7200"), Timestamp: now},
+				{Asset: "USD", Value: money.MustParseDecimal("7100"),
+					Timestamp: now.Add(-time.Second)},
+			},
+			want: "7200",
+		},
+		{
+			name:   "invalid price is ignored",
+			maxAge: time.Minute,
+			updates: []Price{
+				{Asset: "USD", Value: money.MustParseDecimal("7200"), Timestamp: now},
+				{Asset: "USD", Value: money.MustParseDecimal("0"),
+					Timestamp: now.Add(time.Second)},
+			},
+			want: "7200",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := New(test.maxAge)
+			for _, price := range test.updates {
+				o.setPrice(price)
+			}
+
+			price, err := o.Price("USD")
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if test.want == "" {
+				return
+			}
+
+			if got := price.Value.String(); got != test.want {
+				t.Fatalf("got price %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// BoltStore stores records in a bbolt database. Every record is protobuf
+// encoded, contracts are keyed by uuid, and rebalances and payments are kept
+// in a bucket per contract, keyed by id and payment hash.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// OpenBolt opens the bbolt database at path, creating it if it does not
+// exist, and migrates it to the latest schema version
+func OpenBolt(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{
+		Timeout: 1 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not open database: %w", err)
+	}
+
+	if err := createBucketsIfNotExist(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func createBucketsIfNotExist(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{contractsBucket, rebalancesBucket,
+			paymentsBucket, serverBucket, metaBucket} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return fmt.Errorf("could not create bucket: %w", err)
+			}
+		}
+		// add additional buckets here
+		return nil
+	})
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltStore) Update(fn func(tx Tx) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx: tx})
+	})
+}
+
+// view runs fn in a read-only transaction
+func (s *BoltStore) view(fn func(tx boltTx) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx: tx})
+	})
+}
+
+func (s *BoltStore) GetContract(uuid string) (*larpc.ClientContract, error) {
+	var contract *larpc.ClientContract
+	err := s.view(func(tx boltTx) error {
+		var err error
+		contract, err = tx.GetContract(uuid)
+		return err
+	})
+
+	return contract, err
+}
+
+func (s *BoltStore) ListContracts() ([]*larpc.ClientContract, error) {
+	var contracts []*larpc.ClientContract
+	err := s.view(func(tx boltTx) error {
+		var err error
+		contracts, err = tx.ListContracts()
+		return err
+	})
+
+	return contracts, err
+}
+
+func (s *BoltStore) PutContract(contract *larpc.ClientContract) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutContract(contract)
+	})
+}
+
+func (s *BoltStore) DeleteContract(uuid string) error {
+	return s.Update(func(tx Tx) error {
+		return tx.DeleteContract(uuid)
+	})
+}
+
+func (s *BoltStore) LatestRebalance(uuid string) (*larpc.Rebalance, error) {
+	var rebalance *larpc.Rebalance
+	err := s.view(func(tx boltTx) error {
+		var err error
+		rebalance, err = tx.LatestRebalance(uuid)
+		return err
+	})
+
+	return rebalance, err
+}
+
+func (s *BoltStore) PutRebalance(rebalance *larpc.Rebalance) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutRebalance(rebalance)
+	})
+}
+
+func (s *BoltStore) GetPayment(hash string) (*larpc.Payment, error) {
+	var payment *larpc.Payment
+	err := s.view(func(tx boltTx) error {
+		var err error
+		payment, err = tx.GetPayment(hash)
+		return err
+	})
+
+	return payment, err
+}
+
+func (s *BoltStore) ListPayments(uuid string) ([]*larpc.Payment, error) {
+	var payments []*larpc.Payment
+	err := s.view(func(tx boltTx) error {
+		var err error
+		payments, err = tx.ListPayments(uuid)
+		return err
+	})
+
+	return payments, err
+}
+
+func (s *BoltStore) PutPayment(payment *larpc.Payment) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutPayment(payment)
+	})
+}
+
+func (s *BoltStore) GetServerIdentity() (*larpc.ServerIdentity, error) {
+	var identity *larpc.ServerIdentity
+	err := s.view(func(tx boltTx) error {
+		var err error
+		identity, err = tx.GetServerIdentity()
+		return err
+	})
+
+	return identity, err
+}
+
+func (s *BoltStore) PutServerIdentity(identity *larpc.ServerIdentity) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutServerIdentity(identity)
+	})
+}
+
+// boltTx implements Tx on a bbolt transaction
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t boltTx) GetContract(uuid string) (*larpc.ClientContract, error) {
+	contractBytes := t.tx.Bucket(contractsBucket).Get([]byte(uuid))
+	if contractBytes == nil {
+		return nil, ErrContractNotFound
+	}
+
+	var contract larpc.ClientContract
+	if err := decodeRecord(contractBytes, &contract); err != nil {
+		return nil, err
+	}
+
+	return &contract, nil
+}
+
+func (t boltTx) ListContracts() ([]*larpc.ClientContract, error) {
+	var contracts []*larpc.ClientContract
+	err := t.tx.Bucket(contractsBucket).ForEach(func(k, v []byte) error {
+		var contract larpc.ClientContract
+		if err := decodeRecord(v, &contract); err != nil {
+			return err
+		}
+
+		contracts = append(contracts, &contract)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list contracts: %w", err)
+	}
+
+	return contracts, nil
+}
+
+func (t boltTx) PutContract(contract *larpc.ClientContract) error {
+	contractBytes, err := encodeRecord(contract)
+	if err != nil {
+		return err
+	}
+
+	return t.tx.Bucket(contractsBucket).Put([]byte(contract.Uuid), contractBytes)
+}
+
+func (t boltTx) DeleteContract(uuid string) error {
+	return t.tx.Bucket(contractsBucket).Delete([]byte(uuid))
+}
+
+func (t boltTx) LatestRebalance(uuid string) (*larpc.Rebalance, error) {
+	b := t.tx.Bucket(rebalancesBucket).Bucket([]byte(uuid))
+	if b == nil {
+		return nil, nil
+	}
+
+	_, rebalanceBytes := b.Cursor().Last()
+	if rebalanceBytes == nil {
+		return nil, nil
+	}
+
+	var rebalance larpc.Rebalance
+	if err := decodeRecord(rebalanceBytes, &rebalance); err != nil {
+		return nil, fmt.Errorf("could not get rebalance: %w", err)
+	}
+
+	return &rebalance, nil
+}
+
+func (t boltTx) PutRebalance(rebalance *larpc.Rebalance) error {
+	b, err := t.tx.Bucket(rebalancesBucket).CreateBucketIfNotExists(
+		[]byte(rebalance.ContractUuid))
+	if err != nil {
+		return err
+	}
+
+	rebalanceBytes, err := encodeRecord(rebalance)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(itob(rebalance.Id), rebalanceBytes)
+}
+
+func (t boltTx) GetPayment(hash string) (*larpc.Payment, error) {
+	root := t.tx.Bucket(paymentsBucket)
+
+	// payments are kept per contract, so we look in the bucket of every
+	// contract
+	var payment *larpc.Payment
+	err := root.ForEach(func(uuid, _ []byte) error {
+		paymentBytes := root.Bucket(uuid).Get([]byte(hash))
+		if paymentBytes == nil {
+			return nil
+		}
+
+		payment = &larpc.Payment{}
+		return decodeRecord(paymentBytes, payment)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+func (t boltTx) ListPayments(uuid string) ([]*larpc.Payment, error) {
+	var payments []*larpc.Payment
+
+	readBucket := func(b *bolt.Bucket) error {
+		return b.ForEach(func(k, v []byte) error {
+			var payment larpc.Payment
+			if err := decodeRecord(v, &payment); err != nil {
+				return err
+			}
+
+			payments = append(payments, &payment)
+			return nil
+		})
+	}
+
+	root := t.tx.Bucket(paymentsBucket)
+
+	var err error
+	if uuid != "" {
+		if b := root.Bucket([]byte(uuid)); b != nil {
+			err = readBucket(b)
+		}
+	} else {
+		err = root.ForEach(func(k, _ []byte) error {
+			return readBucket(root.Bucket(k))
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list payments: %w", err)
+	}
+
+	sortPayments(payments)
+
+	return payments, nil
+}
+
+func (t boltTx) PutPayment(payment *larpc.Payment) error {
+	b, err := t.tx.Bucket(paymentsBucket).CreateBucketIfNotExists(
+		[]byte(payment.ContractUuid))
+	if err != nil {
+		return err
+	}
+
+	paymentBytes, err := encodeRecord(payment)
+	if err != nil {
+		return err
+	}
+
+	return b.Put([]byte(payment.PaymentHash), paymentBytes)
+}
+
+func (t boltTx) GetServerIdentity() (*larpc.ServerIdentity, error) {
+	identityBytes := t.tx.Bucket(serverBucket).Get(serverIdentityKey)
+	if identityBytes == nil {
+		return nil, nil
+	}
+
+	var identity larpc.ServerIdentity
+	if err := decodeRecord(identityBytes, &identity); err != nil {
+		return nil, fmt.Errorf("could not read server identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+func (t boltTx) PutServerIdentity(identity *larpc.ServerIdentity) error {
+	identityBytes, err := encodeRecord(identity)
+	if err != nil {
+		return err
+	}
+
+	return t.tx.Bucket(serverBucket).Put(serverIdentityKey, identityBytes)
+}
+
+// sortPayments sorts payments by creation time, keeping the order of
+// payments created at the same time
+func sortPayments(payments []*larpc.Payment) {
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].CreatedAt < payments[j].CreatedAt
+	})
+}
@@ -0,0 +1,34 @@
+package store_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+	"github.com/ArcaneCryptoAS/lassets-client/store/storetest"
+)
+
+func TestBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// every test gets its own database in dir
+	var databases int
+	storetest.TestStore(t, func(t *testing.T) store.Store {
+		databases++
+		path := filepath.Join(dir, fmt.Sprintf("laclient-%d.db", databases))
+
+		s, err := store.OpenBolt(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	})
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	contractsBucket  = []byte("contracts")
+	rebalancesBucket = []byte("rebalances")
+	paymentsBucket   = []byte("payments")
+	serverBucket     = []byte("server")
+
+	// metaBucket holds data about the database itself
+	metaBucket = []byte("meta")
+
+	// the key the schema version is stored at in the meta bucket
+	schemaVersionKey = []byte("schema_version")
+
+	// the key the pinned identity of the server is stored at in the server
+	// bucket
+	serverIdentityKey = []byte("identity")
+)
+
+// encodeRecord encodes a record for the database. Records are protobuf
+// encoded, so fields can be added and removed without breaking the records
+// we already have.
+func encodeRecord(record proto.Message) ([]byte, error) {
+	recordBytes, err := proto.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode record: %w", err)
+	}
+
+	return recordBytes, nil
+}
+
+// decodeRecord decodes a record from the database into record
+func decodeRecord(recordBytes []byte, record proto.Message) error {
+	if err := proto.Unmarshal(recordBytes, record); err != nil {
+		return fmt.Errorf("could not decode record: %w", err)
+	}
+
+	return nil
+}
+
+// getSchemaVersion returns the schema version of the database. Databases
+// from before we had versions are version 0.
+func getSchemaVersion(tx *bolt.Tx) uint32 {
+	versionBytes := tx.Bucket(metaBucket).Get(schemaVersionKey)
+	if len(versionBytes) != 4 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(versionBytes)
+}
+
+// putSchemaVersion stores the schema version of the database
+func putSchemaVersion(tx *bolt.Tx, version uint32) error {
+	versionBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(versionBytes, version)
+
+	return tx.Bucket(metaBucket).Put(schemaVersionKey, versionBytes)
+}
+
+// itob encodes v as an 8-byte big endian value, so keys sort in order
+func itob(v int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(v))
+	return b
+}
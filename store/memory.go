@@ -0,0 +1,312 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// MemoryStore keeps all records in memory, and loses them when the process
+// exits. It is meant for tests, and for trying out the daemon.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	state *memoryState
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		state: &memoryState{
+			contracts:  make(map[string]*larpc.ClientContract),
+			rebalances: make(map[string]map[int64]*larpc.Rebalance),
+			payments:   make(map[string]map[string]*larpc.Payment),
+		},
+	}
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// Update runs fn on a copy of the records, which replaces the records if fn
+// succeeds
+func (s *MemoryStore) Update(fn func(tx Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state.copy()
+	if err := fn(state); err != nil {
+		return err
+	}
+
+	s.state = state
+	return nil
+}
+
+// view runs fn on the records, which it may not modify
+func (s *MemoryStore) view(fn func(tx *memoryState) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return fn(s.state)
+}
+
+func (s *MemoryStore) GetContract(uuid string) (*larpc.ClientContract, error) {
+	var contract *larpc.ClientContract
+	err := s.view(func(tx *memoryState) error {
+		var err error
+		contract, err = tx.GetContract(uuid)
+		return err
+	})
+
+	return contract, err
+}
+
+func (s *MemoryStore) ListContracts() ([]*larpc.ClientContract, error) {
+	var contracts []*larpc.ClientContract
+	err := s.view(func(tx *memoryState) error {
+		var err error
+		contracts, err = tx.ListContracts()
+		return err
+	})
+
+	return contracts, err
+}
+
+func (s *MemoryStore) PutContract(contract *larpc.ClientContract) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutContract(contract)
+	})
+}
+
+func (s *MemoryStore) DeleteContract(uuid string) error {
+	return s.Update(func(tx Tx) error {
+		return tx.DeleteContract(uuid)
+	})
+}
+
+func (s *MemoryStore) LatestRebalance(uuid string) (*larpc.Rebalance, error) {
+	var rebalance *larpc.Rebalance
+	err := s.view(func(tx *memoryState) error {
+		var err error
+		rebalance, err = tx.LatestRebalance(uuid)
+		return err
+	})
+
+	return rebalance, err
+}
+
+func (s *MemoryStore) PutRebalance(rebalance *larpc.Rebalance) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutRebalance(rebalance)
+	})
+}
+
+func (s *MemoryStore) GetPayment(hash string) (*larpc.Payment, error) {
+	var payment *larpc.Payment
+	err := s.view(func(tx *memoryState) error {
+		var err error
+		payment, err = tx.GetPayment(hash)
+		return err
+	})
+
+	return payment, err
+}
+
+func (s *MemoryStore) ListPayments(uuid string) ([]*larpc.Payment, error) {
+	var payments []*larpc.Payment
+	err := s.view(func(tx *memoryState) error {
+		var err error
+		payments, err = tx.ListPayments(uuid)
+		return err
+	})
+
+	return payments, err
+}
+
+func (s *MemoryStore) PutPayment(payment *larpc.Payment) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutPayment(payment)
+	})
+}
+
+func (s *MemoryStore) GetServerIdentity() (*larpc.ServerIdentity, error) {
+	var identity *larpc.ServerIdentity
+	err := s.view(func(tx *memoryState) error {
+		var err error
+		identity, err = tx.GetServerIdentity()
+		return err
+	})
+
+	return identity, err
+}
+
+func (s *MemoryStore) PutServerIdentity(identity *larpc.ServerIdentity) error {
+	return s.Update(func(tx Tx) error {
+		return tx.PutServerIdentity(identity)
+	})
+}
+
+// memoryState holds the records of a MemoryStore, and implements Tx on
+// them. Records are cloned going in and out, so callers never share them
+// with the store.
+type memoryState struct {
+	contracts map[string]*larpc.ClientContract
+	// rebalances by contract uuid and id
+	rebalances map[string]map[int64]*larpc.Rebalance
+	// payments by contract uuid and payment hash
+	payments map[string]map[string]*larpc.Payment
+	identity *larpc.ServerIdentity
+}
+
+// copy returns a copy of the state that can be modified without changing
+// the original. The records themselves are never modified, so they are
+// shared.
+func (m *memoryState) copy() *memoryState {
+	c := &memoryState{
+		contracts:  make(map[string]*larpc.ClientContract, len(m.contracts)),
+		rebalances: make(map[string]map[int64]*larpc.Rebalance, len(m.rebalances)),
+		payments:   make(map[string]map[string]*larpc.Payment, len(m.payments)),
+		identity:   m.identity,
+	}
+
+	for uuid, contract := range m.contracts {
+		c.contracts[uuid] = contract
+	}
+	for uuid, rebalances := range m.rebalances {
+		c.rebalances[uuid] = make(map[int64]*larpc.Rebalance, len(rebalances))
+		for id, rebalance := range rebalances {
+			c.rebalances[uuid][id] = rebalance
+		}
+	}
+	for uuid, payments := range m.payments {
+		c.payments[uuid] = make(map[string]*larpc.Payment, len(payments))
+		for hash, payment := range payments {
+			c.payments[uuid][hash] = payment
+		}
+	}
+
+	return c
+}
+
+func (m *memoryState) GetContract(uuid string) (*larpc.ClientContract, error) {
+	contract, ok := m.contracts[uuid]
+	if !ok {
+		return nil, ErrContractNotFound
+	}
+
+	return proto.Clone(contract).(*larpc.ClientContract), nil
+}
+
+func (m *memoryState) ListContracts() ([]*larpc.ClientContract, error) {
+	var contracts []*larpc.ClientContract
+	for _, contract := range m.contracts {
+		contracts = append(contracts, proto.Clone(contract).(*larpc.ClientContract))
+	}
+
+	// in the order bbolt lists them
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].Uuid < contracts[j].Uuid
+	})
+
+	return contracts, nil
+}
+
+func (m *memoryState) PutContract(contract *larpc.ClientContract) error {
+	m.contracts[contract.Uuid] = proto.Clone(contract).(*larpc.ClientContract)
+	return nil
+}
+
+func (m *memoryState) DeleteContract(uuid string) error {
+	delete(m.contracts, uuid)
+	return nil
+}
+
+func (m *memoryState) LatestRebalance(uuid string) (*larpc.Rebalance, error) {
+	var latest *larpc.Rebalance
+	for _, rebalance := range m.rebalances[uuid] {
+		if latest == nil || rebalance.Id > latest.Id {
+			latest = rebalance
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	return proto.Clone(latest).(*larpc.Rebalance), nil
+}
+
+func (m *memoryState) PutRebalance(rebalance *larpc.Rebalance) error {
+	rebalances, ok := m.rebalances[rebalance.ContractUuid]
+	if !ok {
+		rebalances = make(map[int64]*larpc.Rebalance)
+		m.rebalances[rebalance.ContractUuid] = rebalances
+	}
+
+	rebalances[rebalance.Id] = proto.Clone(rebalance).(*larpc.Rebalance)
+	return nil
+}
+
+func (m *memoryState) GetPayment(hash string) (*larpc.Payment, error) {
+	for _, payments := range m.payments {
+		if payment, ok := payments[hash]; ok {
+			return proto.Clone(payment).(*larpc.Payment), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *memoryState) ListPayments(uuid string) ([]*larpc.Payment, error) {
+	var payments []*larpc.Payment
+	for contractUUID, contractPayments := range m.payments {
+		if uuid != "" && contractUUID != uuid {
+			continue
+		}
+
+		for _, payment := range contractPayments {
+			payments = append(payments, proto.Clone(payment).(*larpc.Payment))
+		}
+	}
+
+	// in the order bbolt lists them, by contract and hash, before sorting
+	// them by creation time
+	sort.Slice(payments, func(i, j int) bool {
+		if payments[i].ContractUuid != payments[j].ContractUuid {
+			return payments[i].ContractUuid < payments[j].ContractUuid
+		}
+		return payments[i].PaymentHash < payments[j].PaymentHash
+	})
+	sortPayments(payments)
+
+	return payments, nil
+}
+
+func (m *memoryState) PutPayment(payment *larpc.Payment) error {
+	payments, ok := m.payments[payment.ContractUuid]
+	if !ok {
+		payments = make(map[string]*larpc.Payment)
+		m.payments[payment.ContractUuid] = payments
+	}
+
+	payments[payment.PaymentHash] = proto.Clone(payment).(*larpc.Payment)
+	return nil
+}
+
+func (m *memoryState) GetServerIdentity() (*larpc.ServerIdentity, error) {
+	if m.identity == nil {
+		return nil, nil
+	}
+
+	return proto.Clone(m.identity).(*larpc.ServerIdentity), nil
+}
+
+func (m *memoryState) PutServerIdentity(identity *larpc.ServerIdentity) error {
+	m.identity = proto.Clone(identity).(*larpc.ServerIdentity)
+	return nil
+}
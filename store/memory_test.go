@@ -0,0 +1,14 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+	"github.com/ArcaneCryptoAS/lassets-client/store/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.TestStore(t, func(t *testing.T) store.Store {
+		return store.NewMemoryStore()
+	})
+}
@@ -0,0 +1,237 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// migration upgrades the database from the previous schema version
+type migration struct {
+	description string
+	migrate     func(tx *bolt.Tx) error
+}
+
+// migrations lists every migration in the order they are run. Migration i
+// upgrades the database to schema version i+1. Migrations are never removed
+// or reordered, new ones are appended.
+var migrations = []migration{
+	{
+		description: "give contracts a status",
+		migrate:     upgradeContractStatuses,
+	},
+	{
+		description: "encode records with protobuf instead of json",
+		migrate:     encodeRecordsWithProto,
+	},
+}
+
+// legacyUnmarshaler decodes records saved as json. Some versions of lacd
+// saved enums by name instead of by number, and records can have fields
+// that have since been removed, ie invoices_paid.
+var legacyUnmarshaler = jsonpb.Unmarshaler{AllowUnknownFields: true}
+
+// legacyMarshaler encodes records the way the json migrations read them
+var legacyMarshaler = jsonpb.Marshaler{OrigName: true}
+
+// latestSchemaVersion is the schema version of a fully migrated database
+var latestSchemaVersion = uint32(len(migrations))
+
+// migrateDB runs all migrations the database has not had yet. Each
+// migration runs in its own transaction together with bumping the schema
+// version, so an interrupted migration is run again on the next start.
+func migrateDB(db *bolt.DB) error {
+	var version uint32
+	err := db.View(func(tx *bolt.Tx) error {
+		version = getSchemaVersion(tx)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if version > latestSchemaVersion {
+		return fmt.Errorf("database has schema version %d, but this version "+
+			"of lacd only supports up to %d", version, latestSchemaVersion)
+	}
+
+	for ; version < latestSchemaVersion; version++ {
+		m := migrations[version]
+
+		log.Infof("migrating database to version %d: %s", version+1, m.description)
+
+		err := db.Update(func(tx *bolt.Tx) error {
+			if err := m.migrate(tx); err != nil {
+				return err
+			}
+
+			return putSchemaVersion(tx, version+1)
+		})
+		if err != nil {
+			return fmt.Errorf("could not migrate database to version %d: %w",
+				version+1, err)
+		}
+	}
+
+	return nil
+}
+
+// encodeRecordsWithProto re-encodes every record saved as json with
+// protobuf
+func encodeRecordsWithProto(tx *bolt.Tx) error {
+	err := jsonToProto(tx.Bucket(contractsBucket), func() proto.Message {
+		return &larpc.ClientContract{}
+	})
+	if err != nil {
+		return fmt.Errorf("contracts: %w", err)
+	}
+
+	err = jsonToProto(tx.Bucket(rebalancesBucket), func() proto.Message {
+		return &larpc.Rebalance{}
+	})
+	if err != nil {
+		return fmt.Errorf("rebalances: %w", err)
+	}
+
+	err = jsonToProto(tx.Bucket(paymentsBucket), func() proto.Message {
+		return &larpc.Payment{}
+	})
+	if err != nil {
+		return fmt.Errorf("payments: %w", err)
+	}
+
+	b := tx.Bucket(serverBucket)
+	identityBytes := b.Get(serverIdentityKey)
+	if identityBytes == nil {
+		return nil
+	}
+
+	identityBytes, err = reencode(identityBytes, &larpc.ServerIdentity{})
+	if err != nil {
+		return fmt.Errorf("server identity: %w", err)
+	}
+
+	return b.Put(serverIdentityKey, identityBytes)
+}
+
+// jsonToProto re-encodes every record in b from json to protobuf. Records
+// in nested buckets, ie the payments of each contract, are re-encoded too.
+func jsonToProto(b *bolt.Bucket, newRecord func() proto.Message) error {
+	// the bucket can not be modified while iterating over it, so we
+	// collect the re-encoded records first
+	records := make(map[string][]byte)
+	var nested [][]byte
+
+	err := b.ForEach(func(k, v []byte) error {
+		if v == nil {
+			nested = append(nested, k)
+			return nil
+		}
+
+		recordBytes, err := reencode(v, newRecord())
+		if err != nil {
+			return fmt.Errorf("%x: %w", k, err)
+		}
+
+		records[string(k)] = recordBytes
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for k, recordBytes := range records {
+		if err := b.Put([]byte(k), recordBytes); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range nested {
+		if err := jsonToProto(b.Bucket(k), newRecord); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reencode decodes jsonBytes into record, and encodes it with protobuf
+func reencode(jsonBytes []byte, record proto.Message) ([]byte, error) {
+	err := legacyUnmarshaler.Unmarshal(bytes.NewReader(jsonBytes), record)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode json: %w", err)
+	}
+
+	return encodeRecord(record)
+}
+
+// upgradeContractStatuses gives a status to contracts saved before we had
+// statuses, based on the invoices_paid flag they were saved with. Contracts
+// were still saved as json then.
+func upgradeContractStatuses(tx *bolt.Tx) error {
+	b := tx.Bucket(contractsBucket)
+
+	// the bucket can not be modified while iterating over it, so we
+	// collect the upgraded contracts first
+	upgraded := make(map[string][]byte)
+
+	err := b.ForEach(func(k, v []byte) error {
+		var legacy struct {
+			InvoicesPaid  bool              `json:"invoices_paid"`
+			StatusHistory []json.RawMessage `json:"status_history"`
+		}
+		if err := json.Unmarshal(v, &legacy); err != nil {
+			return err
+		}
+
+		if len(legacy.StatusHistory) != 0 {
+			return nil
+		}
+
+		var contract larpc.ClientContract
+		err := legacyUnmarshaler.Unmarshal(bytes.NewReader(v), &contract)
+		if err != nil {
+			return err
+		}
+
+		status := larpc.ContractStatus_CREATED
+		if legacy.InvoicesPaid {
+			status = larpc.ContractStatus_OPEN
+			contract.MarginPaid = true
+			contract.InitPaid = contract.ContractType == larpc.ContractType_FUNDED
+		}
+
+		contract.Status = status
+		contract.StatusHistory = []*larpc.ContractStatusChange{{
+			Status:    status,
+			Timestamp: time.Now().Unix(),
+			Reason:    "upgraded from invoices_paid",
+		}}
+
+		contractJSON, err := legacyMarshaler.MarshalToString(&contract)
+		if err != nil {
+			return err
+		}
+
+		upgraded[string(k)] = []byte(contractJSON)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for uuid, contractBytes := range upgraded {
+		if err := b.Put([]byte(uuid), contractBytes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
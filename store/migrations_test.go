@@ -0,0 +1,380 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+// openTestDB opens an empty bbolt database in a temporary directory. The
+// returned function closes and removes it.
+func openTestDB(t *testing.T) (*bolt.DB, func()) {
+	dir, err := ioutil.TempDir("", "lacstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dir, "laclient.db"), 0600, nil)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// seedLegacyDB writes records the way lacd saved them before the database
+// had a schema version, ie as json
+func seedLegacyDB(t *testing.T, db *bolt.DB) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		contracts, err := tx.CreateBucketIfNotExists(contractsBucket)
+		if err != nil {
+			return err
+		}
+
+		// saved before contracts had a status
+		err = contracts.Put([]byte("paid"), []byte(`{"uuid":"paid",`+
+			`"asset":"USD","amount":0.1,"amount_sat":1000,"invoices_paid":true}`))
+		if err != nil {
+			return err
+		}
+		err = contracts.Put([]byte("unpaid"), []byte(`{"uuid":"unpaid",`+
+			`"asset":"EUR","amount":5,"amount_sat":50000}`))
+		if err != nil {
+			return err
+		}
+
+		// saved before contracts had a status, with the enums by name
+		err = contracts.Put([]byte("byname"), []byte(`{"uuid":"byname",`+
+			`"asset":"USD","amount":2,"amount_sat":20000,`+
+			`"contract_type":"UNFUNDED","invoices_paid":true}`))
+		if err != nil {
+			return err
+		}
+
+		// saved with the status by name
+		err = contracts.Put([]byte("closing"), []byte(`{"uuid":"closing",`+
+			`"asset":"USD","amount":1,"amount_sat":10000,"status":"CLOSING",`+
+			`"status_history":[{"status":"OPEN","timestamp":5},`+
+			`{"status":"CLOSING","timestamp":6,"reason":"closed by user"}]}`))
+		if err != nil {
+			return err
+		}
+
+		rebalances, err := tx.CreateBucketIfNotExists(rebalancesBucket)
+		if err != nil {
+			return err
+		}
+		closingRebalances, err := rebalances.CreateBucket([]byte("closing"))
+		if err != nil {
+			return err
+		}
+		err = closingRebalances.Put(itob(1), []byte(`{"contract_uuid":"closing",`+
+			`"id":1,"asset_price":10000,"amount_sat":-5,"state":"REBALANCE_COMPLETED"}`))
+		if err != nil {
+			return err
+		}
+
+		payments, err := tx.CreateBucketIfNotExists(paymentsBucket)
+		if err != nil {
+			return err
+		}
+		closingPayments, err := payments.CreateBucket([]byte("closing"))
+		if err != nil {
+			return err
+		}
+		err = closingPayments.Put([]byte("aa"), []byte(`{"contract_uuid":"closing",`+
+			`"amount_sat":5,"payment_hash":"aa","type":"REBALANCE"}`))
+		if err != nil {
+			return err
+		}
+
+		server, err := tx.CreateBucketIfNotExists(serverBucket)
+		if err != nil {
+			return err
+		}
+		return server.Put(serverIdentityKey, []byte(`{"pubkey":"02ab","pinned_at":7}`))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := createBucketsIfNotExist(db); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// dumpDB returns every key and value in the database, so two states of the
+// database can be compared
+func dumpDB(t *testing.T, db *bolt.DB) map[string][]byte {
+	dump := make(map[string][]byte)
+
+	var walk func(prefix string, b *bolt.Bucket) error
+	walk = func(prefix string, b *bolt.Bucket) error {
+		return b.ForEach(func(k, v []byte) error {
+			key := prefix + "/" + string(k)
+			if v == nil {
+				return walk(key, b.Bucket(k))
+			}
+
+			dump[key] = append([]byte(nil), v...)
+			return nil
+		})
+	}
+
+	err := db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return walk(string(name), b)
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dump
+}
+
+func TestMigrateLegacyDB(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	seedLegacyDB(t, db)
+
+	if err := migrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	err := db.View(func(tx *bolt.Tx) error {
+		if version := getSchemaVersion(tx); version != latestSchemaVersion {
+			t.Errorf("got schema version %d, want %d", version, latestSchemaVersion)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &BoltStore{db: db}
+
+	contractTests := []struct {
+		uuid           string
+		wantStatus     larpc.ContractStatus
+		wantMarginPaid bool
+		wantInitPaid   bool
+		wantAmountSat  int64
+		wantHistory    []larpc.ContractStatus
+	}{
+		{
+			uuid:           "paid",
+			wantStatus:     larpc.ContractStatus_OPEN,
+			wantMarginPaid: true,
+			wantInitPaid:   true,
+			wantAmountSat:  1000,
+			wantHistory:    []larpc.ContractStatus{larpc.ContractStatus_OPEN},
+		},
+		{
+			uuid:           "byname",
+			wantStatus:     larpc.ContractStatus_OPEN,
+			wantMarginPaid: true,
+			wantAmountSat:  20000,
+			wantHistory:    []larpc.ContractStatus{larpc.ContractStatus_OPEN},
+		},
+		{
+			uuid:          "unpaid",
+			wantStatus:    larpc.ContractStatus_CREATED,
+			wantAmountSat: 50000,
+			wantHistory:   []larpc.ContractStatus{larpc.ContractStatus_CREATED},
+		},
+		{
+			uuid:          "closing",
+			wantStatus:    larpc.ContractStatus_CLOSING,
+			wantAmountSat: 10000,
+			wantHistory: []larpc.ContractStatus{larpc.ContractStatus_OPEN,
+				larpc.ContractStatus_CLOSING},
+		},
+	}
+
+	for _, test := range contractTests {
+		t.Run(test.uuid, func(t *testing.T) {
+			contract, err := s.GetContract(test.uuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if contract.Uuid != test.uuid {
+				t.Errorf("got uuid %q, want %q", contract.Uuid, test.uuid)
+			}
+			if contract.Status != test.wantStatus {
+				t.Errorf("got status %s, want %s", contract.Status, test.wantStatus)
+			}
+			if contract.MarginPaid != test.wantMarginPaid {
+				t.Errorf("got margin paid %t, want %t", contract.MarginPaid,
+					test.wantMarginPaid)
+			}
+			if contract.InitPaid != test.wantInitPaid {
+				t.Errorf("got init paid %t, want %t", contract.InitPaid,
+					test.wantInitPaid)
+			}
+			if contract.AmountSat != test.wantAmountSat {
+				t.Errorf("got %d sats, want %d", contract.AmountSat,
+					test.wantAmountSat)
+			}
+
+			if len(contract.StatusHistory) != len(test.wantHistory) {
+				t.Fatalf("got %d status changes, want %d",
+					len(contract.StatusHistory), len(test.wantHistory))
+			}
+			for i, change := range contract.StatusHistory {
+				if change.Status != test.wantHistory[i] {
+					t.Errorf("status change %d is %s, want %s", i,
+						change.Status, test.wantHistory[i])
+				}
+			}
+		})
+	}
+
+	rebalance, err := s.LatestRebalance("closing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRebalance := &larpc.Rebalance{
+		ContractUuid: "closing",
+		Id:           1,
+		AssetPrice:   10000,
+		AmountSat:    -5,
+		State:        larpc.RebalanceState_REBALANCE_COMPLETED,
+	}
+	if !proto.Equal(rebalance, wantRebalance) {
+		t.Errorf("got rebalance %v, want %v", rebalance, wantRebalance)
+	}
+
+	payment, err := s.GetPayment("aa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPayment := &larpc.Payment{
+		ContractUuid: "closing",
+		AmountSat:    5,
+		PaymentHash:  "aa",
+		Type:         larpc.PaymentType_REBALANCE,
+	}
+	if !proto.Equal(payment, wantPayment) {
+		t.Errorf("got payment %v, want %v", payment, wantPayment)
+	}
+
+	identity, err := s.GetServerIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIdentity := &larpc.ServerIdentity{Pubkey: "02ab", PinnedAt: 7}
+	if !proto.Equal(identity, wantIdentity) {
+		t.Errorf("got server identity %v, want %v", identity, wantIdentity)
+	}
+}
+
+func TestUpgradeContractStatuses(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	seedLegacyDB(t, db)
+
+	// the upgraded contracts are still json, and must be encoded like
+	// the records lacd saved as json, with enums by name
+	var record map[string]interface{}
+	err := db.Update(func(tx *bolt.Tx) error {
+		if err := upgradeContractStatuses(tx); err != nil {
+			return err
+		}
+
+		v := tx.Bucket(contractsBucket).Get([]byte("byname"))
+		return json.Unmarshal(v, &record)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"uuid":          "byname",
+		"status":        "OPEN",
+		"contract_type": "UNFUNDED",
+	}
+	for field, value := range want {
+		if record[field] != value {
+			t.Errorf("got %s %v, want %q", field, record[field], value)
+		}
+	}
+}
+
+func TestMigrateTwice(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	seedLegacyDB(t, db)
+
+	if err := migrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+	migrated := dumpDB(t, db)
+
+	if err := migrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+	again := dumpDB(t, db)
+
+	if len(again) != len(migrated) {
+		t.Fatalf("got %d records after migrating again, want %d", len(again),
+			len(migrated))
+	}
+	for key, value := range migrated {
+		if !bytes.Equal(again[key], value) {
+			t.Errorf("%s changed when migrating again", key)
+		}
+	}
+}
+
+func TestMigrateNewerDB(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	if err := createBucketsIfNotExist(db); err != nil {
+		t.Fatal(err)
+	}
+
+	newer := latestSchemaVersion + 1
+	err := db.Update(func(tx *bolt.Tx) error {
+		return putSchemaVersion(tx, newer)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = migrateDB(db)
+	if err == nil {
+		t.Fatal("migrated a database with a newer schema version")
+	}
+	if !strings.Contains(err.Error(), "schema version") {
+		t.Errorf("got error %q, want it to mention the schema version", err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if version := getSchemaVersion(tx); version != newer {
+			t.Errorf("got schema version %d, want it left at %d", version, newer)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
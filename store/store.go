@@ -0,0 +1,90 @@
+// Package store persists the contracts, rebalances and payments of the
+// client, and what it knows about the asset server. Store is implemented on
+// top of bbolt by BoltStore, and in memory by MemoryStore.
+package store
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+)
+
+var log = logrus.New()
+
+// ErrContractNotFound is returned when a contract does not exist
+var ErrContractNotFound = errors.New("contract not found")
+
+// ContractStore stores contracts and their rebalances
+type ContractStore interface {
+	// GetContract returns the contract with the given uuid, or
+	// ErrContractNotFound
+	GetContract(uuid string) (*larpc.ClientContract, error)
+
+	// ListContracts returns all contracts
+	ListContracts() ([]*larpc.ClientContract, error)
+
+	// PutContract stores a contract, replacing any contract with the same
+	// uuid
+	PutContract(contract *larpc.ClientContract) error
+
+	// DeleteContract removes a contract. Its rebalances and payments are
+	// kept.
+	DeleteContract(uuid string) error
+
+	// LatestRebalance returns the rebalance of a contract with the highest
+	// id, or nil if the contract has never been rebalanced
+	LatestRebalance(uuid string) (*larpc.Rebalance, error)
+
+	// PutRebalance stores a rebalance, replacing any rebalance of the same
+	// contract with the same id
+	PutRebalance(rebalance *larpc.Rebalance) error
+}
+
+// PaymentStore stores the payments of contracts
+type PaymentStore interface {
+	// GetPayment returns the payment with the given hash, or nil if there
+	// is none
+	GetPayment(hash string) (*larpc.Payment, error)
+
+	// ListPayments returns all payments of the contract with the given
+	// uuid, or of all contracts if uuid is empty, sorted by creation time
+	ListPayments(uuid string) ([]*larpc.Payment, error)
+
+	// PutPayment stores a payment, replacing any payment of the same
+	// contract with the same hash
+	PutPayment(payment *larpc.Payment) error
+}
+
+// ServerStore stores what we know about the asset server
+type ServerStore interface {
+	// GetServerIdentity returns the pinned identity of the server, or nil
+	// if none was ever pinned
+	GetServerIdentity() (*larpc.ServerIdentity, error)
+
+	// PutServerIdentity stores the pinned identity of the server
+	PutServerIdentity(identity *larpc.ServerIdentity) error
+}
+
+// Tx reads and writes records within a transaction. Records returned by it
+// are copies, and can be modified freely.
+type Tx interface {
+	ContractStore
+	PaymentStore
+	ServerStore
+}
+
+// Store reads and writes records. Every method of Tx called on a Store runs
+// in a transaction of its own.
+type Store interface {
+	Tx
+
+	// Update runs fn in a single transaction. Either all records fn writes
+	// are stored, or none are if fn returns an error. fn must only use tx,
+	// not the store itself.
+	Update(fn func(tx Tx) error) error
+
+	// Close releases the resources of the store
+	Close() error
+}
@@ -0,0 +1,377 @@
+// Package storetest is a conformance suite every implementation of
+// store.Store must pass, so they can be used interchangeably.
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/ArcaneCryptoAS/lassets-client/larpc"
+	"github.com/ArcaneCryptoAS/lassets-client/store"
+)
+
+// TestStore runs the suite against the stores created by newStore. Every
+// test gets a new, empty store, which is closed when the test ends.
+func TestStore(t *testing.T, newStore func(t *testing.T) store.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s store.Store)
+	}{
+		{"contracts", testContracts},
+		{"delete contract", testDeleteContract},
+		{"rebalances", testRebalances},
+		{"payments", testPayments},
+		{"server identity", testServerIdentity},
+		{"records are copies", testCopies},
+		{"update commits", testUpdateCommits},
+		{"update rolls back", testUpdateRollsBack},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			s := newStore(t)
+			defer s.Close()
+
+			test.test(t, s)
+		})
+	}
+}
+
+func contract(uuid string) *larpc.ClientContract {
+	return &larpc.ClientContract{
+		Uuid:          uuid,
+		Asset:         "USD",
+		AmountDecimal: "0.10",
+		AmountSat:     1000,
+		Status:        larpc.ContractStatus_OPEN,
+		StatusHistory: []*larpc.ContractStatusChange{{
+			Status:    larpc.ContractStatus_OPEN,
+			Timestamp: 1,
+		}},
+	}
+}
+
+func payment(uuid, hash string, createdAt int64) *larpc.Payment {
+	return &larpc.Payment{
+		ContractUuid: uuid,
+		PaymentHash:  hash,
+		AmountSat:    100,
+		CreatedAt:    createdAt,
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertEqual(t *testing.T, got, want proto.Message) {
+	t.Helper()
+
+	if !proto.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func testContracts(t *testing.T, s store.Store) {
+	_, err := s.GetContract("a")
+	if !errors.Is(err, store.ErrContractNotFound) {
+		t.Fatalf("got error %v for a missing contract, want %v",
+			err, store.ErrContractNotFound)
+	}
+
+	contracts, err := s.ListContracts()
+	must(t, err)
+	if len(contracts) != 0 {
+		t.Fatalf("empty store lists %d contracts", len(contracts))
+	}
+
+	must(t, s.PutContract(contract("b")))
+	must(t, s.PutContract(contract("a")))
+
+	got, err := s.GetContract("a")
+	must(t, err)
+	assertEqual(t, got, contract("a"))
+
+	// putting a contract again replaces it
+	updated := contract("a")
+	updated.Status = larpc.ContractStatus_CLOSED
+	must(t, s.PutContract(updated))
+
+	got, err = s.GetContract("a")
+	must(t, err)
+	assertEqual(t, got, updated)
+
+	contracts, err = s.ListContracts()
+	must(t, err)
+	if len(contracts) != 2 {
+		t.Fatalf("listed %d contracts, want 2", len(contracts))
+	}
+	assertEqual(t, contracts[0], updated)
+	assertEqual(t, contracts[1], contract("b"))
+}
+
+func testDeleteContract(t *testing.T, s store.Store) {
+	must(t, s.PutContract(contract("a")))
+	must(t, s.PutRebalance(&larpc.Rebalance{ContractUuid: "a", Id: 1}))
+	must(t, s.PutPayment(payment("a", "aa", 1)))
+
+	must(t, s.DeleteContract("a"))
+
+	_, err := s.GetContract("a")
+	if !errors.Is(err, store.ErrContractNotFound) {
+		t.Fatalf("got error %v for a deleted contract, want %v",
+			err, store.ErrContractNotFound)
+	}
+
+	// the history of the contract is kept
+	rebalance, err := s.LatestRebalance("a")
+	must(t, err)
+	if rebalance == nil {
+		t.Fatal("rebalance of deleted contract is gone")
+	}
+	p, err := s.GetPayment("aa")
+	must(t, err)
+	if p == nil {
+		t.Fatal("payment of deleted contract is gone")
+	}
+
+	// deleting a missing contract is not an error
+	must(t, s.DeleteContract("a"))
+}
+
+func testRebalances(t *testing.T, s store.Store) {
+	rebalance, err := s.LatestRebalance("a")
+	must(t, err)
+	if rebalance != nil {
+		t.Fatalf("got rebalance %v of a contract never rebalanced", rebalance)
+	}
+
+	for _, id := range []int64{1, 3, 2} {
+		must(t, s.PutRebalance(&larpc.Rebalance{
+			ContractUuid: "a",
+			Id:           id,
+			AmountSat:    id * 10,
+		}))
+	}
+	must(t, s.PutRebalance(&larpc.Rebalance{ContractUuid: "b", Id: 7}))
+
+	rebalance, err = s.LatestRebalance("a")
+	must(t, err)
+	assertEqual(t, rebalance, &larpc.Rebalance{
+		ContractUuid: "a",
+		Id:           3,
+		AmountSat:    30,
+	})
+
+	// putting a rebalance again replaces it
+	completed := &larpc.Rebalance{
+		ContractUuid: "a",
+		Id:           3,
+		AmountSat:    30,
+		State:        larpc.RebalanceState_REBALANCE_COMPLETED,
+	}
+	must(t, s.PutRebalance(completed))
+
+	rebalance, err = s.LatestRebalance("a")
+	must(t, err)
+	assertEqual(t, rebalance, completed)
+}
+
+func testPayments(t *testing.T, s store.Store) {
+	p, err := s.GetPayment("aa")
+	must(t, err)
+	if p != nil {
+		t.Fatalf("got payment %v from an empty store", p)
+	}
+
+	must(t, s.PutPayment(payment("a", "aa", 3)))
+	must(t, s.PutPayment(payment("a", "ab", 1)))
+	must(t, s.PutPayment(payment("b", "ba", 2)))
+
+	p, err = s.GetPayment("ba")
+	must(t, err)
+	assertEqual(t, p, payment("b", "ba", 2))
+
+	// putting a payment again replaces it
+	settled := payment("a", "aa", 3)
+	settled.Settled = true
+	must(t, s.PutPayment(settled))
+
+	p, err = s.GetPayment("aa")
+	must(t, err)
+	assertEqual(t, p, settled)
+
+	payments, err := s.ListPayments("a")
+	must(t, err)
+	if len(payments) != 2 {
+		t.Fatalf("listed %d payments of contract, want 2", len(payments))
+	}
+	assertEqual(t, payments[0], payment("a", "ab", 1))
+	assertEqual(t, payments[1], settled)
+
+	payments, err = s.ListPayments("")
+	must(t, err)
+	if len(payments) != 3 {
+		t.Fatalf("listed %d payments, want 3", len(payments))
+	}
+	for i, hash := range []string{"ab", "ba", "aa"} {
+		if payments[i].PaymentHash != hash {
+			t.Fatalf("payment %d is %s, want %s", i, payments[i].PaymentHash, hash)
+		}
+	}
+
+	payments, err = s.ListPayments("c")
+	must(t, err)
+	if len(payments) != 0 {
+		t.Fatalf("listed %d payments of a contract without payments",
+			len(payments))
+	}
+}
+
+func testServerIdentity(t *testing.T, s store.Store) {
+	identity, err := s.GetServerIdentity()
+	must(t, err)
+	if identity != nil {
+		t.Fatalf("got identity %v from an empty store", identity)
+	}
+
+	pinned := &larpc.ServerIdentity{Pubkey: "02ab", PinnedAt: 1}
+	must(t, s.PutServerIdentity(pinned))
+
+	identity, err = s.GetServerIdentity()
+	must(t, err)
+	assertEqual(t, identity, pinned)
+
+	// a reset identity is stored without a pubkey
+	must(t, s.PutServerIdentity(&larpc.ServerIdentity{}))
+
+	identity, err = s.GetServerIdentity()
+	must(t, err)
+	assertEqual(t, identity, &larpc.ServerIdentity{})
+}
+
+func testCopies(t *testing.T, s store.Store) {
+	c := contract("a")
+	must(t, s.PutContract(c))
+
+	// neither the record we put nor the one we got is shared with the store
+	c.Status = larpc.ContractStatus_CLOSED
+	got, err := s.GetContract("a")
+	must(t, err)
+	got.StatusHistory[0].Reason = "modified"
+
+	got, err = s.GetContract("a")
+	must(t, err)
+	assertEqual(t, got, contract("a"))
+}
+
+func testUpdateCommits(t *testing.T, s store.Store) {
+	c := contract("a")
+	must(t, s.PutContract(c))
+
+	err := s.Update(func(tx store.Tx) error {
+		stored, err := tx.GetContract("a")
+		if err != nil {
+			return err
+		}
+
+		stored.NumRebalances++
+		if err := tx.PutContract(stored); err != nil {
+			return err
+		}
+		if err := tx.PutRebalance(&larpc.Rebalance{ContractUuid: "a", Id: 1}); err != nil {
+			return err
+		}
+
+		// reads see the writes of the transaction
+		rebalance, err := tx.LatestRebalance("a")
+		if err != nil {
+			return err
+		}
+		if rebalance == nil {
+			t.Error("rebalance put in transaction is not visible in it")
+		}
+
+		return tx.PutPayment(payment("a", "aa", 1))
+	})
+	must(t, err)
+
+	got, err := s.GetContract("a")
+	must(t, err)
+	if got.NumRebalances != 1 {
+		t.Fatalf("contract has %d rebalances, want 1", got.NumRebalances)
+	}
+	rebalance, err := s.LatestRebalance("a")
+	must(t, err)
+	if rebalance == nil {
+		t.Fatal("rebalance was not stored")
+	}
+	p, err := s.GetPayment("aa")
+	must(t, err)
+	if p == nil {
+		t.Fatal("payment was not stored")
+	}
+}
+
+func testUpdateRollsBack(t *testing.T, s store.Store) {
+	must(t, s.PutContract(contract("a")))
+	must(t, s.PutServerIdentity(&larpc.ServerIdentity{Pubkey: "02ab"}))
+
+	failure := errors.New("failure")
+	err := s.Update(func(tx store.Tx) error {
+		closed := contract("a")
+		closed.Status = larpc.ContractStatus_CLOSED
+		if err := tx.PutContract(closed); err != nil {
+			return err
+		}
+		if err := tx.PutContract(contract("b")); err != nil {
+			return err
+		}
+		if err := tx.DeleteContract("a"); err != nil {
+			return err
+		}
+		if err := tx.PutRebalance(&larpc.Rebalance{ContractUuid: "a", Id: 1}); err != nil {
+			return err
+		}
+		if err := tx.PutPayment(payment("a", "aa", 1)); err != nil {
+			return err
+		}
+		if err := tx.PutServerIdentity(&larpc.ServerIdentity{}); err != nil {
+			return err
+		}
+
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("update returned %v, want the error of the transaction", err)
+	}
+
+	got, err := s.GetContract("a")
+	must(t, err)
+	assertEqual(t, got, contract("a"))
+
+	_, err = s.GetContract("b")
+	if !errors.Is(err, store.ErrContractNotFound) {
+		t.Fatalf("contract of failed transaction was stored")
+	}
+	rebalance, err := s.LatestRebalance("a")
+	must(t, err)
+	if rebalance != nil {
+		t.Fatal("rebalance of failed transaction was stored")
+	}
+	p, err := s.GetPayment("aa")
+	must(t, err)
+	if p != nil {
+		t.Fatal("payment of failed transaction was stored")
+	}
+	identity, err := s.GetServerIdentity()
+	must(t, err)
+	assertEqual(t, identity, &larpc.ServerIdentity{Pubkey: "02ab"})
+}
@@ -1,7 +1,6 @@
 package util
 
 import (
-	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,18 +9,29 @@ import (
 	"strings"
 	"time"
 
-	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/credentials"
 	macaroon2 "gopkg.in/macaroon.v2"
 )
 
 var log = logrus.New()
 
-// ConnectToLnd connects to lnd-host using a tls.cert, admin.macaroon, and an net-address
-func ConnectToLnd(lndDir, lndHost, network string) (lnrpc.LightningClient, error) {
+// how long we wait between attempts to connect to lnd
+var lndBackoff = backoff.Config{
+	BaseDelay:  1 * time.Second,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   30 * time.Second,
+}
+
+// ConnectToLnd connects to lnd-host using a tls.cert, admin.macaroon, and an
+// net-address. It does not wait for lnd to be reachable, grpc keeps
+// reconnecting to it with an exponential backoff. The connection serves
+// both the main lnd rpc and its sub-servers, such as the router.
+func ConnectToLnd(lndDir, lndHost, network string) (*grpc.ClientConn, error) {
 	tlsPath := CleanAndExpandPath(fmt.Sprintf("%s/tls.cert", lndDir))
 	macaroonPath := CleanAndExpandPath(fmt.Sprintf("%s/data/chain/bitcoin/%s/admin.macaroon", lndDir, network))
 
@@ -30,26 +40,21 @@ func ConnectToLnd(lndDir, lndHost, network string) (lnrpc.LightningClient, error
 		return nil, fmt.Errorf("could not extract tls cert: %w", err)
 	}
 
-	macaroonBytes, err := ioutil.ReadFile(macaroonPath)
+	macaroon, err := LoadMacaroon(macaroonPath)
 	if err != nil {
-		return nil, fmt.Errorf("could not extract macaroon: %w", err)
-	}
-
-	macaroon := &macaroon2.Macaroon{}
-	if err = macaroon.UnmarshalBinary(macaroonBytes); err != nil {
-		return nil, fmt.Errorf("could not unmarshal macaroonBytes: %w", err)
+		return nil, err
 	}
 
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(tlsCreds),
-		grpc.WithBlock(),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           lndBackoff,
+			MinConnectTimeout: 10 * time.Second,
+		}),
 		grpc.WithPerRPCCredentials(macaroons.NewMacaroonCredential(macaroon)),
 	}
-	ctx := context.Background()
-	withTimeout, cancel := context.WithTimeout(ctx, 2*time.Second)
-	defer cancel()
 
-	conn, err := grpc.DialContext(withTimeout, lndHost, opts...)
+	conn, err := grpc.Dial(lndHost, opts...)
 	if err != nil {
 		log.WithFields(logrus.Fields{
 			"tlsPath":      tlsPath,
@@ -59,9 +64,22 @@ func ConnectToLnd(lndDir, lndHost, network string) (lnrpc.LightningClient, error
 		return nil, fmt.Errorf("could not dial lnd: %w", err)
 	}
 
-	lncli := lnrpc.NewLightningClient(conn)
+	return conn, nil
+}
+
+// LoadMacaroon reads a macaroon from file
+func LoadMacaroon(path string) (*macaroon2.Macaroon, error) {
+	macaroonBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract macaroon: %w", err)
+	}
+
+	macaroon := &macaroon2.Macaroon{}
+	if err = macaroon.UnmarshalBinary(macaroonBytes); err != nil {
+		return nil, fmt.Errorf("could not unmarshal macaroonBytes: %w", err)
+	}
 
-	return lncli, nil
+	return macaroon, nil
 }
 
 // CleanAndExpandPath expands environment variables and leading ~ in the